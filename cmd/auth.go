@@ -6,33 +6,17 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/HallyG/fingrab/internal/export"
 	"github.com/HallyG/fingrab/internal/log"
-	monzoexporter "github.com/HallyG/fingrab/internal/monzo/exporter"
 	"github.com/HallyG/fingrab/internal/oauth"
-	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/HallyG/fingrab/internal/store"
 )
 
-const (
-	envTokenSuffix        = "_TOKEN"
-	envClientIDSuffix     = "_CLIENT_ID"
-	envClientSecretSuffix = "_CLIENT_SECRET"
-)
-
-var bankConfigs = map[export.ExportType]oauth.Config{
-	monzoexporter.ExportTypeMonzo: {
-		AuthURL:              "https://auth.monzo.com",
-		TokenURL:             "https://api.monzo.com/oauth2/token",
-		WaitForApprovalInApp: true,
-	},
-	starlingexporter.ExportTypeStarling: {
-		AuthURL:  "https://oauth.starlingbank.com/oauth/authorize",
-		TokenURL: "https://api.starlingbank.com/oauth2/token",
-	},
-}
-
-func getAuthToken(ctx context.Context, exportType export.ExportType, token string) (string, error) {
+// getAuthToken returns the token from the flag or environment, or else gets one from the exporter's token source or
+// OAuth2 flow. The timeout is the command's, which requests made to get a token are also bound by.
+func getAuthToken(ctx context.Context, exportType export.ExportType, token string, timeout time.Duration) (string, error) {
 	logger := log.FromContext(ctx)
 
 	// Try token from CLI flag first
@@ -42,13 +26,32 @@ func getAuthToken(ctx context.Context, exportType export.ExportType, token strin
 	}
 
 	// Try token from environment variable
-	envVar := getEnvVarName(exportType, envTokenSuffix)
+	envVar := export.EnvVarName(exportType, export.EnvTokenSuffix)
 	authToken := os.Getenv(envVar)
 	if authToken != "" {
 		logger.DebugContext(ctx, "using auth token from environment variable", "env_var", envVar)
 		return authToken, nil
 	}
 
+	metadata, exists := export.Lookup(exportType)
+	if !exists {
+		return "", fmt.Errorf("unsupported bank type: %s (supported types: %v)", exportType, export.All())
+	}
+
+	// Some exporters get a token from other credentials, e.g. a user secret
+	if metadata.TokenSource != nil {
+		return metadata.TokenSource(ctx, export.Options{Timeout: timeout})
+	}
+
+	if metadata.OAuth == nil {
+		help := "set --token or " + envVar
+		if metadata.TokenHelp != "" {
+			help = metadata.TokenHelp + " and " + help
+		}
+
+		return "", fmt.Errorf("no auth token found, %s", help)
+	}
+
 	logger.WarnContext(ctx, "no auth token found, starting OAuth flow")
 	return startOAuth(ctx, exportType)
 }
@@ -56,15 +59,16 @@ func getAuthToken(ctx context.Context, exportType export.ExportType, token strin
 func startOAuth(ctx context.Context, exportType export.ExportType) (string, error) {
 	logger := log.FromContext(ctx)
 
-	config, exists := bankConfigs[exportType]
-	if !exists {
-		supportedTypes := export.All()
-		return "", fmt.Errorf("unsupported bank type: %s (supported types: %v)", exportType, supportedTypes)
+	metadata, _ := export.Lookup(exportType)
+	if metadata.OAuth == nil {
+		return "", fmt.Errorf("%s has no OAuth2 flow", metadata.DisplayName)
 	}
 
+	config := metadata.OAuth()
+
 	// Try oauth client credentials from environment variables
-	clientIDEnvVar := getEnvVarName(exportType, envClientIDSuffix)
-	clientSecretEnvVar := getEnvVarName(exportType, envClientSecretSuffix)
+	clientIDEnvVar := export.EnvVarName(exportType, export.EnvClientIDSuffix)
+	clientSecretEnvVar := export.EnvVarName(exportType, export.EnvClientSecretSuffix)
 
 	if clientID := strings.TrimSpace(os.Getenv(clientIDEnvVar)); clientID != "" {
 		logger.DebugContext(ctx, "using client ID from environment variable", slog.String("env.var", clientIDEnvVar))
@@ -77,9 +81,31 @@ func startOAuth(ctx context.Context, exportType export.ExportType) (string, erro
 	}
 
 	logger.DebugContext(ctx, "starting OAuth2 flow", "bank", exportType)
+
+	// Tokens are kept in the data directory, when there is one, so they can be refreshed on the next run. They're
+	// kept per client and environment, as a token from one isn't accepted by another
+	if dataDir, _ := rootCmd.PersistentFlags().GetString("data-dir"); config.RefreshToken && dataDir != "" {
+		s, err := store.New(dataDir)
+		if err != nil {
+			return "", fmt.Errorf("store: %w", err)
+		}
+
+		return oauth.StoredToken(ctx, &config, s, config.StoredTokenKey(metadata.Command), os.Stdin)
+	}
+
 	return oauth.Exchange(ctx, &config, os.Stdin)
 }
 
-func getEnvVarName(exportType export.ExportType, suffix string) string {
-	return strings.ToUpper(string(exportType)) + suffix
+// commandExample shows how to authenticate a command of the exporter, e.g. "transactions --start 2025-03-01".
+// OAuth2 is only shown for exporters that have a flow.
+func commandExample(exportType export.ExportType, args string) string {
+	metadata, _ := export.Lookup(exportType)
+	command := fmt.Sprintf("fingrab %s %s", metadata.Command, args)
+
+	example := fmt.Sprintf(cmdExample, command+" --token <api-token>", metadata.EnvPrefix, command)
+	if metadata.OAuth != nil {
+		example += "\n\n" + fmt.Sprintf(cmdExampleOAuth, metadata.EnvPrefix, metadata.EnvPrefix, command)
+	}
+
+	return example
 }
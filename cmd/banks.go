@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// unsupportedAnnotation marks the flags of a command which the exporter doesn't support, with the reason.
+const unsupportedAnnotation = "fingrab_unsupported"
+
+type banksOptions struct {
+	Output string
+}
+
+type bank struct {
+	Type         export.ExportType   `json:"type"`
+	Command      string              `json:"command"`
+	Name         string              `json:"name"`
+	EnvPrefix    string              `json:"envPrefix"`
+	OAuth        bool                `json:"oauth"`
+	Capabilities export.Capabilities `json:"capabilities"`
+}
+
+func newBanksCommand() *cobra.Command {
+	opts := &banksOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "banks",
+		Short: "List the banks and files fingrab can export from",
+		Long: `List the registered exporters, including plugins, with their command, the prefix of their environment variables,
+whether they can log in with OAuth2 and what they support.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runBanksCommand(cmd.OutOrStdout(), opts)
+		},
+		Example: `fingrab banks
+fingrab banks --output json`,
+	}
+
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s)", outputTable, outputJSON))
+
+	return cmd
+}
+
+func runBanksCommand(output io.Writer, opts *banksOptions) error {
+	if err := validateOutput(opts.Output, outputTable, outputJSON); err != nil {
+		return err
+	}
+
+	banks := lo.Map(export.All(), func(exportType export.ExportType, _ int) *bank {
+		metadata, _ := export.Lookup(exportType)
+
+		return &bank{
+			Type:         exportType,
+			Command:      metadata.Command,
+			Name:         metadata.DisplayName,
+			EnvPrefix:    metadata.EnvPrefix,
+			OAuth:        metadata.OAuth != nil,
+			Capabilities: metadata.Capabilities,
+		}
+	})
+
+	// Export types aren't consistently cased, so sort by the command that's typed
+	slices.SortFunc(banks, func(a, b *bank) int {
+		return strings.Compare(a.Command, b.Command)
+	})
+
+	if opts.Output == outputJSON {
+		return writeJSON(output, banks)
+	}
+
+	headers := []string{"COMMAND", "NAME", "ENV PREFIX", "OAUTH", "CAPABILITIES"}
+	rows := lo.Map(banks, func(bank *bank, _ int) []string {
+		envPrefix := bank.EnvPrefix
+		if bank.Capabilities.File {
+			envPrefix = ""
+		}
+
+		return []string{
+			bank.Command,
+			bank.Name,
+			envPrefix,
+			fmt.Sprintf("%t", bank.OAuth),
+			strings.Join(bank.Capabilities.Names(), ", "),
+		}
+	})
+
+	return writeTable(output, headers, rows)
+}
+
+// markUnsupported hides the command's flag when the exporter doesn't support it, and has checkUnsupported reject it
+// when it's set anyway. The reason completes "--flag is not supported, ...".
+func markUnsupported(cmd *cobra.Command, flag string, supported bool, reason string) {
+	if supported {
+		return
+	}
+
+	_ = cmd.Flags().SetAnnotation(flag, unsupportedAnnotation, []string{reason})
+	_ = cmd.Flags().MarkHidden(flag)
+}
+
+// checkUnsupported returns an error when a flag marked unsupported has been set.
+func checkUnsupported(cmd *cobra.Command) error {
+	var err error
+	cmd.Flags().Visit(func(flag *pflag.Flag) {
+		if reason, ok := flag.Annotations[unsupportedAnnotation]; ok && err == nil {
+			err = fmt.Errorf("--%s is not supported, %s", flag.Name, strings.Join(reason, " "))
+		}
+	})
+
+	return err
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/HallyG/fingrab/internal/csvfile"
+	csvexporter "github.com/HallyG/fingrab/internal/csvfile/exporter"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/spf13/cobra"
+)
+
+type csvfileTransactionsOptions struct {
+	Input     string
+	Profile   string
+	StartDate string
+	EndDate   string
+	Format    string
+}
+
+func newCSVFileTransactionsCommand() *cobra.Command {
+	opts := &csvfileTransactionsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "transactions",
+		Short: "Convert transactions from a bank statement CSV",
+		Long: `Convert the transactions in a statement CSV to another format, using a profile that maps the bank's columns,
+date layout and amount sign convention to transactions. Use a built-in profile by name or a custom profile
+by the path of its JSON file.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runCSVFileTransactions(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("csvfile: %w", err)
+			}
+
+			return nil
+		},
+		Example: `# Convert an Amex statement to YNAB
+fingrab csvfile transactions --input statement.csv --profile amex --format ynab
+
+# Convert a single month of a statement using a custom profile
+fingrab csvfile transactions --input statement.csv --profile my-bank.json --start 2025-03-01 --end 2025-03-31`,
+	}
+
+	cmd.Flags().StringVar(&opts.Input, "input", "", "Statement CSV to read")
+	cmd.Flags().StringVar(&opts.Profile, "profile", "", fmt.Sprintf("Profile name (options: %s) or path to a custom profile JSON file", strings.Join(csvfile.Profiles(), ", ")))
+	cmd.Flags().StringVar(&opts.StartDate, "start", "", "Start date (YYYY-MM-DD), defaults to the start of the statement")
+	cmd.Flags().StringVar(&opts.EndDate, "end", "", "End date (YYYY-MM-DD), inclusive, defaults to the end of the statement")
+	cmd.Flags().StringVar(&opts.Format, "format", string(format.FormatTypeMoneyDance), formatUsage())
+
+	_ = cmd.MarkFlagRequired("input")
+	_ = cmd.MarkFlagRequired("profile")
+
+	return cmd
+}
+
+func runCSVFileTransactions(ctx context.Context, output io.Writer, opts *csvfileTransactionsOptions) error {
+	startDate, endDate, err := parseOptionalDateRange(opts.StartDate, opts.EndDate)
+	if err != nil {
+		return err
+	}
+
+	return runFileTransactions(ctx, output, csvexporter.ExportTypeCSVFile, opts.Format, export.TransactionOptions{
+		StartDate: startDate,
+		EndDate:   endDate,
+		Options: export.Options{
+			Input:   opts.Input,
+			Profile: opts.Profile,
+		},
+	})
+}
+
+func newCSVFileProfilesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "profiles",
+		Short: "List the built-in statement CSV profiles",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			for _, name := range csvfile.Profiles() {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), name)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/report"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+type declinesOptions struct {
+	StartDate string
+	EndDate   string
+	AuthToken string
+	Timeout   time.Duration
+	AccountID string
+	DataDir   string
+	Output    string
+}
+
+func newDeclinesCommand(exporterType export.ExportType) *cobra.Command {
+	opts := &declinesOptions{}
+	metadata, _ := export.Lookup(exporterType)
+	name := metadata.DisplayName
+
+	cmd := &cobra.Command{
+		Use:   "declines",
+		Short: "Summarise declined transactions from " + name,
+		Long:  fmt.Sprintf("Summarise declined %s transactions by merchant and decline reason for the specified date range.", name),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.DataDir = getDataDir(cmd)
+			err := runDeclinesCommand(cmd.Context(), cmd.OutOrStdout(), opts, exporterType)
+			if err != nil {
+				return fmt.Errorf("%s: %w", metadata.Command, err)
+			}
+
+			return nil
+		},
+		Example: fmt.Sprintf("fingrab %s declines --start 2025-03-01 --end 2025-03-31\nfingrab %s declines --start 2025-03-01 --output json", metadata.Command, metadata.Command),
+	}
+
+	cmd.Flags().StringVar(&opts.StartDate, "start", "", "Start date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&opts.EndDate, "end", "", "End date (YYYY-MM-DD)")
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID (Monzo also accepts an account type, e.g. joint, or description; Starling a name, type or currency, e.g. EUR, or all)")
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s)", outputTable, outputJSON))
+
+	_ = cmd.MarkFlagRequired("start")
+
+	return cmd
+}
+
+func runDeclinesCommand(ctx context.Context, output io.Writer, opts *declinesOptions, exportType export.ExportType) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(exportType)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if err := validateOutput(opts.Output, outputTable, outputJSON); err != nil {
+		return err
+	}
+
+	startDate, endDate, err := parseDateRange(opts.StartDate, opts.EndDate)
+	if err != nil {
+		return err
+	}
+
+	authToken, err := getAuthToken(ctx, exportType, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	transactions, err := export.Transactions(ctx, exportType, export.TransactionOptions{
+		StartDate: startDate,
+		EndDate:   endDate,
+		AccountID: opts.AccountID,
+		Audit:     true,
+		Options: export.Options{
+			AuthToken: authToken,
+			Timeout:   opts.Timeout,
+			DataDir:   opts.DataDir,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	declines := report.Declines(transactions)
+
+	if opts.Output == outputJSON {
+		return writeJSON(output, declines)
+	}
+
+	return writeTable(output,
+		[]string{"MERCHANT", "REASON", "COUNT", "TOTAL", "CURRENCY", "FIRST SEEN", "LAST SEEN"},
+		lo.Map(declines, func(decline *report.DeclineSummary, _ int) []string {
+			return []string{
+				decline.Merchant,
+				decline.Reason,
+				strconv.Itoa(decline.Count),
+				decline.Total.String(),
+				decline.Total.Currency,
+				decline.FirstSeen.Format(timeFormat),
+				decline.LastSeen.Format(timeFormat),
+			}
+		}),
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,35 +14,38 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const outputID = "id"
+
 type exportAccountsOptions struct {
-	AuthToken string
-	Timeout   time.Duration
+	AuthToken     string
+	Timeout       time.Duration
+	DataDir       string
+	Output        string
+	Type          string
+	IncludeClosed bool
 }
 
 func newAccountsCommand(exporterType export.ExportType) *cobra.Command {
 	opts := &exportAccountsOptions{}
-	name := string(exporterType)
-	upperName := strings.ToUpper(name)
-	lowerName := strings.ToLower(name)
+	metadata, _ := export.Lookup(exporterType)
+	name := metadata.DisplayName
 
 	cmd := &cobra.Command{
 		Use:   "accounts",
 		Short: fmt.Sprintf("List accounts from %s", name),
-		Long:  fmt.Sprintf("Fetch and display all available %s account IDs for the authenticated user", name),
+		Long:  fmt.Sprintf("Fetch and display all available %s accounts for the authenticated user", name),
 		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.DataDir = getDataDir(cmd)
 			return runAccountsCommand(cmd.Context(), cmd.OutOrStdout(), opts, exporterType)
 		},
-		Example: fmt.Sprintf(cmdExample,
-			fmt.Sprintf("fingrab %s accounts --token <api-token>", lowerName),
-			upperName,
-			fmt.Sprintf("fingrab %s accounts", lowerName),
-			upperName, upperName,
-			fmt.Sprintf("fingrab %s accounts", lowerName),
-		),
+		Example: commandExample(exporterType, "accounts"),
 	}
 
 	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
 	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s, %s)", outputTable, outputJSON, outputID))
+	cmd.Flags().StringVar(&opts.Type, "type", "", "Only list accounts of this type (e.g. joint)")
+	cmd.Flags().BoolVar(&opts.IncludeClosed, "include-closed", false, "Include closed accounts")
 
 	return cmd
 }
@@ -52,15 +56,22 @@ func runAccountsCommand(ctx context.Context, output io.Writer, opts *exportAccou
 	)
 	ctx = log.WithContext(ctx, logger)
 
-	authToken, err := getAuthToken(ctx, exportType, opts.AuthToken)
+	if err := validateOutput(opts.Output, outputTable, outputJSON, outputID); err != nil {
+		return err
+	}
+
+	authToken, err := getAuthToken(ctx, exportType, opts.AuthToken, opts.Timeout)
 	if err != nil {
 		return fmt.Errorf("%s: authentication failed: %w", strings.ToLower(string(exportType)), err)
 	}
 
 	exportOpts := export.AccountOptions{
+		Type:          opts.Type,
+		IncludeClosed: opts.IncludeClosed,
 		Options: export.Options{
 			AuthToken: authToken,
 			Timeout:   opts.Timeout,
+			DataDir:   opts.DataDir,
 		},
 	}
 
@@ -69,9 +80,51 @@ func runAccountsCommand(ctx context.Context, output io.Writer, opts *exportAccou
 		return fmt.Errorf("export: %w", err)
 	}
 
-	for _, account := range accounts {
-		_, _ = fmt.Fprintln(output, account.ID)
-	}
+	switch opts.Output {
+	case outputJSON:
+		return writeJSON(output, accounts)
+	case outputID:
+		for _, account := range accounts {
+			_, _ = fmt.Fprintln(output, account.ID)
+		}
 
-	return nil
+		return nil
+	default:
+		rows := make([][]string, 0, len(accounts))
+		for _, account := range accounts {
+			rows = append(rows, []string{
+				account.ID,
+				account.Name,
+				account.Type,
+				account.HolderType,
+				account.Currency,
+				account.SortCode,
+				account.AccountNumber,
+				account.IBAN,
+				account.BIC,
+				strings.Join(account.Owners, ", "),
+				strconv.FormatBool(account.Closed),
+				account.CreatedAt.Format(timeFormat),
+				"",
+			})
+
+			// Spaces are listed beneath their account
+			for _, space := range account.Spaces {
+				rows = append(rows, []string{
+					space.ID,
+					account.Name + " / " + space.Name,
+					space.Type,
+					"",
+					space.Balance.Currency,
+					"", "", "", "", "", "", "",
+					space.Balance.String(),
+				})
+			}
+		}
+
+		return writeTable(output,
+			[]string{"ID", "NAME", "TYPE", "HOLDER", "CURRENCY", "SORT CODE", "ACCOUNT NUMBER", "IBAN", "BIC", "OWNERS", "CLOSED", "CREATED", "BALANCE"},
+			rows,
+		)
+	}
 }
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/HallyG/fingrab/internal/domain"
 	"github.com/HallyG/fingrab/internal/export"
 	"github.com/HallyG/fingrab/internal/format"
 	"github.com/HallyG/fingrab/internal/log"
@@ -22,51 +23,63 @@ const (
 )
 
 type exportTransactionOptions struct {
-	StartDate string
-	EndDate   string
-	AuthToken string
-	Timeout   time.Duration
-	AccountID string
-	Format    string
+	StartDate      string
+	EndDate        string
+	AuthToken      string
+	Timeout        time.Duration
+	DataDir        string
+	AccountID      string
+	Space          string
+	Format         string
+	Audit          bool
+	ClosingBalance bool
 }
 
 func newTransactionsCommand(exporterType export.ExportType) *cobra.Command {
 	opts := &exportTransactionOptions{}
-	name := string(exporterType)
-	lowerName := strings.ToLower(name)
-	upperName := strings.ToUpper(name)
+	metadata, _ := export.Lookup(exporterType)
+	name := metadata.DisplayName
 
 	cmd := &cobra.Command{
 		Use:   "transactions",
 		Short: "Export transactions from " + name,
 		Long:  fmt.Sprintf("Export banking transactions from %s for the specified date range.", name),
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			err := runExportTransactions(cmd.Context(), cmd.OutOrStdout(), opts, exporterType)
+			if err := checkUnsupported(cmd); err != nil {
+				return fmt.Errorf("%s: %w", metadata.Command, err)
+			}
+
+			opts.DataDir = getDataDir(cmd)
+
+			// Audit output is for investigation, so default to the format that shows status and decline reason
+			if opts.Audit && !cmd.Flags().Changed("format") {
+				opts.Format = string(format.FormatTypeDetailed)
+			}
+
+			err := runExportTransactions(cmd.Context(), cmd.OutOrStdout(), cmd.ErrOrStderr(), opts, exporterType)
 			if err != nil {
-				return fmt.Errorf("%s: %w", lowerName, err)
+				return fmt.Errorf("%s: %w", metadata.Command, err)
 			}
 
 			return nil
 		},
-		Example: fmt.Sprintf(cmdExample,
-			fmt.Sprintf("fingrab %s transactions --token <api-token> --start 2025-03-01 --end 2025-03-31", lowerName),
-			upperName,
-			fmt.Sprintf("fingrab %s transactions --start 2025-03-01 --end 2025-03-31", lowerName),
-			upperName, upperName,
-			fmt.Sprintf("fingrab %s transactions --start 2025-03-01 --end 2025-03-31", lowerName),
-		),
+		Example: commandExample(exporterType, "transactions --start 2025-03-01 --end 2025-03-31"),
 	}
 
-	allFormats := strings.Join(lo.Map(format.All(), func(item format.FormatType, index int) string {
-		return fmt.Sprintf("%v", item)
-	}), ", ")
-
 	cmd.Flags().StringVar(&opts.StartDate, "start", "", "Start date (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&opts.EndDate, "end", "", "End date (YYYY-MM-DD)")
 	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
 	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
-	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID")
-	cmd.Flags().StringVar(&opts.Format, "format", string(format.FormatTypeMoneyDance), fmt.Sprintf("Output format (options: %s,)", allFormats))
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID (Monzo also accepts an account type, e.g. joint, or description; Starling a name, type or currency, e.g. EUR, or all)")
+	cmd.Flags().StringVar(&opts.Space, "space", "", "Export a space's transactions instead of the account's (Starling savings goal or spending space ID or name)")
+	cmd.Flags().StringVar(&opts.Format, "format", string(format.FormatTypeMoneyDance), formatUsage())
+	cmd.Flags().BoolVar(&opts.Audit, "audit", false, fmt.Sprintf("Include declined and reversed transactions, tagged with their status (defaults --format to %s)", format.FormatTypeDetailed))
+
+	cmd.Flags().BoolVar(&opts.ClosingBalance, "closing-balance", false, "Print the account's balance at the end date to stderr, for reconciliation")
+
+	markUnsupported(cmd, "space", metadata.Capabilities.Spaces, name+" doesn't export spaces")
+	markUnsupported(cmd, "audit", metadata.Capabilities.Declines, name+" doesn't export declined transactions")
+	markUnsupported(cmd, "closing-balance", metadata.Capabilities.Balances, name+" doesn't report balances")
 
 	_ = cmd.MarkFlagRequired("start")
 
@@ -77,44 +90,61 @@ func parseDate(str string) (time.Time, error) {
 	return time.Parse(timeFormat, str)
 }
 
-func runExportTransactions(ctx context.Context, output io.Writer, opts *exportTransactionOptions, exportType export.ExportType) error {
-	logger := log.FromContext(ctx).With(
-		slog.String("bank", string(exportType)),
-	)
-	ctx = log.WithContext(ctx, logger)
+// parseDateRange parses and validates the start and end dates (YYYY-MM-DD) of an export.
+// An empty end date defaults to tomorrow.
+// formatUsage is the help of a --format flag, listing the formats.
+func formatUsage() string {
+	allFormats := lo.Map(format.All(), func(item format.FormatType, _ int) string {
+		return string(item)
+	})
+
+	return fmt.Sprintf("Output format (options: %s)", strings.Join(allFormats, ", "))
+}
 
-	startDate, err := parseDate(opts.StartDate)
+func parseDateRange(start string, end string) (time.Time, time.Time, error) {
+	startDate, err := parseDate(start)
 	if err != nil {
-		return fmt.Errorf("start date: %w", err)
+		return time.Time{}, time.Time{}, fmt.Errorf("start date: %w", err)
 	}
 
 	now := time.Now().Truncate(24 * time.Hour)
 	endDate := now.Add(24 * time.Hour)
 
-	if opts.EndDate != "" {
-		endDate, err = parseDate(opts.EndDate)
+	if end != "" {
+		endDate, err = parseDate(end)
 		if err != nil {
-			return fmt.Errorf("end date: %w", err)
+			return time.Time{}, time.Time{}, fmt.Errorf("end date: %w", err)
 		}
 	}
 
 	// TODO: handle the case where we generate the start date at mightnight, but now is less than that
 	if endDate.Before(startDate) {
-		return errors.New("end date must be after start date")
+		return time.Time{}, time.Time{}, errors.New("end date must be after start date")
 	}
 
 	if startDate.After(now) {
-		return fmt.Errorf("start date %q cannot be in the future", startDate.Format(timeFormat))
-	}
-	if endDate.Before(startDate) {
-		return fmt.Errorf("end date %q must be after start date %q", endDate.Format(timeFormat), startDate.Format(timeFormat))
+		return time.Time{}, time.Time{}, fmt.Errorf("start date %q cannot be in the future", startDate.Format(timeFormat))
 	}
 
 	if endDate.After(now.Add(24 * time.Hour)) {
-		return errors.New("end date cannot be more than 1 day in the future")
+		return time.Time{}, time.Time{}, errors.New("end date cannot be more than 1 day in the future")
 	}
 
-	authToken, err := getAuthToken(ctx, exportType, opts.AuthToken)
+	return startDate, endDate, nil
+}
+
+func runExportTransactions(ctx context.Context, output io.Writer, errOutput io.Writer, opts *exportTransactionOptions, exportType export.ExportType) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(exportType)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	startDate, endDate, err := parseDateRange(opts.StartDate, opts.EndDate)
+	if err != nil {
+		return err
+	}
+
+	authToken, err := getAuthToken(ctx, exportType, opts.AuthToken, opts.Timeout)
 	if err != nil {
 		return err
 	}
@@ -123,9 +153,12 @@ func runExportTransactions(ctx context.Context, output io.Writer, opts *exportTr
 		StartDate: startDate,
 		EndDate:   endDate,
 		AccountID: opts.AccountID,
+		Space:     opts.Space,
+		Audit:     opts.Audit,
 		Options: export.Options{
 			AuthToken: authToken,
 			Timeout:   opts.Timeout,
+			DataDir:   opts.DataDir,
 		},
 	}
 
@@ -139,9 +172,23 @@ func runExportTransactions(ctx context.Context, output io.Writer, opts *exportTr
 		return fmt.Errorf("export: %w", err)
 	}
 
+	// The balance is fetched before anything is written, so a failure doesn't leave a partial export behind
+	var balance domain.Money
+	if opts.ClosingBalance {
+		balance, err = export.ClosingBalance(ctx, exportType, exportOpts)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := format.WriteCollection(formatter, transactions); err != nil {
 		return err
 	}
 
+	if opts.ClosingBalance {
+		// Written separately from the transactions so the formatted output stays importable
+		_, _ = fmt.Fprintf(errOutput, "closing balance on %s: %s %s\n", endDate.Format(timeFormat), balance.String(), balance.Currency)
+	}
+
 	return nil
 }
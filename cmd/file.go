@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/HallyG/fingrab/internal/log"
+)
+
+// parseOptionalDateRange parses the start and end dates (YYYY-MM-DD) of a file export, either of which may be empty
+// to include the whole file. The end date is inclusive, so the range returned ends at the start of the following day.
+func parseOptionalDateRange(start string, end string) (time.Time, time.Time, error) {
+	var startDate, endDate time.Time
+	var err error
+	if start != "" {
+		startDate, err = parseDate(start)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("start date: %w", err)
+		}
+	}
+
+	if end != "" {
+		endDate, err = parseDate(end)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("end date: %w", err)
+		}
+
+		endDate = endDate.AddDate(0, 0, 1)
+	}
+
+	return startDate, endDate, nil
+}
+
+// runFileTransactions writes the transactions read from a downloaded file, e.g. a statement CSV or OFX file.
+func runFileTransactions(ctx context.Context, output io.Writer, exportType export.ExportType, formatType string, opts export.TransactionOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(exportType)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	formatter, err := format.NewFormatter(format.FormatType(formatType), output)
+	if err != nil {
+		return fmt.Errorf("formatter: %w", err)
+	}
+
+	// A file needs no auth token or date range, so skip export.Transactions' validation of both
+	exporter, err := export.NewExporter(exportType, opts.Options)
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	transactions, err := exporter.ExportTransactions(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	return format.WriteCollection(formatter, transactions)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/export"
+	gocardlessexporter "github.com/HallyG/fingrab/internal/gocardless/exporter"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+type gocardlessRequisitionsOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	Output    string
+}
+
+func newGoCardlessRequisitionsCommand() *cobra.Command {
+	opts := &gocardlessRequisitionsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "requisitions",
+		Short: "List GoCardless requisitions and their agreements",
+		Long: `List every requisition, with its institution, status, linked accounts and when its agreement's access expires.
+Only the accounts of linked requisitions are exported.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runGoCardlessRequisitions(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("gocardless: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab gocardless requisitions
+fingrab gocardless requisitions --output json`,
+	}
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s)", outputTable, outputJSON))
+
+	return cmd
+}
+
+func runGoCardlessRequisitions(ctx context.Context, output io.Writer, opts *gocardlessRequisitionsOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(gocardlessexporter.ExportTypeGoCardless)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if err := validateOutput(opts.Output, outputTable, outputJSON); err != nil {
+		return err
+	}
+
+	authToken, err := getAuthToken(ctx, gocardlessexporter.ExportTypeGoCardless, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := gocardlessexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	links, err := exporter.ExportLinks(ctx)
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == outputJSON {
+		return writeJSON(output, links)
+	}
+
+	headers := []string{"ID", "INSTITUTION", "STATUS", "HISTORY DAYS", "ACCESS EXPIRES", "ACCOUNTS"}
+	rows := lo.Map(links, func(link *gocardlessexporter.Link, _ int) []string {
+		historyDays := ""
+		if link.MaxHistoricalDays > 0 {
+			historyDays = strconv.Itoa(link.MaxHistoricalDays)
+		}
+
+		accessExpires := ""
+		if !link.AccessExpiresAt.IsZero() {
+			accessExpires = link.AccessExpiresAt.Format(timeFormat)
+		}
+
+		return []string{
+			link.RequisitionID,
+			link.InstitutionID,
+			link.Status,
+			historyDays,
+			accessExpires,
+			strings.Join(link.Accounts, ", "),
+		}
+	})
+
+	return writeTable(output, headers, rows)
+}
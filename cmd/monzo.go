@@ -1,13 +1,100 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	monzoexporter "github.com/HallyG/fingrab/internal/monzo/exporter"
 	"github.com/spf13/cobra"
 )
 
-var (
-	monzoCmd = &cobra.Command{
-		Use:   "monzo",
-		Short: "Monzo bank commands",
-		Long:  "Commands for interacting with the Monzo API",
+type monzoBackfillOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	AccountID string
+	DataDir   string
+}
+
+func newMonzoBackfillCommand() *cobra.Command {
+	opts := &monzoBackfillOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Store your full Monzo transaction history locally",
+		Long: `Monzo only returns transactions older than 90 days within 5 minutes of authenticating.
+Backfill logs in with OAuth2 and immediately walks the account's history, from the account's creation date,
+in 90 day windows and stores it locally. Later transaction exports fall back to the stored history
+when Monzo requires verification.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.DataDir = getDataDir(cmd)
+			err := runMonzoBackfill(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("monzo: %w", err)
+			}
+
+			return nil
+		},
+		Example: `# Using OAuth2
+export MONZO_CLIENT_ID=<client-id>
+export MONZO_CLIENT_SECRET=<client-secret>
+fingrab monzo backfill
+
+# Using a token issued less than 5 minutes ago
+fingrab monzo backfill --token <api-token>`,
 	}
-)
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token (must have been issued less than 5 minutes ago)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID")
+
+	return cmd
+}
+
+func runMonzoBackfill(ctx context.Context, output io.Writer, opts *monzoBackfillOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(monzoexporter.ExportTypeMonzo)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if opts.DataDir == "" {
+		return errors.New("data directory is required")
+	}
+
+	// The history window is only open immediately after authenticating, so a previously issued token from the
+	// environment is ignored in favour of a fresh OAuth2 login.
+	authToken := opts.AuthToken
+	if authToken != "" {
+		logger.WarnContext(ctx, "using auth token from cli flag, backfill will fail if it was issued more than 5 minutes ago")
+	} else {
+		token, err := startOAuth(ctx, monzoexporter.ExportTypeMonzo)
+		if err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+
+		authToken = token
+	}
+
+	exporter, err := monzoexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+		DataDir:   opts.DataDir,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	count, err := exporter.Backfill(ctx, opts.AccountID)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(output, "stored %d transactions in %s\n", count, opts.DataDir)
+
+	return nil
+}
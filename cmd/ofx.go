@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/HallyG/fingrab/internal/log"
+	ofxexporter "github.com/HallyG/fingrab/internal/ofx/exporter"
+	"github.com/spf13/cobra"
+)
+
+type ofxTransactionsOptions struct {
+	Input     string
+	AccountID string
+	StartDate string
+	EndDate   string
+	Format    string
+}
+
+func newOFXTransactionsCommand() *cobra.Command {
+	opts := &ofxTransactionsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "transactions",
+		Short: "Convert transactions from an OFX or QFX file",
+		Long: `Convert the transactions in an OFX or QFX file to another format. Each transaction keeps the bank's FITID as its
+ID and the statement's currency, so the output can be merged with other exports.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runOFXTransactions(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("ofx: %w", err)
+			}
+
+			return nil
+		},
+		Example: `# Convert an OFX download to YNAB
+fingrab ofx transactions --input statement.ofx --format ynab
+
+# Convert a single month of one account in a QFX file
+fingrab ofx transactions --input statement.qfx --account 12345678 --start 2025-03-01 --end 2025-03-31`,
+	}
+
+	cmd.Flags().StringVar(&opts.Input, "input", "", "OFX or QFX file to read")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID of the statement to convert, defaults to every statement in the file")
+	cmd.Flags().StringVar(&opts.StartDate, "start", "", "Start date (YYYY-MM-DD), defaults to the start of the file")
+	cmd.Flags().StringVar(&opts.EndDate, "end", "", "End date (YYYY-MM-DD), inclusive, defaults to the end of the file")
+	cmd.Flags().StringVar(&opts.Format, "format", string(format.FormatTypeMoneyDance), formatUsage())
+
+	_ = cmd.MarkFlagRequired("input")
+
+	return cmd
+}
+
+func runOFXTransactions(ctx context.Context, output io.Writer, opts *ofxTransactionsOptions) error {
+	startDate, endDate, err := parseOptionalDateRange(opts.StartDate, opts.EndDate)
+	if err != nil {
+		return err
+	}
+
+	return runFileTransactions(ctx, output, ofxexporter.ExportTypeOFX, opts.Format, export.TransactionOptions{
+		AccountID: opts.AccountID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Options: export.Options{
+			Input: opts.Input,
+		},
+	})
+}
+
+type ofxAccountsOptions struct {
+	Input  string
+	Output string
+}
+
+func newOFXAccountsCommand() *cobra.Command {
+	opts := &ofxAccountsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "accounts",
+		Short: "List the accounts in an OFX or QFX file",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runOFXAccounts(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("ofx: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab ofx accounts --input statement.ofx`,
+	}
+
+	cmd.Flags().StringVar(&opts.Input, "input", "", "OFX or QFX file to read")
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s, %s)", outputTable, outputJSON, outputID))
+
+	_ = cmd.MarkFlagRequired("input")
+
+	return cmd
+}
+
+func runOFXAccounts(ctx context.Context, output io.Writer, opts *ofxAccountsOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(ofxexporter.ExportTypeOFX)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if err := validateOutput(opts.Output, outputTable, outputJSON, outputID); err != nil {
+		return err
+	}
+
+	exporter, err := export.NewExporter(ofxexporter.ExportTypeOFX, export.Options{Input: opts.Input})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	accounts, err := exporter.ExportAccounts(ctx, export.AccountOptions{})
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	switch opts.Output {
+	case outputJSON:
+		return writeJSON(output, accounts)
+	case outputID:
+		for _, account := range accounts {
+			_, _ = fmt.Fprintln(output, account.ID)
+		}
+
+		return nil
+	default:
+		rows := make([][]string, 0, len(accounts))
+		for _, account := range accounts {
+			rows = append(rows, []string{
+				account.ID,
+				account.Name,
+				account.Type,
+				account.Currency,
+				account.SortCode,
+			})
+		}
+
+		return writeTable(output, []string{"ID", "NAME", "TYPE", "CURRENCY", "SORT CODE"}, rows)
+	}
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/HallyG/fingrab/internal/log"
+	plaidexporter "github.com/HallyG/fingrab/internal/plaid/exporter"
+	"github.com/spf13/cobra"
+)
+
+type plaidSyncOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	DataDir   string
+	Format    string
+	Modified  string
+	Removed   string
+}
+
+func newPlaidSyncCommand() *cobra.Command {
+	opts := &plaidSyncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Export Plaid transactions added, modified or removed since the last sync",
+		Long: `Export only the changes to the item's transactions since the previous sync, using Plaid's sync cursor, which is
+stored in the data directory once the transactions have been written. The first sync returns the item's whole
+history. New transactions are written to stdout. Transactions returned by an earlier sync which have since been
+modified (e.g. a pending amount changed) are written separately to --modified, and the IDs of removed ones
+(e.g. pending transactions which have posted) to --removed, so they can be updated rather than imported twice.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.DataDir = getDataDir(cmd)
+			err := runPlaidSync(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("plaid: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab plaid sync --format detailed --modified modified.csv --removed removed.txt >> new.csv`,
+	}
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "Item access token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.Format, "format", string(format.FormatTypeDetailed), formatUsage())
+	cmd.Flags().StringVar(&opts.Modified, "modified", "", "File to write modified transactions to (required when any were modified)")
+	cmd.Flags().StringVar(&opts.Removed, "removed", "", "File to write the IDs of removed transactions to, one per line (required when any were removed)")
+
+	return cmd
+}
+
+func runPlaidSync(ctx context.Context, output io.Writer, opts *plaidSyncOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(plaidexporter.ExportTypePlaid)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if opts.DataDir == "" {
+		return errors.New("data directory is required")
+	}
+
+	formatter, err := format.NewFormatter(format.FormatType(opts.Format), output)
+	if err != nil {
+		return fmt.Errorf("formatter: %w", err)
+	}
+
+	authToken, err := getAuthToken(ctx, plaidexporter.ExportTypePlaid, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := plaidexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+		DataDir:   opts.DataDir,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	result, err := exporter.Sync(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Committing the cursor would lose changes that aren't written, so refuse before anything is written
+	if len(result.Modified) > 0 && opts.Modified == "" {
+		return fmt.Errorf("%d transactions were modified since the last sync, use --modified to write them", len(result.Modified))
+	}
+
+	if len(result.Removed) > 0 && opts.Removed == "" {
+		return fmt.Errorf("%d transactions were removed since the last sync, use --removed to write them", len(result.Removed))
+	}
+
+	// Every output is opened before anything is written, so a failure doesn't leave the new transactions written alone
+	var modifiedFile, removedFile *os.File
+	var modifiedFormatter format.Formatter
+	if opts.Modified != "" {
+		modifiedFile, err = os.Create(opts.Modified)
+		if err != nil {
+			return fmt.Errorf("create modified output: %w", err)
+		}
+		defer modifiedFile.Close()
+
+		modifiedFormatter, err = format.NewFormatter(format.FormatType(opts.Format), modifiedFile)
+		if err != nil {
+			return fmt.Errorf("formatter: %w", err)
+		}
+	}
+
+	if opts.Removed != "" {
+		removedFile, err = os.OpenFile(opts.Removed, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("create removed output: %w", err)
+		}
+		defer removedFile.Close()
+	}
+
+	if err := format.WriteCollection(formatter, result.New); err != nil {
+		return err
+	}
+
+	if modifiedFormatter != nil {
+		if err := format.WriteCollection(modifiedFormatter, result.Modified); err != nil {
+			return err
+		}
+
+		if err := modifiedFile.Close(); err != nil {
+			return fmt.Errorf("close modified output: %w", err)
+		}
+	}
+
+	if removedFile != nil {
+		for _, id := range result.Removed {
+			if _, err := fmt.Fprintln(removedFile, id); err != nil {
+				return fmt.Errorf("write removed output: %w", err)
+			}
+		}
+
+		if err := removedFile.Close(); err != nil {
+			return fmt.Errorf("close removed output: %w", err)
+		}
+	}
+
+	// Only advance the cursor once everything has been written, so a failed sync is fetched again
+	return exporter.CommitSync(result)
+}
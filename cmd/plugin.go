@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"context"
+	"slices"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/plugin"
+	pluginexporter "github.com/HallyG/fingrab/internal/plugin/exporter"
+)
+
+// usesPlugins reports whether the arguments could run a plugin's command or list the plugins, so plugins are only
+// discovered, and run to describe themselves, when they could be needed. Arguments which don't resolve to a built-in
+// command, e.g. a plugin's name or the root's help, and the banks command need them.
+func usesPlugins(args []string) bool {
+	found, _, err := rootCmd.Find(args)
+	if err != nil || found == rootCmd {
+		return true
+	}
+
+	for found.Parent() != rootCmd {
+		found = found.Parent()
+	}
+
+	return found.Name() == "banks"
+}
+
+// registerPlugins registers the exporter plugins found in the plugin directory, and on PATH when opted in, and adds
+// their commands like any other exporter's. Each plugin is run to describe its capabilities, which decide the
+// commands it's given. A plugin can't replace a built-in exporter or take the name of another command.
+func registerPlugins() {
+	builtIn := export.All()
+	reserved := reservedCommands()
+
+	for _, p := range plugin.Discover(plugin.Dirs()) {
+		exportType := export.ExportType(p.Name)
+		if slices.Contains(builtIn, exportType) || slices.Contains(reserved, p.Name) {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		pluginexporter.Register(ctx, p)
+		cancel()
+
+		bankCmd := newBankCommand(exportType)
+		bankCmds[exportType] = bankCmd
+		rootCmd.AddCommand(bankCmd)
+	}
+}
+
+// reservedCommands returns the names and aliases of the root's commands, along with those cobra adds itself.
+func reservedCommands() []string {
+	reserved := []string{"help", "completion"}
+	for _, cmd := range rootCmd.Commands() {
+		reserved = append(reserved, cmd.Name())
+		reserved = append(reserved, cmd.Aliases...)
+	}
+
+	return reserved
+}
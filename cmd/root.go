@@ -5,15 +5,17 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"net/http"
 
-	"github.com/HallyG/fingrab/internal/api"
+	csvexporter "github.com/HallyG/fingrab/internal/csvfile/exporter"
 	"github.com/HallyG/fingrab/internal/export"
+	_ "github.com/HallyG/fingrab/internal/exporters"
+	gocardlessexporter "github.com/HallyG/fingrab/internal/gocardless/exporter"
 	"github.com/HallyG/fingrab/internal/log"
-	"github.com/HallyG/fingrab/internal/monzo"
 	monzoexporter "github.com/HallyG/fingrab/internal/monzo/exporter"
-	"github.com/HallyG/fingrab/internal/starling"
+	ofxexporter "github.com/HallyG/fingrab/internal/ofx/exporter"
+	plaidexporter "github.com/HallyG/fingrab/internal/plaid/exporter"
 	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/HallyG/fingrab/internal/store"
 	"github.com/spf13/cobra"
 
 	_ "embed"
@@ -54,52 +56,88 @@ var (
 	}
 	//go:embed cmd_example.txt
 	cmdExample string
+	//go:embed cmd_example_oauth.txt
+	cmdExampleOAuth string
 )
 
-func getBankCommand(exportType export.ExportType) *cobra.Command {
-	switch exportType {
-	case monzoexporter.ExportTypeMonzo:
-		return monzoCmd
-	case starlingexporter.ExportTypeStarling:
-		return starlingCmd
-	default:
-		return nil
+// bankCmds are the commands of the registered exporters, by export type.
+var bankCmds = make(map[export.ExportType]*cobra.Command)
+
+// newBankCommand builds the exporter's command from its registered metadata. Exporters of a bank's API get the
+// commands their capabilities support, file exporters add their own.
+func newBankCommand(exportType export.ExportType) *cobra.Command {
+	metadata, _ := export.Lookup(exportType)
+
+	cmd := &cobra.Command{
+		Use:   metadata.Command,
+		Short: metadata.DisplayName + " commands",
+		Long:  metadata.Description,
 	}
-}
 
-func init() {
-	export.Register(starlingexporter.ExportTypeStarling, func(opts export.Options) (export.Exporter, error) {
-		client := &http.Client{
-			Timeout: opts.Timeout,
-		}
+	if metadata.Capabilities.File {
+		return cmd
+	}
+
+	if metadata.Capabilities.Transactions {
+		cmd.AddCommand(newTransactionsCommand(exportType))
+	}
+
+	if metadata.Capabilities.Declines {
+		cmd.AddCommand(newDeclinesCommand(exportType))
+	}
+
+	if metadata.Capabilities.Accounts {
+		cmd.AddCommand(newAccountsCommand(exportType))
+	}
 
-		api := starling.New(client, api.WithAuthToken(opts.BearerAuthToken()))
-		return starlingexporter.New(api)
-	})
+	return cmd
+}
 
-	export.Register(monzoexporter.ExportTypeMonzo, func(opts export.Options) (export.Exporter, error) {
-		client := &http.Client{
-			Timeout: opts.Timeout,
-		}
+func getDataDir(cmd *cobra.Command) string {
+	dataDir, _ := cmd.Flags().GetString("data-dir")
+	return dataDir
+}
 
-		api := monzo.New(client, api.WithAuthToken(opts.BearerAuthToken()))
-		return monzoexporter.New(api)
-	})
+func init() {
+	defaultDataDir, _ := store.DefaultDir()
 
 	rootCmd.SilenceUsage = true
 	rootCmd.SilenceErrors = true
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "enable verbose output")
 	rootCmd.PersistentFlags().BoolP("no-colour", "", false, "disable coloured output")
+	rootCmd.PersistentFlags().String("data-dir", defaultDataDir, "directory used to store local state, such as backfilled history")
 
 	for _, exportType := range export.All() {
-		bankCmd := getBankCommand(exportType)
-		if bankCmd != nil {
-			bankCmd.AddCommand(newTransactionsCommand(exportType))
-			bankCmd.AddCommand(newAccountsCommand(exportType))
-			rootCmd.AddCommand(bankCmd)
-		}
+		bankCmd := newBankCommand(exportType)
+		bankCmds[exportType] = bankCmd
+		rootCmd.AddCommand(bankCmd)
 	}
+
+	rootCmd.AddCommand(newBanksCommand())
+
+	monzoCmd := bankCmds[monzoexporter.ExportTypeMonzo]
+	monzoCmd.AddCommand(newMonzoBackfillCommand())
+
+	starlingCmd := bankCmds[starlingexporter.ExportTypeStarling]
+	starlingCmd.AddCommand(newStarlingBalanceCommand())
+	starlingCmd.AddCommand(newStarlingStatementsCommand())
+	starlingCmd.AddCommand(newStarlingAnnotateCommand())
+	starlingCmd.AddCommand(newStarlingRecurringCommand())
+	starlingCmd.AddCommand(newStarlingInsightsCommand())
+	starlingCmd.AddCommand(newStarlingSyncCommand())
+	starlingCmd.AddCommand(newStarlingWebhookCommand())
+
+	bankCmds[gocardlessexporter.ExportTypeGoCardless].AddCommand(newGoCardlessRequisitionsCommand())
+	bankCmds[plaidexporter.ExportTypePlaid].AddCommand(newPlaidSyncCommand())
+
+	csvfileCmd := bankCmds[csvexporter.ExportTypeCSVFile]
+	csvfileCmd.AddCommand(newCSVFileTransactionsCommand())
+	csvfileCmd.AddCommand(newCSVFileProfilesCommand())
+
+	ofxCmd := bankCmds[ofxexporter.ExportTypeOFX]
+	ofxCmd.AddCommand(newOFXTransactionsCommand())
+	ofxCmd.AddCommand(newOFXAccountsCommand())
 }
 
 func Main(ctx context.Context, args []string, output io.Writer, errOutput io.Writer) error {
@@ -107,5 +145,9 @@ func Main(ctx context.Context, args []string, output io.Writer, errOutput io.Wri
 	rootCmd.SetErr(errOutput)
 	rootCmd.SetArgs(args[1:])
 
+	if usesPlugins(args[1:]) {
+		registerPlugins()
+	}
+
 	return rootCmd.ExecuteContext(ctx)
 }
@@ -1,13 +1,817 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
-var (
-	starlingCmd = &cobra.Command{
-		Use:   "starling",
-		Short: "Starling bank commands",
-		Long:  "Commands for interacting with the Starling API",
+type starlingBalanceOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	AccountID string
+	Output    string
+}
+
+func newStarlingBalanceCommand() *cobra.Command {
+	opts := &starlingBalanceOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "balance",
+		Short: "Show Starling balances and savings goal progress",
+		Long: `Show the cleared, effective, pending and available to spend balances of each Starling account,
+and how much has been saved towards each savings goal.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runStarlingBalance(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("starling: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab starling balance
+fingrab starling balance --account <account-id> --output json`,
 	}
-)
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID, name, type or currency (defaults to all accounts)")
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s)", outputTable, outputJSON))
+
+	return cmd
+}
+
+func runStarlingBalance(ctx context.Context, output io.Writer, opts *starlingBalanceOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(starlingexporter.ExportTypeStarling)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if err := validateOutput(opts.Output, outputTable, outputJSON); err != nil {
+		return err
+	}
+
+	authToken, err := getAuthToken(ctx, starlingexporter.ExportTypeStarling, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := starlingexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	balances, err := exporter.ExportBalances(ctx, opts.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == outputJSON {
+		return writeJSON(output, balances)
+	}
+
+	accountRows := make([][]string, 0, len(balances))
+	goalRows := make([][]string, 0)
+	for _, balance := range balances {
+		accountRows = append(accountRows, []string{
+			balance.AccountID,
+			balance.Name,
+			balance.Cleared.String(),
+			balance.Effective.String(),
+			balance.Pending.String(),
+			balance.AvailableToSpend.String(),
+			balance.Cleared.Currency,
+		})
+
+		for _, goal := range balance.SavingsGoals {
+			goalRows = append(goalRows, []string{
+				balance.Name,
+				goal.Name,
+				goal.TotalSaved.String(),
+				goal.Target.String(),
+				fmt.Sprintf("%.1f%%", goal.Progress),
+				goal.TotalSaved.Currency,
+			})
+		}
+	}
+
+	if err := writeTable(output,
+		[]string{"ID", "NAME", "CLEARED", "EFFECTIVE", "PENDING", "AVAILABLE", "CURRENCY"},
+		accountRows,
+	); err != nil {
+		return err
+	}
+
+	if len(goalRows) == 0 {
+		return nil
+	}
+
+	_, _ = fmt.Fprintln(output)
+
+	return writeTable(output,
+		[]string{"ACCOUNT", "SAVINGS GOAL", "SAVED", "TARGET", "PROGRESS", "CURRENCY"},
+		goalRows,
+	)
+}
+
+type starlingStatementsOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	AccountID string
+	From      string
+	To        string
+	Type      string
+	Dir       string
+}
+
+func newStarlingStatementsCommand() *cobra.Command {
+	opts := &starlingStatementsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "statements",
+		Short: "Download Starling statements",
+		Long: `Download the official monthly Starling statements for each available period in the range.
+Statements already present in the directory are skipped, as are incomplete statements for the current month.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runStarlingStatements(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("starling: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab starling statements --from 2025-01 --to 2025-06
+fingrab starling statements --from 2025-01 --type csv --dir ./statements`,
+	}
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID, name, type or currency, e.g. EUR")
+	cmd.Flags().StringVar(&opts.From, "from", "", "First month (YYYY-MM)")
+	cmd.Flags().StringVar(&opts.To, "to", "", "Last month (YYYY-MM), defaults to the latest available statement")
+	cmd.Flags().StringVar(&opts.Type, "type", string(starling.StatementFormatPDF), fmt.Sprintf("Statement file type (options: %s, %s)", starling.StatementFormatPDF, starling.StatementFormatCSV))
+	cmd.Flags().StringVar(&opts.Dir, "dir", ".", "Directory to save statements to")
+
+	_ = cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func runStarlingStatements(ctx context.Context, output io.Writer, opts *starlingStatementsOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(starlingexporter.ExportTypeStarling)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	authToken, err := getAuthToken(ctx, starlingexporter.ExportTypeStarling, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := starlingexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	statements, err := exporter.DownloadStatements(ctx, starlingexporter.StatementOptions{
+		AccountID: opts.AccountID,
+		From:      opts.From,
+		To:        opts.To,
+		Format:    starling.StatementFormat(opts.Type),
+		Dir:       opts.Dir,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range statements {
+		status := "downloaded"
+		if statement.Skipped {
+			status = "skipped (already exists)"
+		}
+
+		_, _ = fmt.Fprintf(output, "%s\t%s\t%s\n", statement.Period, statement.Path, status)
+	}
+
+	return nil
+}
+
+type starlingAnnotateOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	AccountID string
+	Input     string
+	DryRun    bool
+}
+
+func newStarlingAnnotateCommand() *cobra.Command {
+	opts := &starlingAnnotateOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "annotate",
+		Short: "Write notes and spending categories back to Starling transactions",
+		Long: `Update the user note and spending category of Starling feed items from a CSV.
+The CSV must have a feedItemUid column, and a userNote and/or spendingCategory column. Empty values are left unchanged.
+Each feed item is fetched first, so only values that differ are written.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runStarlingAnnotate(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("starling: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab starling annotate --input notes.csv --dry-run
+fingrab starling annotate --input notes.csv`,
+	}
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID, name, type or currency, e.g. EUR")
+	cmd.Flags().StringVar(&opts.Input, "input", "", "CSV file of feedItemUid, userNote and spendingCategory")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Show the changes without writing them")
+
+	_ = cmd.MarkFlagRequired("input")
+
+	return cmd
+}
+
+func runStarlingAnnotate(ctx context.Context, output io.Writer, opts *starlingAnnotateOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(starlingexporter.ExportTypeStarling)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	file, err := os.Open(opts.Input)
+	if err != nil {
+		return fmt.Errorf("open input: %w", err)
+	}
+	defer file.Close()
+
+	annotations, err := starlingexporter.ReadAnnotations(file)
+	if err != nil {
+		return fmt.Errorf("read input: %w", err)
+	}
+
+	authToken, err := getAuthToken(ctx, starlingexporter.ExportTypeStarling, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := starlingexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	changes, err := exporter.Annotate(ctx, opts.AccountID, annotations, opts.DryRun)
+
+	// Print whatever was changed before any failure, so a partial run can be followed up
+	for _, change := range changes {
+		prefix := "updated"
+		if !change.Applied {
+			prefix = "would update"
+		}
+
+		_, _ = fmt.Fprintf(output, "%s %s %s: %q -> %q\n", prefix, change.FeedItemID, change.Field, change.Old, change.New)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		_, _ = fmt.Fprintln(output, "no changes")
+	}
+
+	return nil
+}
+
+type starlingRecurringOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	AccountID string
+	Output    string
+}
+
+func newStarlingRecurringCommand() *cobra.Command {
+	opts := &starlingRecurringOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "recurring",
+		Short: "List Starling standing orders and direct debits",
+		Long: `List the account's active standing orders and live direct debit mandates, with their payee, amount,
+frequency and next payment date. Direct debits are collected by the payee, so only the last payment is known.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runStarlingRecurring(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("starling: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab starling recurring
+fingrab starling recurring --output csv > recurring.csv`,
+	}
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID, name, type or currency, e.g. EUR")
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s, %s)", outputTable, outputJSON, outputCSV))
+
+	return cmd
+}
+
+func runStarlingRecurring(ctx context.Context, output io.Writer, opts *starlingRecurringOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(starlingexporter.ExportTypeStarling)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if err := validateOutput(opts.Output, outputTable, outputJSON, outputCSV); err != nil {
+		return err
+	}
+
+	authToken, err := getAuthToken(ctx, starlingexporter.ExportTypeStarling, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := starlingexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	payments, err := exporter.ExportRecurringPayments(ctx, opts.AccountID)
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == outputJSON {
+		return writeJSON(output, payments)
+	}
+
+	formatDate := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+
+		return t.Format(timeFormat)
+	}
+
+	headers := []string{"TYPE", "PAYEE", "REFERENCE", "AMOUNT", "CURRENCY", "FREQUENCY", "NEXT PAYMENT", "LAST PAYMENT", "CATEGORY"}
+	rows := lo.Map(payments, func(payment *domain.RecurringPayment, _ int) []string {
+		return []string{
+			string(payment.Type),
+			payment.Payee,
+			payment.Reference,
+			payment.Amount.String(),
+			payment.Amount.Currency,
+			payment.Frequency,
+			formatDate(payment.NextPaymentDate),
+			formatDate(payment.LastPaymentDate),
+			payment.Category,
+		}
+	})
+
+	if opts.Output == outputCSV {
+		return writeCSV(output, headers, rows)
+	}
+
+	return writeTable(output, headers, rows)
+}
+
+type starlingInsightsOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	AccountID string
+	Month     string
+	By        string
+	Output    string
+}
+
+func newStarlingInsightsCommand() *cobra.Command {
+	opts := &starlingInsightsOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "insights",
+		Short: "Show Starling spending insights for a month",
+		Long: `Show the month's spending as aggregated by Starling, broken down by spending category, counterparty or
+country. It's a server-side cross-check for totals calculated from exported transactions.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			err := runStarlingInsights(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("starling: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab starling insights --month 2025-03
+fingrab starling insights --month 2025-03 --by counterparty --output json`,
+	}
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID, name, type or currency, e.g. EUR")
+	cmd.Flags().StringVar(&opts.Month, "month", "", "Month (YYYY-MM)")
+	cmd.Flags().StringVar(&opts.By, "by", string(starling.InsightsByCategory), fmt.Sprintf("Breakdown (options: %s, %s, %s)", starling.InsightsByCategory, starling.InsightsByCounterparty, starling.InsightsByCountry))
+	cmd.Flags().StringVar(&opts.Output, "output", outputTable, fmt.Sprintf("Output (options: %s, %s)", outputTable, outputJSON))
+
+	_ = cmd.MarkFlagRequired("month")
+
+	return cmd
+}
+
+func runStarlingInsights(ctx context.Context, output io.Writer, opts *starlingInsightsOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(starlingexporter.ExportTypeStarling)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if err := validateOutput(opts.Output, outputTable, outputJSON); err != nil {
+		return err
+	}
+
+	authToken, err := getAuthToken(ctx, starlingexporter.ExportTypeStarling, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := starlingexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	insights, err := exporter.ExportSpendingInsights(ctx, starlingexporter.InsightsOptions{
+		AccountID: opts.AccountID,
+		Month:     opts.Month,
+		By:        starling.InsightsBreakdown(opts.By),
+	})
+	if err != nil {
+		return err
+	}
+
+	if opts.Output == outputJSON {
+		return writeJSON(output, insights)
+	}
+
+	rows := lo.Map(insights.Breakdown, func(insight *starlingexporter.SpendingInsight, _ int) []string {
+		return []string{
+			insight.Name,
+			insight.Spent.String(),
+			insight.Received.String(),
+			insight.Net.String(),
+			fmt.Sprintf("%.1f%%", insight.Percentage),
+			fmt.Sprintf("%d", insight.TransactionCount),
+			insight.Spent.Currency,
+		}
+	})
+	rows = append(rows, []string{
+		"TOTAL",
+		insights.Spent.String(),
+		insights.Received.String(),
+		insights.Net.String(),
+		"",
+		"",
+		insights.Spent.Currency,
+	})
+
+	return writeTable(output,
+		[]string{strings.ToUpper(opts.By), "SPENT", "RECEIVED", "NET", "SHARE", "TRANSACTIONS", "CURRENCY"},
+		rows,
+	)
+}
+
+type starlingSyncOptions struct {
+	AuthToken string
+	Timeout   time.Duration
+	DataDir   string
+	AccountID string
+	Since     string
+	Format    string
+	Amended   string
+	Audit     bool
+}
+
+func newStarlingSyncCommand() *cobra.Command {
+	opts := &starlingSyncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Export Starling transactions created or changed since the last sync",
+		Long: `Export only the feed items created or changed since the previous sync of each account, rather than a whole
+date range. The time of the latest change is stored in the data directory once the transactions have been written.
+New transactions are written to stdout. Transactions returned by an earlier sync which have since changed
+(e.g. settled, reversed or had their note edited) are written separately to --amended, so they can be updated
+rather than imported twice. Transactions are remembered for 90 days, after which a change is written as new.`,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.DataDir = getDataDir(cmd)
+			err := runStarlingSync(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("starling: %w", err)
+			}
+
+			return nil
+		},
+		Example: `# The first sync of an account starts from --since
+fingrab starling sync --since 2025-03-01 --format detailed --amended amended.csv > new.csv
+
+# Later syncs continue from the last change seen
+fingrab starling sync --account all --format detailed --amended amended.csv >> new.csv`,
+	}
+
+	cmd.Flags().StringVar(&opts.AuthToken, "token", "", "API auth token")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", timeout, "API request timeout")
+	cmd.Flags().StringVar(&opts.AccountID, "account", "", "Account ID, name, type or currency, e.g. EUR, or all to include every space")
+	cmd.Flags().StringVar(&opts.Since, "since", "", "Start date (YYYY-MM-DD) for accounts which haven't been synced before")
+	cmd.Flags().StringVar(&opts.Format, "format", string(format.FormatTypeDetailed), formatUsage())
+	cmd.Flags().StringVar(&opts.Amended, "amended", "", "File to write amended transactions to (required when any were amended)")
+	cmd.Flags().BoolVar(&opts.Audit, "audit", false, "Include declined transactions")
+
+	return cmd
+}
+
+func runStarlingSync(ctx context.Context, output io.Writer, opts *starlingSyncOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(starlingexporter.ExportTypeStarling)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	if opts.DataDir == "" {
+		return errors.New("data directory is required")
+	}
+
+	var since time.Time
+	if opts.Since != "" {
+		date, err := parseDate(opts.Since)
+		if err != nil {
+			return fmt.Errorf("since: %w", err)
+		}
+
+		since = date
+	}
+
+	formatter, err := format.NewFormatter(format.FormatType(opts.Format), output)
+	if err != nil {
+		return fmt.Errorf("formatter: %w", err)
+	}
+
+	authToken, err := getAuthToken(ctx, starlingexporter.ExportTypeStarling, opts.AuthToken, opts.Timeout)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := starlingexporter.NewFromOptions(export.Options{
+		AuthToken: authToken,
+		Timeout:   opts.Timeout,
+		DataDir:   opts.DataDir,
+	})
+	if err != nil {
+		return fmt.Errorf("exporter: %w", err)
+	}
+
+	result, err := exporter.Sync(ctx, starlingexporter.SyncOptions{
+		AccountID: opts.AccountID,
+		Since:     since,
+		Audit:     opts.Audit,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Committing the marks would lose amendments that aren't written, so refuse before anything is written
+	if len(result.Amended) > 0 && opts.Amended == "" {
+		return fmt.Errorf("%d transactions were amended since the last sync, use --amended to write them", len(result.Amended))
+	}
+
+	// Every output is opened before anything is written, so a failure doesn't leave the new transactions written alone
+	var amendedFile *os.File
+	var amendedFormatter format.Formatter
+	if opts.Amended != "" {
+		amendedFile, err = os.Create(opts.Amended)
+		if err != nil {
+			return fmt.Errorf("create amended output: %w", err)
+		}
+		defer amendedFile.Close()
+
+		amendedFormatter, err = format.NewFormatter(format.FormatType(opts.Format), amendedFile)
+		if err != nil {
+			return fmt.Errorf("formatter: %w", err)
+		}
+	}
+
+	if err := format.WriteCollection(formatter, result.New); err != nil {
+		return err
+	}
+
+	if amendedFormatter != nil {
+		if err := format.WriteCollection(amendedFormatter, result.Amended); err != nil {
+			return err
+		}
+
+		if err := amendedFile.Close(); err != nil {
+			return fmt.Errorf("close amended output: %w", err)
+		}
+	}
+
+	// Only advance the high-water marks once everything has been written, so a failed sync is fetched again
+	return exporter.CommitSync(result)
+}
+
+const starlingWebhookSecretEnv = "STARLING_WEBHOOK_SECRET"
+
+type starlingWebhookServeOptions struct {
+	Addr          string
+	Path          string
+	PublicKeyFile string
+	Secret        string
+	Output        string
+	Format        string
+	Tolerance     time.Duration
+	DataDir       string
+}
+
+func newStarlingWebhookCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Receive Starling webhooks",
+	}
+
+	cmd.AddCommand(newStarlingWebhookServeCommand())
+
+	return cmd
+}
+
+func newStarlingWebhookServeCommand() *cobra.Command {
+	opts := &starlingWebhookServeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Append settled transactions from Starling feed item webhooks to a file",
+		Long: fmt.Sprintf(`Serve an endpoint for Starling feed item webhooks. Each delivery's X-Hook-Signature is verified against the
+webhook public key (or the legacy shared secret, also read from %s), and each settled feed item is
+appended to the output as a transaction. Stale and replayed deliveries are rejected. The feed items written are
+kept in the data directory for 30 days, so a later event for one isn't appended again, even after a restart.`, starlingWebhookSecretEnv),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			opts.DataDir = getDataDir(cmd)
+			if opts.Secret == "" {
+				opts.Secret = os.Getenv(starlingWebhookSecretEnv)
+			}
+
+			err := runStarlingWebhookServe(cmd.Context(), cmd.OutOrStdout(), opts)
+			if err != nil {
+				return fmt.Errorf("starling: %w", err)
+			}
+
+			return nil
+		},
+		Example: `fingrab starling webhook serve --public-key-file starling-webhook.pem --output transactions.csv --format ynab
+fingrab starling webhook serve --addr :9000 --secret <shared-secret>`,
+	}
+
+	cmd.Flags().StringVar(&opts.Addr, "addr", ":8080", "Address to listen on")
+	cmd.Flags().StringVar(&opts.Path, "path", "/webhooks/starling", "Path to receive webhooks on")
+	cmd.Flags().StringVar(&opts.PublicKeyFile, "public-key-file", "", "File containing the webhook public key from the Starling developer portal")
+	cmd.Flags().StringVar(&opts.Secret, "secret", "", "Legacy webhook shared secret")
+	cmd.Flags().StringVar(&opts.Output, "output", "", "File to append transactions to (defaults to stdout)")
+	cmd.Flags().StringVar(&opts.Format, "format", string(format.FormatTypeMoneyDance), formatUsage())
+	cmd.Flags().DurationVar(&opts.Tolerance, "tolerance", 5*time.Minute, "Maximum age of a webhook event before it is rejected as a replay")
+
+	cmd.MarkFlagsMutuallyExclusive("public-key-file", "secret")
+
+	return cmd
+}
+
+func runStarlingWebhookServe(ctx context.Context, stdout io.Writer, opts *starlingWebhookServeOptions) error {
+	logger := log.FromContext(ctx).With(
+		slog.String("bank", string(starlingexporter.ExportTypeStarling)),
+	)
+	ctx = log.WithContext(ctx, logger)
+
+	var verifier starling.SignatureVerifier
+	switch {
+	case opts.PublicKeyFile != "":
+		publicKey, err := os.ReadFile(opts.PublicKeyFile)
+		if err != nil {
+			return fmt.Errorf("read public key: %w", err)
+		}
+
+		verifier, err = starling.NewPublicKeyVerifier(publicKey)
+		if err != nil {
+			return err
+		}
+	case opts.Secret != "":
+		verifier = starling.NewSharedSecretVerifier(opts.Secret)
+	default:
+		return fmt.Errorf("a webhook public key or shared secret is required (--public-key-file, --secret or %s)", starlingWebhookSecretEnv)
+	}
+
+	output := stdout
+	writeHeader := true
+	if opts.Output != "" {
+		file, err := os.OpenFile(opts.Output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("open output: %w", err)
+		}
+		defer file.Close()
+
+		info, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("stat output: %w", err)
+		}
+
+		// Only a new file needs a header, an existing one is appended to
+		writeHeader = info.Size() == 0
+		output = file
+	}
+
+	formatter, err := format.NewFormatter(format.FormatType(opts.Format), output)
+	if err != nil {
+		return fmt.Errorf("formatter: %w", err)
+	}
+
+	if writeHeader {
+		if err := formatter.WriteHeader(); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+
+		if err := formatter.Flush(); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+	}
+
+	handlerOpts := []starlingexporter.WebhookOption{starlingexporter.WithTolerance(opts.Tolerance)}
+	if opts.DataDir != "" {
+		s, err := store.New(opts.DataDir)
+		if err != nil {
+			return fmt.Errorf("store: %w", err)
+		}
+
+		handlerOpts = append(handlerOpts, starlingexporter.WithWebhookStore(s))
+	}
+
+	handler, err := starlingexporter.NewWebhookHandler(verifier, formatter, handlerOpts...)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(opts.Path, handler)
+
+	server := &http.Server{
+		Addr:              opts.Addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logger.InfoContext(ctx, "listening for webhooks", slog.String("addr", opts.Addr), slog.String("path", opts.Path))
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+
+	return nil
+}
@@ -78,7 +78,8 @@ func New(baseURL string, httpClient *http.Client, opts ...Option) *resty.Client
 			return nil
 		}).
 		AddRetryConditions(func(r *resty.Response, err error) bool {
-			retry := err != nil || r.StatusCode() >= 500
+			// 501 Not Implemented is permanent, e.g. an endpoint a provider doesn't support
+			retry := err != nil || (r.StatusCode() >= 500 && r.StatusCode() != http.StatusNotImplemented)
 			return retry
 		})
 
@@ -126,3 +127,54 @@ func ExecuteRequest[T any](ctx context.Context, client *resty.Client, method, ur
 
 	return &result, nil
 }
+
+// ExecuteDownload performs an HTTP request with the specified method, URL, query parameters and Accept header,
+// and returns the raw response body, e.g. a PDF or CSV file.
+// Returns an error if the request fails or the if the response indicates an error (4xx or 5xx status code).
+func ExecuteDownload(ctx context.Context, client *resty.Client, method, url string, values url.Values, accept string) ([]byte, error) {
+	resp, err := client.R().
+		SetContext(ctx).
+		SetHeader("Accept", accept).
+		SetUnescapeQueryParams(false).
+		SetQueryParamsFromValues(values).
+		Execute(method, url)
+	if err != nil {
+		return nil, fmt.Errorf("execute %s %s: %w", method, resp.Request.URL, err)
+	}
+
+	if resp.IsError() {
+		if err, ok := resp.Error().(error); ok {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return resp.Bytes(), nil
+}
+
+// ExecuteRequestWithBody performs an HTTP request with the specified method and URL, sending body as JSON,
+// and unmarshals the response into the provided type T. Use struct{} for T when the response has no body.
+// Returns an error if the request fails or the if the response indicates an error (4xx or 5xx status code).
+func ExecuteRequestWithBody[T any](ctx context.Context, client *resty.Client, method, url string, body any) (*T, error) {
+	var result T
+
+	resp, err := client.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetBody(body).
+		Execute(method, url)
+	if err != nil {
+		return nil, fmt.Errorf("execute %s %s: %w", method, resp.Request.URL, err)
+	}
+
+	if resp.IsError() {
+		if err, ok := resp.Error().(error); ok {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,106 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/csvfile"
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/samber/lo"
+)
+
+const (
+	ExportTypeCSVFile   = export.ExportType("csvfile")
+	csvFileMaxDateRange = time.Duration(0)
+)
+
+var _ export.Exporter = (*TransactionExporter)(nil)
+
+var capabilities = export.Capabilities{
+	Transactions: true,
+	File:         true,
+}
+
+// TransactionExporter reads transactions from a statement CSV, rather than a bank's API.
+type TransactionExporter struct {
+	input   string
+	profile *csvfile.Profile
+}
+
+func New(input string, profile *csvfile.Profile) (*TransactionExporter, error) {
+	if input == "" {
+		return nil, errors.New("input file is required")
+	}
+
+	if profile == nil {
+		return nil, errors.New("profile is required")
+	}
+
+	return &TransactionExporter{
+		input:   input,
+		profile: profile,
+	}, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return ExportTypeCSVFile
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return csvFileMaxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
+// ExportAccounts returns the statement's account, named after the input file as statements don't identify it.
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	return []*domain.Account{
+		{
+			ID:       filepath.Base(e.input),
+			Name:     e.profile.Bank,
+			Type:     e.profile.Name,
+			Currency: e.profile.Currency,
+		},
+	}, nil
+}
+
+// ExportTransactions returns the statement's transactions within the date range, whose end is exclusive. Either end
+// of the range may be left unset to include the whole statement.
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	file, err := os.Open(e.input)
+	if err != nil {
+		return nil, fmt.Errorf("open input: %w", err)
+	}
+	defer file.Close()
+
+	transactions, err := csvfile.Read(ctx, file, e.profile)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", filepath.Base(e.input), err)
+	}
+
+	filtered := lo.Filter(transactions, func(txn *domain.Transaction, _ int) bool {
+		if !opts.StartDate.IsZero() && txn.CreatedAt.Before(opts.StartDate) {
+			return false
+		}
+
+		return opts.EndDate.IsZero() || txn.CreatedAt.Before(opts.EndDate)
+	})
+
+	log.FromContext(ctx).InfoContext(ctx, "read transactions from statement",
+		slog.String("input", e.input),
+		slog.String("profile", e.profile.Name),
+		slog.Int("transaction.total", len(transactions)),
+		slog.Int("transaction.count", len(filtered)),
+	)
+
+	return filtered, nil
+}
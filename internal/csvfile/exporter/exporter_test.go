@@ -0,0 +1,146 @@
+package exporter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/csvfile"
+	csvexporter "github.com/HallyG/fingrab/internal/csvfile/exporter"
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+const statement = `Number,Date,Account,Amount,Subcategory,Memo
+,01/03/2025,20-00-00 12345678,-12.99,Card Payment,NETFLIX.COM
+,15/03/2025,20-00-00 12345678,1500.00,Credit,ACME LTD SALARY
+,01/04/2025,20-00-00 12345678,-950.00,Standing Order,RENT
+`
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	profile, err := csvfile.LoadProfile(t.Context(), "barclays")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		input       string
+		profile     *csvfile.Profile
+		expectedErr string
+	}{
+		"returns error when input is empty": {
+			profile:     profile,
+			expectedErr: "input file is required",
+		},
+		"returns error when profile is nil": {
+			input:       "statement.csv",
+			expectedErr: "profile is required",
+		},
+		"success": {
+			input:   "statement.csv",
+			profile: profile,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter, err := csvexporter.New(test.input, test.profile)
+
+			if test.expectedErr != "" {
+				require.Nil(t, exporter)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, csvexporter.ExportTypeCSVFile, exporter.Type())
+			require.Equal(t, time.Duration(0), exporter.MaxDateRange())
+		})
+	}
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) export.Exporter {
+		t.Helper()
+
+		input := filepath.Join(t.TempDir(), "statement.csv")
+		require.NoError(t, os.WriteFile(input, []byte(statement), 0o600))
+
+		profile, err := csvfile.LoadProfile(t.Context(), "barclays")
+		require.NoError(t, err)
+
+		exporter, err := csvexporter.New(input, profile)
+		require.NoError(t, err)
+
+		return exporter
+	}
+
+	tests := map[string]struct {
+		startDate          time.Time
+		endDate            time.Time
+		expectedReferences []string
+	}{
+		"returns whole statement when range is unset": {
+			expectedReferences: []string{"NETFLIX.COM", "ACME LTD SALARY", "RENT"},
+		},
+		"returns transactions within range": {
+			startDate:          time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC),
+			endDate:            time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+			expectedReferences: []string{"ACME LTD SALARY"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transactions, err := setup(t).ExportTransactions(t.Context(), export.TransactionOptions{
+				StartDate: test.startDate,
+				EndDate:   test.endDate,
+			})
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedReferences, lo.Map(transactions, func(txn *domain.Transaction, _ int) string {
+				return txn.Reference
+			}))
+		})
+	}
+
+	t.Run("returns error when input is missing", func(t *testing.T) {
+		t.Parallel()
+
+		profile, err := csvfile.LoadProfile(t.Context(), "barclays")
+		require.NoError(t, err)
+
+		exporter, err := csvexporter.New(filepath.Join(t.TempDir(), "missing.csv"), profile)
+		require.NoError(t, err)
+
+		transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{})
+
+		require.Nil(t, transactions)
+		require.ErrorContains(t, err, "open input")
+	})
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	profile, err := csvfile.LoadProfile(t.Context(), "nationwide")
+	require.NoError(t, err)
+
+	exporter, err := csvexporter.New(filepath.Join("statements", "march.csv"), profile)
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Account{
+		{ID: "march.csv", Name: "Nationwide", Type: "nationwide", Currency: "GBP"},
+	}, accounts)
+}
@@ -0,0 +1,29 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HallyG/fingrab/internal/csvfile"
+	"github.com/HallyG/fingrab/internal/export"
+)
+
+func init() {
+	export.Register(ExportTypeCSVFile, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName("Bank statement CSV"),
+		export.WithDescription("Commands for converting statement CSVs downloaded from banks without an API"),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+// NewFromOptions returns an exporter of the input file, read with the built-in or custom profile.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	profile, err := csvfile.LoadProfile(context.Background(), opts.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+
+	return New(opts.Input, profile)
+}
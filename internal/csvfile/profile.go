@@ -0,0 +1,151 @@
+// Package csvfile reads statement CSVs downloaded from banks without an API, using declarative mapping profiles
+// that describe each bank's columns, date layout and amount sign convention.
+package csvfile
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// AmountSign is the sign convention of a profile's amount column.
+type AmountSign string
+
+const (
+	// AmountSignDebitNegative amounts are negative when money leaves the account, as exported by most banks.
+	AmountSignDebitNegative AmountSign = "debit-negative"
+	// AmountSignDebitPositive amounts are positive when money leaves the account, as exported by credit cards.
+	AmountSignDebitPositive AmountSign = "debit-positive"
+)
+
+// Profile maps the columns of a bank's statement CSV to transaction fields. Columns are matched by header name,
+// ignoring case and surrounding whitespace.
+type Profile struct {
+	Name             string     `json:"name"`
+	Bank             string     `json:"bank"`                       // Bank name given to the transactions
+	Delimiter        string     `json:"delimiter,omitempty"`        // Defaults to a comma
+	SkipRows         int        `json:"skipRows,omitempty"`         // Rows before the header row, e.g. account details
+	DateColumn       string     `json:"dateColumn"`                 // Column of the transaction date
+	DateLayout       string     `json:"dateLayout"`                 // Go time layout of the date, e.g. 02/01/2006
+	ReferenceColumn  string     `json:"referenceColumn"`            // Column of the payee or description
+	AmountColumn     string     `json:"amountColumn,omitempty"`     // Column of a single signed amount
+	AmountSign       AmountSign `json:"amountSign,omitempty"`       // Sign convention of AmountColumn, defaults to debit-negative
+	DebitColumn      string     `json:"debitColumn,omitempty"`      // Column of money paid out, when not using AmountColumn
+	CreditColumn     string     `json:"creditColumn,omitempty"`     // Column of money paid in, when not using AmountColumn
+	DecimalSeparator string     `json:"decimalSeparator,omitempty"` // Defaults to a point, or a comma for amounts like 1.234,56
+	Currency         string     `json:"currency,omitempty"`         // Currency of every amount, when not using CurrencyColumn
+	CurrencyColumn   string     `json:"currencyColumn,omitempty"`   // Column of each amount's currency
+	IDColumn         string     `json:"idColumn,omitempty"`         // Column of the bank's transaction ID
+	CategoryColumn   string     `json:"categoryColumn,omitempty"`   // Column of the bank's category
+	NotesColumn      string     `json:"notesColumn,omitempty"`      // Column of any additional details
+}
+
+func (p Profile) Validate(ctx context.Context) error {
+	usesAmount := p.AmountColumn != ""
+
+	return validation.ValidateStructWithContext(ctx, &p,
+		validation.Field(&p.Name, validation.Required.Error("is required")),
+		validation.Field(&p.Bank, validation.Required.Error("is required")),
+		validation.Field(&p.Delimiter, validation.RuneLength(0, 1).Error("must be a single character")),
+		validation.Field(&p.SkipRows, validation.Min(0).Error("must not be negative")),
+		validation.Field(&p.DateColumn, validation.Required.Error("is required")),
+		validation.Field(&p.DateLayout, validation.Required.Error("is required")),
+		validation.Field(&p.ReferenceColumn, validation.Required.Error("is required")),
+		validation.Field(&p.AmountSign, validation.In(AmountSignDebitNegative, AmountSignDebitPositive).Error("must be debit-negative or debit-positive")),
+		validation.Field(&p.DebitColumn,
+			validation.When(!usesAmount, validation.Required.Error("is required without an amount column")),
+			validation.When(usesAmount, validation.Empty.Error("must be empty with an amount column")),
+		),
+		validation.Field(&p.CreditColumn,
+			validation.When(!usesAmount, validation.Required.Error("is required without an amount column")),
+			validation.When(usesAmount, validation.Empty.Error("must be empty with an amount column")),
+		),
+		validation.Field(&p.DecimalSeparator, validation.In(".", ",").Error("must be . or ,")),
+		validation.Field(&p.Currency, validation.When(p.CurrencyColumn == "", validation.Required.Error("is required without a currency column"))),
+	)
+}
+
+var profiles = map[string]*Profile{
+	"amex": {
+		Name:            "amex",
+		Bank:            "Amex",
+		DateColumn:      "Date",
+		DateLayout:      "02/01/2006",
+		ReferenceColumn: "Description",
+		AmountColumn:    "Amount",
+		AmountSign:      AmountSignDebitPositive,
+		Currency:        "GBP",
+		IDColumn:        "Reference",
+		CategoryColumn:  "Category",
+		NotesColumn:     "Extended Details",
+	},
+	"barclays": {
+		Name:            "barclays",
+		Bank:            "Barclays",
+		DateColumn:      "Date",
+		DateLayout:      "02/01/2006",
+		ReferenceColumn: "Memo",
+		AmountColumn:    "Amount",
+		AmountSign:      AmountSignDebitNegative,
+		Currency:        "GBP",
+		CategoryColumn:  "Subcategory",
+	},
+	"nationwide": {
+		Name:            "nationwide",
+		Bank:            "Nationwide",
+		SkipRows:        3,
+		DateColumn:      "Date",
+		DateLayout:      "02 Jan 2006",
+		ReferenceColumn: "Description",
+		DebitColumn:     "Paid out",
+		CreditColumn:    "Paid in",
+		Currency:        "GBP",
+		CategoryColumn:  "Transaction type",
+	},
+}
+
+// Profiles returns the names of the built-in profiles, sorted.
+func Profiles() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// LoadProfile returns the built-in profile with the name (case-insensitive) or, failing that, reads a custom profile
+// from the JSON file at the path.
+func LoadProfile(ctx context.Context, nameOrPath string) (*Profile, error) {
+	if profile, ok := profiles[strings.ToLower(strings.TrimSpace(nameOrPath))]; ok {
+		copied := *profile
+		return &copied, nil
+	}
+
+	b, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("unknown profile %q (built-in profiles: %s)", nameOrPath, strings.Join(Profiles(), ", "))
+		}
+
+		return nil, fmt.Errorf("read profile: %w", err)
+	}
+
+	var profile Profile
+	if err := json.Unmarshal(b, &profile); err != nil {
+		return nil, fmt.Errorf("decode profile: %w", err)
+	}
+
+	if err := profile.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+
+	return &profile, nil
+}
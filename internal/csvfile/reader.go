@@ -0,0 +1,245 @@
+package csvfile
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+)
+
+// Read parses the statement CSV using the profile's column mapping. Rows without a date, such as totals or blank
+// trailing rows, are skipped.
+func Read(ctx context.Context, r io.Reader, profile *Profile) ([]*domain.Transaction, error) {
+	if profile == nil {
+		return nil, errors.New("profile is required")
+	}
+
+	if err := profile.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid profile: %w", err)
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if profile.Delimiter != "" {
+		reader.Comma = []rune(profile.Delimiter)[0]
+	}
+
+	for range profile.SkipRows {
+		if _, err := reader.Read(); err != nil {
+			return nil, fmt.Errorf("skip row: %w", err)
+		}
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	columns, err := newColumns(header, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*domain.Transaction, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("read row: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		transaction, err := columns.transaction(record, profile)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if transaction != nil {
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	return transactions, nil
+}
+
+// columns is the index of each of the profile's columns in the header, -1 when the profile doesn't use it.
+type columns struct {
+	date, reference, amount, debit, credit, currency, id, category, notes int
+}
+
+func newColumns(header []string, profile *Profile) (*columns, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[normaliseColumn(name)] = i
+	}
+
+	var missing []string
+	find := func(name string) int {
+		if name == "" {
+			return -1
+		}
+
+		i, ok := index[normaliseColumn(name)]
+		if !ok {
+			missing = append(missing, name)
+			return -1
+		}
+
+		return i
+	}
+
+	c := &columns{
+		date:      find(profile.DateColumn),
+		reference: find(profile.ReferenceColumn),
+		amount:    find(profile.AmountColumn),
+		debit:     find(profile.DebitColumn),
+		credit:    find(profile.CreditColumn),
+		currency:  find(profile.CurrencyColumn),
+		id:        find(profile.IDColumn),
+		category:  find(profile.CategoryColumn),
+		notes:     find(profile.NotesColumn),
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing columns for profile %s: %s", profile.Name, strings.Join(missing, ", "))
+	}
+
+	return c, nil
+}
+
+func (c *columns) transaction(record []string, profile *Profile) (*domain.Transaction, error) {
+	value := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	date := value(c.date)
+	if date == "" {
+		return nil, nil
+	}
+
+	createdAt, err := time.Parse(profile.DateLayout, date)
+	if err != nil {
+		return nil, fmt.Errorf("parse date %q: %w", date, err)
+	}
+
+	currency := profile.Currency
+	if c.currency >= 0 {
+		currency = strings.ToUpper(value(c.currency))
+	}
+
+	var minorUnits int64
+	if c.amount >= 0 {
+		minorUnits, err = parseAmount(value(c.amount), currency, profile.DecimalSeparator)
+		if err != nil {
+			return nil, err
+		}
+
+		if profile.AmountSign == AmountSignDebitPositive {
+			minorUnits = -minorUnits
+		}
+	} else {
+		debit, err := parseAmount(value(c.debit), currency, profile.DecimalSeparator)
+		if err != nil {
+			return nil, err
+		}
+
+		credit, err := parseAmount(value(c.credit), currency, profile.DecimalSeparator)
+		if err != nil {
+			return nil, err
+		}
+
+		minorUnits = abs(credit) - abs(debit)
+	}
+
+	return &domain.Transaction{
+		ID:        strings.Trim(value(c.id), "'"), // Amex quotes its references to stop spreadsheets mangling them
+		Amount:    domain.Money{MinorUnit: minorUnits, Currency: currency},
+		Reference: value(c.reference),
+		Category:  value(c.category),
+		CreatedAt: createdAt,
+		IsDeposit: minorUnits > 0,
+		BankName:  profile.Bank,
+		Notes:     value(c.notes),
+		Status:    domain.TransactionStatusSettled,
+	}, nil
+}
+
+// parseAmount parses an amount in major units, e.g. "£1,234.56", "-12.50" or "(12.50)", to minor units. Whichever
+// of a point or comma isn't the decimal separator (a point by default) separates thousands, so it must come before
+// the decimal separator and be followed by a group of three digits. An empty amount is zero.
+func parseAmount(amount string, currency string, decimalSeparator string) (int64, error) {
+	decimal, thousands := '.', ','
+	if decimalSeparator == "," {
+		decimal, thousands = ',', '.'
+	}
+
+	// e.g. 1.234,56 read with a point as the decimal separator, which would otherwise be parsed as 1.23
+	if i := strings.IndexRune(amount, decimal); i >= 0 && strings.ContainsRune(amount[i:], thousands) {
+		return 0, fmt.Errorf("parse amount %q: thousands separator %q after decimal separator %q, check the profile's decimal separator", amount, thousands, decimal)
+	}
+
+	// e.g. 12,50 read with a point as the decimal separator, which would otherwise be parsed as 1250
+	for i, r := range amount {
+		if r == thousands && !isThousandsGroup(amount[i+1:]) {
+			return 0, fmt.Errorf("parse amount %q: thousands separator %q not followed by three digits, check the profile's decimal separator", amount, thousands)
+		}
+	}
+
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9', r == '-':
+			return r
+		case r == decimal:
+			return '.'
+		case r == '(':
+			return '-'
+		default:
+			return -1
+		}
+	}, amount)
+
+	if cleaned == "" {
+		return 0, nil
+	}
+
+	major, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse amount %q: %w", amount, err)
+	}
+
+	return domain.MoneyFromMajorUnit(major, currency).MinorUnit, nil
+}
+
+// normaliseColumn lowercases the column name and removes any byte order mark, which some banks prefix files with.
+func normaliseColumn(name string) string {
+	return strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// isThousandsGroup reports whether the rest of an amount, after a thousands separator, starts with exactly three digits.
+func isThousandsGroup(rest string) bool {
+	digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+	return digits == 3
+}
@@ -0,0 +1,223 @@
+package csvfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/csvfile"
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := map[string]struct {
+		profile              string
+		input                string
+		expectedTransactions []*domain.Transaction
+	}{
+		"amex charges are positive": {
+			profile: "amex",
+			input:   "amex.csv",
+			expectedTransactions: []*domain.Transaction{
+				{
+					ID:        "AT250730031000010012345",
+					Amount:    domain.Money{MinorUnit: -4218, Currency: "GBP"},
+					Reference: "TESCO STORES 2041",
+					Category:  "General Purchases-Groceries",
+					CreatedAt: date(2025, time.March, 14),
+					BankName:  "Amex",
+					Status:    domain.TransactionStatusSettled,
+				},
+				{
+					ID:        "AT250740031000010054321",
+					Amount:    domain.Money{MinorUnit: 25000, Currency: "GBP"},
+					Reference: "PAYMENT RECEIVED - THANK YOU",
+					CreatedAt: date(2025, time.March, 15),
+					IsDeposit: true,
+					BankName:  "Amex",
+					Status:    domain.TransactionStatusSettled,
+				},
+			},
+		},
+		"barclays amounts are signed": {
+			profile: "barclays",
+			input:   "barclays.csv",
+			expectedTransactions: []*domain.Transaction{
+				{
+					Amount:    domain.Money{MinorUnit: -1299, Currency: "GBP"},
+					Reference: "NETFLIX.COM",
+					Category:  "Card Payment",
+					CreatedAt: date(2025, time.March, 3),
+					BankName:  "Barclays",
+					Status:    domain.TransactionStatusSettled,
+				},
+				{
+					Amount:    domain.Money{MinorUnit: 150000, Currency: "GBP"},
+					Reference: "ACME LTD SALARY",
+					Category:  "Credit",
+					CreatedAt: date(2025, time.March, 4),
+					IsDeposit: true,
+					BankName:  "Barclays",
+					Status:    domain.TransactionStatusSettled,
+				},
+			},
+		},
+		"nationwide skips account details and splits paid in and out": {
+			profile: "nationwide",
+			input:   "nationwide.csv",
+			expectedTransactions: []*domain.Transaction{
+				{
+					Amount:    domain.Money{MinorUnit: -345, Currency: "GBP"},
+					Reference: "COSTA COFFEE",
+					Category:  "Visa purchase",
+					CreatedAt: date(2025, time.March, 5),
+					BankName:  "Nationwide",
+					Status:    domain.TransactionStatusSettled,
+				},
+				{
+					Amount:    domain.Money{MinorUnit: 100000, Currency: "GBP"},
+					Reference: "ACME LTD",
+					Category:  "Bank credit",
+					CreatedAt: date(2025, time.March, 6),
+					IsDeposit: true,
+					BankName:  "Nationwide",
+					Status:    domain.TransactionStatusSettled,
+				},
+			},
+		},
+		"custom profile with delimiter, decimal separator and currency column": {
+			profile: filepath.Join("testdata", "custom-profile.json"),
+			input:   "custom.csv",
+			expectedTransactions: []*domain.Transaction{
+				{
+					Amount:    domain.Money{MinorUnit: -450, Currency: "EUR"},
+					Reference: "Bakery",
+					CreatedAt: date(2025, time.March, 7),
+					BankName:  "Credit Union",
+					Status:    domain.TransactionStatusSettled,
+				},
+				{
+					Amount:    domain.Money{MinorUnit: -123450, Currency: "EUR"},
+					Reference: "Rent",
+					CreatedAt: date(2025, time.March, 8),
+					BankName:  "Credit Union",
+					Status:    domain.TransactionStatusSettled,
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			profile, err := csvfile.LoadProfile(t.Context(), test.profile)
+			require.NoError(t, err)
+
+			file, err := os.Open(filepath.Join("testdata", test.input))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = file.Close() })
+
+			transactions, err := csvfile.Read(t.Context(), file, profile)
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransactions, transactions)
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		profile     string
+		input       string
+		expectedErr string
+	}{
+		"missing columns": {
+			profile:     "barclays",
+			input:       "Date,Description\n01/03/2025,Coffee\n",
+			expectedErr: "missing columns for profile barclays: Memo, Amount, Subcategory",
+		},
+		"invalid date": {
+			profile:     "barclays",
+			input:       "Date,Memo,Amount,Subcategory\n2025-03-01,Coffee,-3.00,\n",
+			expectedErr: `line 2: parse date "2025-03-01"`,
+		},
+		"invalid amount": {
+			profile:     "barclays",
+			input:       "Date,Memo,Amount,Subcategory\n01/03/2025,Coffee,1.2.3,\n",
+			expectedErr: `line 2: parse amount "1.2.3"`,
+		},
+		"amount with thousands separator after decimal separator": {
+			profile:     "barclays",
+			input:       "Date,Memo,Amount,Subcategory\n01/03/2025,Rent,\"1.234,56\",\n",
+			expectedErr: `line 2: parse amount "1.234,56": thousands separator ',' after decimal separator '.'`,
+		},
+		"amount with decimal comma read with a decimal point": {
+			profile:     "barclays",
+			input:       "Date,Memo,Amount,Subcategory\n01/03/2025,Coffee,\"12,50\",\n",
+			expectedErr: `line 2: parse amount "12,50": thousands separator ',' not followed by three digits`,
+		},
+		"empty file": {
+			profile:     "amex",
+			expectedErr: "read header: EOF",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			profile, err := csvfile.LoadProfile(t.Context(), test.profile)
+			require.NoError(t, err)
+
+			transactions, err := csvfile.Read(t.Context(), strings.NewReader(test.input), profile)
+
+			require.Nil(t, transactions)
+			require.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads built-in profile ignoring case", func(t *testing.T) {
+		t.Parallel()
+
+		profile, err := csvfile.LoadProfile(t.Context(), "AMEX")
+
+		require.NoError(t, err)
+		require.Equal(t, "amex", profile.Name)
+		require.Equal(t, csvfile.AmountSignDebitPositive, profile.AmountSign)
+	})
+
+	t.Run("returns error for unknown profile", func(t *testing.T) {
+		t.Parallel()
+
+		profile, err := csvfile.LoadProfile(t.Context(), "lloyds")
+
+		require.Nil(t, profile)
+		require.EqualError(t, err, `unknown profile "lloyds" (built-in profiles: amex, barclays, nationwide)`)
+	})
+
+	t.Run("returns error for invalid custom profile", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "profile.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"name": "bank", "bank": "Bank", "dateColumn": "Date", "dateLayout": "2006-01-02", "referenceColumn": "Payee", "currency": "GBP"}`), 0o600))
+
+		profile, err := csvfile.LoadProfile(t.Context(), path)
+
+		require.Nil(t, profile)
+		require.EqualError(t, err, "invalid profile: creditColumn: is required without an amount column; debitColumn: is required without an amount column.")
+	})
+}
@@ -29,6 +29,23 @@ func (m Money) ToMajorUnit() float64 {
 	return float64(m.MinorUnit) / math.Pow10(currency.Fraction)
 }
 
+// MoneyFromMajorUnit converts an amount in major units to Money, rounding to the currency's smallest unit.
+// If the currency is invalid or not found, the amount is rounded to a whole number of minor units without conversion.
+// Example:
+//
+//	m := MoneyFromMajorUnit(100.5, "GBP") // Returns Money{MinorUnit: 10050, Currency: "GBP"}
+func MoneyFromMajorUnit(amount float64, currencyCode string) Money {
+	fraction := 0
+	if currency := money.GetCurrency(currencyCode); currency != nil {
+		fraction = currency.Fraction
+	}
+
+	return Money{
+		MinorUnit: int64(math.Round(amount * math.Pow10(fraction))),
+		Currency:  currencyCode,
+	}
+}
+
 // String returns a human-readable string representation of the Money amount in major units with the currency's fractional precision.
 // If the currency is invalid, it returns a string indicating the error along with the raw minor unit and currency code.
 //
@@ -49,18 +66,100 @@ func (m Money) String() string {
 	return fmt.Sprintf("%.*f", currency.Fraction, m.ToMajorUnit())
 }
 
+// TransactionStatus is the bank-independent state of a transaction.
+type TransactionStatus string
+
+const (
+	TransactionStatusSettled   TransactionStatus = "settled"
+	TransactionStatusPending   TransactionStatus = "pending"
+	TransactionStatusDeclined  TransactionStatus = "declined"
+	TransactionStatusReversed  TransactionStatus = "reversed"
+	TransactionStatusCardCheck TransactionStatus = "card_check" // Zero value authorisation used to verify a card
+)
+
+// Categories shared by exporters which map a bank's own categories, rather than passing them through. They're named
+// after Monzo's, so exports from several banks can be categorised alike.
+const (
+	CategoryBills         = "bills"
+	CategoryCharity       = "charity"
+	CategoryEatingOut     = "eating_out"
+	CategoryEntertainment = "entertainment"
+	CategoryFinances      = "finances"
+	CategoryGeneral       = "general"
+	CategoryGroceries     = "groceries"
+	CategoryHolidays      = "holidays"
+	CategoryIncome        = "income"
+	CategoryPersonalCare  = "personal_care"
+	CategoryShopping      = "shopping"
+	CategoryTransfers     = "transfers"
+	CategoryTransport     = "transport"
+)
+
 type Transaction struct {
-	Amount    Money
-	Reference string
-	Category  string
-	CreatedAt time.Time
-	IsDeposit bool   // Indicates if the transaction is a deposit (true) or withdrawal (false)
-	BankName  string // The name of the bank the transaction was exported from.
-	Notes     string
+	ID             string // The bank's identifier for the transaction, if it has one.
+	Amount         Money
+	OriginalAmount Money // The amount in the currency it was made in, when converted into Amount's currency. Zero otherwise.
+	Reference      string
+	Category       string
+	CreatedAt      time.Time
+	IsDeposit      bool   // Indicates if the transaction is a deposit (true) or withdrawal (false)
+	BankName       string // The name of the bank the transaction was exported from.
+	Account        string // The name of the account (or space) the transaction belongs to, if known.
+	Notes          string
+	Splits         []Split // Populated when the transaction is split across several categories. The amounts sum to Amount.
+	Status         TransactionStatus
+	DeclineReason  string // Why the bank declined the transaction, if known.
+}
+
+// Split is the portion of a transaction's amount assigned to a single category.
+type Split struct {
+	Category string
+	Amount   Money
 }
 
 type Account struct {
-	ID        string
-	Type      string
-	CreatedAt time.Time
+	ID            string    `json:"id"`
+	Name          string    `json:"name,omitempty"` // Human readable name or description of the account
+	Type          string    `json:"type"`
+	Currency      string    `json:"currency,omitempty"`
+	SortCode      string    `json:"sortCode,omitempty"`
+	AccountNumber string    `json:"accountNumber,omitempty"`
+	IBAN          string    `json:"iban,omitempty"`
+	BIC           string    `json:"bic,omitempty"`
+	HolderType    string    `json:"holderType,omitempty"` // Who holds the account, e.g. individual, joint or business
+	Owners        []string  `json:"owners,omitempty"`
+	Closed        bool      `json:"closed"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Spaces        []*Space  `json:"spaces,omitempty"`
+}
+
+// Space is a sub-account that holds money separately from the main balance, e.g. a savings goal or spending space.
+type Space struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	State   string `json:"state,omitempty"`
+	Balance Money  `json:"balance"`
+}
+
+// RecurringPaymentType is how a recurring payment is collected.
+type RecurringPaymentType string
+
+const (
+	RecurringPaymentTypeStandingOrder RecurringPaymentType = "standing_order" // Pushed by the account holder
+	RecurringPaymentTypeDirectDebit   RecurringPaymentType = "direct_debit"   // Pulled by the payee under a mandate
+)
+
+// RecurringPayment is a scheduled outgoing payment from an account.
+type RecurringPayment struct {
+	ID              string               `json:"id"`
+	Type            RecurringPaymentType `json:"type"`
+	Payee           string               `json:"payee"`
+	Reference       string               `json:"reference"`
+	Amount          Money                `json:"amount"`                    // The scheduled amount, or the last amount paid when it varies (direct debits)
+	Frequency       string               `json:"frequency,omitempty"`       // e.g. monthly, every 2 weeks. Empty when unknown (direct debits)
+	NextPaymentDate *time.Time           `json:"nextPaymentDate,omitempty"` // Empty when unknown (direct debits)
+	LastPaymentDate *time.Time           `json:"lastPaymentDate,omitempty"`
+	Category        string               `json:"category,omitempty"`
+	BankName        string               `json:"bankName"`
 }
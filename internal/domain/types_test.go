@@ -29,6 +29,42 @@ func TestToMajorUnit(t *testing.T) {
 	}
 }
 
+func TestMoneyFromMajorUnit(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		amount   float64
+		currency string
+		expected domain.Money
+	}{
+		"converts GBP": {
+			amount:   250.25,
+			currency: "GBP",
+			expected: domain.Money{MinorUnit: 25025, Currency: "GBP"},
+		},
+		"rounds to the smallest unit": {
+			amount:   0.1 + 0.2,
+			currency: "GBP",
+			expected: domain.Money{MinorUnit: 30, Currency: "GBP"},
+		},
+		"converts JPY": {
+			amount:   1245,
+			currency: "JPY",
+			expected: domain.Money{MinorUnit: 1245, Currency: "JPY"},
+		},
+		"returns whole amount when nil currency": {
+			amount:   12.45,
+			expected: domain.Money{MinorUnit: 12},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, test.expected, domain.MoneyFromMajorUnit(test.amount, test.currency))
+		})
+	}
+}
+
 func TestCurrencyFormat(t *testing.T) {
 	t.Parallel()
 
@@ -3,11 +3,15 @@ package export
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/samber/lo"
 )
 
 type AccountOptions struct {
+	Type          string // Only include accounts whose type contains this value (case-insensitive), e.g. "joint"
+	IncludeClosed bool   // Include closed accounts, which are excluded by default
 	Options
 }
 
@@ -34,5 +38,17 @@ func Accounts(ctx context.Context, exportType ExportType, opts AccountOptions) (
 		return nil, fmt.Errorf("transctions: %w", err)
 	}
 
-	return accounts, nil
+	return filterAccounts(accounts, opts), nil
+}
+
+func filterAccounts(accounts []*domain.Account, opts AccountOptions) []*domain.Account {
+	accountType := strings.ToLower(strings.TrimSpace(opts.Type))
+
+	return lo.Filter(accounts, func(account *domain.Account, _ int) bool {
+		if account.Closed && !opts.IncludeClosed {
+			return false
+		}
+
+		return accountType == "" || strings.Contains(strings.ToLower(account.Type), accountType)
+	})
 }
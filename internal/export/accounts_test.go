@@ -12,7 +12,9 @@ import (
 func TestAccounts(t *testing.T) {
 	t.Parallel()
 
-	export.Register(ExportTypeStub, func(opts export.Options) (export.Exporter, error) {
+	// Registered separately from ExportTypeStub, which other parallel tests register with different accounts
+	exportType := export.ExportType("stubaccounts")
+	export.Register(exportType, func(opts export.Options) (export.Exporter, error) {
 		if opts.AuthToken == "12345" {
 			return nil, errors.New("invalid auth token")
 		}
@@ -22,7 +24,9 @@ func TestAccounts(t *testing.T) {
 				{},
 			},
 			accounts: []*domain.Account{
-				{},
+				{Type: "uk_retail"},
+				{Type: "uk_retail_joint"},
+				{Type: "uk_retail", Closed: true},
 			},
 		}, nil
 	})
@@ -38,8 +42,26 @@ func TestAccounts(t *testing.T) {
 					AuthToken: "token",
 				},
 			},
+			expectedAccountsLen: 2,
+		},
+		"filters by type": {
+			opts: export.AccountOptions{
+				Type: "JOINT",
+				Options: export.Options{
+					AuthToken: "token",
+				},
+			},
 			expectedAccountsLen: 1,
 		},
+		"includes closed accounts": {
+			opts: export.AccountOptions{
+				IncludeClosed: true,
+				Options: export.Options{
+					AuthToken: "token",
+				},
+			},
+			expectedAccountsLen: 3,
+		},
 		"returns error when invalid token": {
 			opts:           export.AccountOptions{},
 			expectedErrMsg: "invalid options: AuthToken: is required.",
@@ -57,7 +79,7 @@ func TestAccounts(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 			t.Parallel()
 
-			accounts, err := export.Accounts(t.Context(), ExportTypeStub, test.opts)
+			accounts, err := export.Accounts(t.Context(), exportType, test.opts)
 
 			if test.expectedErrMsg != "" {
 				require.Nil(t, accounts)
@@ -0,0 +1,42 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/HallyG/fingrab/internal/domain"
+)
+
+// ErrClosingBalanceUnsupported is returned when the exporter cannot report a closing balance.
+var ErrClosingBalanceUnsupported = errors.New("closing balance is not supported")
+
+// ClosingBalanceExporter is implemented by exporters that can report an account's balance at the end of an export's
+// date range, so an export can be reconciled against the bank.
+type ClosingBalanceExporter interface {
+	ExportClosingBalance(ctx context.Context, opts TransactionOptions) (domain.Money, error)
+}
+
+// ClosingBalance returns the balance of the account (or space) selected by opts at opts.EndDate.
+func ClosingBalance(ctx context.Context, exportType ExportType, opts TransactionOptions) (domain.Money, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return domain.Money{}, fmt.Errorf("invalid options: %w", err)
+	}
+
+	exporter, err := NewExporter(exportType, opts.Options)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("exporter: %w", err)
+	}
+
+	balanceExporter, ok := exporter.(ClosingBalanceExporter)
+	if !ok {
+		return domain.Money{}, fmt.Errorf("%w by %s", ErrClosingBalanceUnsupported, exportType)
+	}
+
+	balance, err := balanceExporter.ExportClosingBalance(ctx, opts)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("closing balance: %w", err)
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,74 @@
+package export_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/stretchr/testify/require"
+)
+
+const ExportTypeStubBalance export.ExportType = "stubbalance"
+
+var _ export.ClosingBalanceExporter = (*StubBalanceExporter)(nil)
+
+type StubBalanceExporter struct {
+	StubExporter
+	balance domain.Money
+}
+
+func (s *StubBalanceExporter) ExportClosingBalance(ctx context.Context, opts export.TransactionOptions) (domain.Money, error) {
+	return s.balance, s.err
+}
+
+func TestClosingBalance(t *testing.T) {
+	t.Parallel()
+
+	export.Register(ExportTypeStubBalance, func(opts export.Options) (export.Exporter, error) {
+		return &StubBalanceExporter{
+			balance: domain.Money{MinorUnit: 1234, Currency: "GBP"},
+		}, nil
+	})
+
+	opts := export.TransactionOptions{
+		EndDate:   time.Now(),
+		StartDate: time.Now(),
+		Options: export.Options{
+			AuthToken: "token",
+		},
+	}
+
+	t.Run("returns closing balance", func(t *testing.T) {
+		t.Parallel()
+
+		balance, err := export.ClosingBalance(t.Context(), ExportTypeStubBalance, opts)
+
+		require.NoError(t, err)
+		require.Equal(t, domain.Money{MinorUnit: 1234, Currency: "GBP"}, balance)
+	})
+
+	t.Run("returns error when exporter does not support closing balances", func(t *testing.T) {
+		t.Parallel()
+
+		exportType := export.ExportType("stubnobalance")
+		export.Register(exportType, func(opts export.Options) (export.Exporter, error) {
+			return &StubExporter{}, nil
+		})
+
+		balance, err := export.ClosingBalance(t.Context(), exportType, opts)
+
+		require.Empty(t, balance)
+		require.ErrorIs(t, err, export.ErrClosingBalanceUnsupported)
+	})
+
+	t.Run("returns error when invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		balance, err := export.ClosingBalance(t.Context(), ExportTypeStubBalance, export.TransactionOptions{})
+
+		require.Empty(t, balance)
+		require.ErrorContains(t, err, "invalid options")
+	})
+}
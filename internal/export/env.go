@@ -0,0 +1,26 @@
+package export
+
+import (
+	"os"
+	"strings"
+)
+
+// Suffixes of the environment variables shared by exporters, which are named after their prefix, e.g. MONZO_TOKEN.
+const (
+	EnvTokenSuffix        = "_TOKEN"
+	EnvClientIDSuffix     = "_CLIENT_ID"
+	EnvClientSecretSuffix = "_CLIENT_SECRET"
+	EnvBankNameSuffix     = "_BANK_NAME"
+	EnvEnvironmentSuffix  = "_ENV"
+)
+
+// EnvVarName returns the name of the export type's environment variable with the suffix, e.g. MONZO_TOKEN.
+func EnvVarName(exportType ExportType, suffix string) string {
+	metadata, _ := Lookup(exportType)
+	return metadata.EnvPrefix + suffix
+}
+
+// Getenv returns the value of the export type's environment variable with the suffix, without surrounding whitespace.
+func Getenv(exportType ExportType, suffix string) string {
+	return strings.TrimSpace(os.Getenv(EnvVarName(exportType, suffix)))
+}
@@ -0,0 +1,46 @@
+package export_test
+
+import (
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVarName(t *testing.T) {
+	t.Parallel()
+
+	export.Register("env-configured", func(opts export.Options) (export.Exporter, error) {
+		return &StubExporter{}, nil
+	},
+		export.WithEnvPrefix("ENV_CONFIGURED_BANK"),
+	)
+
+	tests := map[string]struct {
+		exportType   export.ExportType
+		expectedName string
+	}{
+		"uses the registered prefix": {
+			exportType:   "env-configured",
+			expectedName: "ENV_CONFIGURED_BANK_TOKEN",
+		},
+		"uses the default prefix of an unregistered type": {
+			exportType:   "env-missing",
+			expectedName: "ENV_MISSING_TOKEN",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.expectedName, export.EnvVarName(test.exportType, export.EnvTokenSuffix))
+		})
+	}
+}
+
+func TestGetenv(t *testing.T) {
+	t.Setenv("ENV_GETENV_BANK_NAME", "  Example Bank \n")
+
+	require.Equal(t, "Example Bank", export.Getenv("env-getenv", export.EnvBankNameSuffix))
+	require.Empty(t, export.Getenv("env-getenv", "_UNSET"))
+}
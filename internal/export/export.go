@@ -20,6 +20,8 @@ type (
 		// MaxDateRange returns the maximum allowed date range for fetching transactions.
 		// A zero duration indicates no limit.
 		MaxDateRange() time.Duration
+		// Capabilities describes what the exporter supports.
+		Capabilities() Capabilities
 		ExportTransactions(ctx context.Context, opts TransactionOptions) ([]*domain.Transaction, error)
 		ExportAccounts(ctx context.Context, opts AccountOptions) ([]*domain.Account, error)
 	}
@@ -28,6 +30,9 @@ type (
 type Options struct {
 	AuthToken string
 	Timeout   time.Duration
+	DataDir   string // Directory used by exporters to persist local state. Empty disables persistence.
+	Input     string // File read by exporters without an API, e.g. a statement CSV.
+	Profile   string // Name or path of the profile describing Input's layout.
 }
 
 func (o Options) Validate(ctx context.Context) error {
@@ -51,30 +56,48 @@ func (o Options) BearerAuthToken() string {
 	return token
 }
 
+type registration struct {
+	constructor ExporterConstructor
+	metadata    Metadata
+}
+
 var (
-	registry     = make(map[ExportType]ExporterConstructor)
+	registry     = make(map[ExportType]registration)
 	registryLock = sync.RWMutex{}
 )
 
-// Register adds a new exporter constructor to the registry for the given export type.
+// Register adds a new exporter constructor to the registry for the given export type, along with the metadata the
+// CLI is built from (see Metadata's defaults).
 // It is thread-safe and overwrites any existing constructor for the same ExportType.
-func Register(exportType ExportType, constructor ExporterConstructor) {
+func Register(exportType ExportType, constructor ExporterConstructor, opts ...RegisterOption) {
+	metadata := defaultMetadata(exportType)
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(&metadata)
+	}
+
 	registryLock.Lock()
 	defer registryLock.Unlock()
 
-	registry[exportType] = constructor
+	registry[exportType] = registration{
+		constructor: constructor,
+		metadata:    metadata,
+	}
 }
 
 func NewExporter(exportType ExportType, opts Options) (Exporter, error) {
 	registryLock.RLock()
 	defer registryLock.RUnlock()
 
-	constructor, exists := registry[exportType]
+	registration, exists := registry[exportType]
 	if !exists {
 		return nil, fmt.Errorf("unsupported type: %s", exportType)
 	}
 
-	exporter, err := constructor(opts)
+	exporter, err := registration.constructor(opts)
 	if err != nil {
 		return nil, fmt.Errorf("constructor: %w", err)
 	}
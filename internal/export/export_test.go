@@ -85,6 +85,94 @@ func TestNewExporter(t *testing.T) {
 	})
 }
 
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	constructor := func(opts export.Options) (export.Exporter, error) {
+		return &StubExporter{}, nil
+	}
+
+	export.Register("Lookup-Defaults", constructor)
+	export.Register("lookup-configured", constructor,
+		export.WithDisplayName("Configured Bank"),
+		export.WithCommand("configured"),
+		export.WithEnvPrefix("CONFIGURED"),
+		export.WithDescription("Commands for the configured bank"),
+		export.WithTokenHelp("create a token in the app"),
+		export.WithCapabilities(export.Capabilities{Accounts: true, Transactions: true}),
+	)
+
+	tests := map[string]struct {
+		exportType         export.ExportType
+		expectedMetadata   export.Metadata
+		expectedRegistered bool
+	}{
+		"returns defaults derived from the export type": {
+			exportType: "Lookup-Defaults",
+			expectedMetadata: export.Metadata{
+				DisplayName: "Lookup-Defaults",
+				Command:     "lookup-defaults",
+				EnvPrefix:   "LOOKUP_DEFAULTS",
+			},
+			expectedRegistered: true,
+		},
+		"returns registered metadata": {
+			exportType: "lookup-configured",
+			expectedMetadata: export.Metadata{
+				DisplayName:  "Configured Bank",
+				Command:      "configured",
+				EnvPrefix:    "CONFIGURED",
+				Description:  "Commands for the configured bank",
+				TokenHelp:    "create a token in the app",
+				Capabilities: export.Capabilities{Accounts: true, Transactions: true},
+			},
+			expectedRegistered: true,
+		},
+		"returns defaults for unregistered type": {
+			exportType: "lookup-missing",
+			expectedMetadata: export.Metadata{
+				DisplayName: "lookup-missing",
+				Command:     "lookup-missing",
+				EnvPrefix:   "LOOKUP_MISSING",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			metadata, registered := export.Lookup(test.exportType)
+
+			require.Equal(t, test.expectedRegistered, registered)
+			require.Equal(t, test.expectedMetadata, metadata)
+		})
+	}
+}
+
+func TestCapabilitiesNames(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		capabilities  export.Capabilities
+		expectedNames []string
+	}{
+		"returns supported capabilities in order": {
+			capabilities:  export.Capabilities{Cursors: true, Accounts: true, WriteBack: true, Transactions: true},
+			expectedNames: []string{"accounts", "transactions", "write-back", "cursors"},
+		},
+		"returns empty when nothing is supported": {
+			expectedNames: []string{},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.expectedNames, test.capabilities.Names())
+		})
+	}
+}
+
 const ExportTypeStub export.ExportType = "stubtype"
 
 var _ export.Exporter = (*StubExporter)(nil)
@@ -92,6 +180,7 @@ var _ export.Exporter = (*StubExporter)(nil)
 type StubExporter struct {
 	transactions []*domain.Transaction
 	accounts     []*domain.Account
+	capabilities export.Capabilities
 	err          error
 }
 
@@ -103,6 +192,10 @@ func (s *StubExporter) MaxDateRange() time.Duration {
 	return 24 * time.Hour
 }
 
+func (s *StubExporter) Capabilities() export.Capabilities {
+	return s.capabilities
+}
+
 func (s *StubExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
 	return s.accounts, s.err
 }
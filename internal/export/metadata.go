@@ -0,0 +1,148 @@
+package export
+
+import (
+	"context"
+	"strings"
+
+	"github.com/HallyG/fingrab/internal/oauth"
+)
+
+// Metadata describes a registered exporter, so its command, help text and authentication can be built from the
+// registry rather than per bank.
+type Metadata struct {
+	DisplayName string // Human readable name, e.g. Open Banking UK. Defaults to the export type.
+	Command     string // Name of the exporter's command. Defaults to the lower-cased export type.
+	EnvPrefix   string // Prefix of the exporter's environment variables, e.g. MONZO for MONZO_TOKEN. Defaults to the upper-cased export type.
+	Description string // Long help of the exporter's command.
+	// OAuth returns the OAuth2 flow used to get a token when none is given. Nil when the exporter has none.
+	OAuth func() oauth.Config
+	// TokenSource gets a token when none is given instead of an OAuth2 flow, e.g. by exchanging other credentials.
+	// It's given the command's options, without a token, e.g. for its timeout.
+	TokenSource func(ctx context.Context, opts Options) (string, error)
+	// TokenHelp explains how to get a token, for exporters with no way of getting one themselves, e.g. "create a
+	// personal API token in the bank's settings".
+	TokenHelp string
+	// Capabilities are those returned by the exporter's Capabilities method, registered so they're known without
+	// constructing one, which needs a token.
+	Capabilities Capabilities
+}
+
+// Capabilities describe what an exporter supports, so only the commands and options which work are offered.
+type Capabilities struct {
+	Accounts     bool `json:"accounts"`     // Lists accounts
+	Transactions bool `json:"transactions"` // Exports transactions between dates
+	// File is set for exporters which read a file (Options.Input) rather than a bank's API. They have no token, so
+	// have their own commands rather than the API's.
+	File      bool `json:"file"`
+	Balances  bool `json:"balances"`  // Reports an account's closing balance, see ClosingBalanceExporter
+	Spaces    bool `json:"spaces"`    // Exports the transactions of a space (TransactionOptions.Space), e.g. a savings goal
+	Pending   bool `json:"pending"`   // Exports transactions which haven't settled yet, with a pending status
+	Declines  bool `json:"declines"`  // Exports declined transactions, with their reason, when auditing
+	Webhooks  bool `json:"webhooks"`  // Receives transactions as the bank pushes them
+	WriteBack bool `json:"writeBack"` // Writes notes or categories back to the bank
+	Cursors   bool `json:"cursors"`   // Exports only the transactions changed since the last sync
+}
+
+// Names returns the names of the supported capabilities, e.g. for listing them.
+func (c Capabilities) Names() []string {
+	capabilities := []struct {
+		name      string
+		supported bool
+	}{
+		{"accounts", c.Accounts},
+		{"transactions", c.Transactions},
+		{"file", c.File},
+		{"balances", c.Balances},
+		{"spaces", c.Spaces},
+		{"pending", c.Pending},
+		{"declines", c.Declines},
+		{"webhooks", c.Webhooks},
+		{"write-back", c.WriteBack},
+		{"cursors", c.Cursors},
+	}
+
+	names := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		if capability.supported {
+			names = append(names, capability.name)
+		}
+	}
+
+	return names
+}
+
+type RegisterOption func(*Metadata)
+
+func WithDisplayName(name string) RegisterOption {
+	return func(m *Metadata) {
+		m.DisplayName = name
+	}
+}
+
+func WithCommand(command string) RegisterOption {
+	return func(m *Metadata) {
+		m.Command = command
+	}
+}
+
+func WithEnvPrefix(prefix string) RegisterOption {
+	return func(m *Metadata) {
+		m.EnvPrefix = prefix
+	}
+}
+
+func WithDescription(description string) RegisterOption {
+	return func(m *Metadata) {
+		m.Description = description
+	}
+}
+
+// WithOAuth configures the OAuth2 flow used to get a token when none is given. The config is fetched when it's
+// needed, so it can depend on the environment.
+func WithOAuth(config func() oauth.Config) RegisterOption {
+	return func(m *Metadata) {
+		m.OAuth = config
+	}
+}
+
+func WithTokenSource(source func(ctx context.Context, opts Options) (string, error)) RegisterOption {
+	return func(m *Metadata) {
+		m.TokenSource = source
+	}
+}
+
+func WithTokenHelp(help string) RegisterOption {
+	return func(m *Metadata) {
+		m.TokenHelp = help
+	}
+}
+
+func WithCapabilities(capabilities Capabilities) RegisterOption {
+	return func(m *Metadata) {
+		m.Capabilities = capabilities
+	}
+}
+
+// Lookup returns the metadata of the registered export type. The defaults are returned for unregistered types.
+func Lookup(exportType ExportType) (Metadata, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	registration, exists := registry[exportType]
+	if !exists {
+		return defaultMetadata(exportType), false
+	}
+
+	return registration.metadata, true
+}
+
+func defaultMetadata(exportType ExportType) Metadata {
+	name := string(exportType)
+
+	return Metadata{
+		DisplayName: name,
+		Command:     strings.ToLower(name),
+		// Dashes are valid in commands but not environment variable names
+		EnvPrefix: strings.ToUpper(strings.ReplaceAll(name, "-", "_")),
+	}
+}
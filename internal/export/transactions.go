@@ -9,10 +9,15 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 )
 
+// AllAccounts is the AccountID that selects every account (and space), for exporters that support it.
+const AllAccounts = "all"
+
 type TransactionOptions struct {
 	AccountID string
 	EndDate   time.Time
 	StartDate time.Time
+	Audit     bool   // Include the transactions excluded by default: declined ones, and reversed ones and card checks where the bank reports them.
+	Space     string // Export the transactions of a space (by ID or name) instead of the account's main balance.
 	Options
 }
 
@@ -48,6 +53,10 @@ func Transactions(ctx context.Context, exportType ExportType, opts TransactionOp
 		return nil, fmt.Errorf("exporter: %w", err)
 	}
 
+	if opts.Space != "" && !exporter.Capabilities().Spaces {
+		return nil, fmt.Errorf("spaces are not supported by %s", exportType)
+	}
+
 	maxDateRange := exporter.MaxDateRange()
 	days := (opts.EndDate.Sub(opts.StartDate).Hours()) / 24
 	if maxDateRange > 0 && opts.EndDate.Sub(opts.StartDate) > maxDateRange {
@@ -88,6 +88,17 @@ func TestTransactions(t *testing.T) {
 			},
 			expectedErrMsg: "date range 2 days is too long, max is 1 days",
 		},
+		"returns error when spaces are unsupported": {
+			opts: export.TransactionOptions{
+				EndDate:   time.Now(),
+				StartDate: time.Now(),
+				Space:     "holiday",
+				Options: export.Options{
+					AuthToken: "token",
+				},
+			},
+			expectedErrMsg: "spaces are not supported by stubtype",
+		},
 	}
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
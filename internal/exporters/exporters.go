@@ -0,0 +1,15 @@
+// Package exporters imports every built-in exporter, each of which registers itself, with its environment variables
+// and OAuth2 flow, when imported. A new exporter is added to this list, which gives it a command.
+package exporters
+
+import (
+	_ "github.com/HallyG/fingrab/internal/csvfile/exporter"
+	_ "github.com/HallyG/fingrab/internal/gocardless/exporter"
+	_ "github.com/HallyG/fingrab/internal/monzo/exporter"
+	_ "github.com/HallyG/fingrab/internal/ofx/exporter"
+	_ "github.com/HallyG/fingrab/internal/openbanking/exporter"
+	_ "github.com/HallyG/fingrab/internal/plaid/exporter"
+	_ "github.com/HallyG/fingrab/internal/starling/exporter"
+	_ "github.com/HallyG/fingrab/internal/truelayer/exporter"
+	_ "github.com/HallyG/fingrab/internal/wise/exporter"
+)
@@ -0,0 +1,56 @@
+package format
+
+import (
+	"io"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+)
+
+const (
+	detailedTimeFormat            = time.RFC3339
+	FormatTypeDetailed FormatType = "detailed"
+)
+
+func init() {
+	register(FormatTypeDetailed, func(w io.Writer, location *time.Location) (Formatter, error) {
+		return &DetailedFormatter{
+			CSVFormatter: NewCSVFormatter(w),
+			location:     location,
+		}, nil
+	})
+}
+
+// DetailedFormatter formats transactions as CSV with every exported field, including the transaction's status,
+// decline reason and original amount. It's intended for auditing rather than importing into another application.
+type DetailedFormatter struct {
+	*CSVFormatter
+	location *time.Location
+}
+
+func (d *DetailedFormatter) WriteHeader() error {
+	return d.writer.Write([]string{"id", "date", "bank", "account", "reference", "category", "amount", "currency", "status", "decline reason", "notes", "original amount", "original currency"})
+}
+
+func (d *DetailedFormatter) WriteTransaction(t *domain.Transaction) error {
+	originalAmount := ""
+	if t.OriginalAmount.Currency != "" {
+		originalAmount = t.OriginalAmount.String()
+	}
+
+	return d.writer.Write([]string{
+		t.ID,
+		t.CreatedAt.In(d.location).Format(detailedTimeFormat),
+		t.BankName,
+		t.Account,
+		t.Reference,
+		t.Category,
+		t.Amount.String(),
+		t.Amount.Currency,
+		string(t.Status),
+		t.DeclineReason,
+		t.Notes,
+		originalAmount,
+		t.OriginalAmount.Currency,
+	})
+}
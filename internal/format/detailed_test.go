@@ -0,0 +1,74 @@
+package format_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetailedFormatter(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (format.Formatter, *bytes.Buffer) {
+		t.Helper()
+		buffer := bytes.NewBuffer(nil)
+		formatter, err := format.NewFormatter(format.FormatTypeDetailed, buffer)
+		require.NoError(t, err)
+
+		return formatter, buffer
+	}
+
+	t.Run("writes CSV data with status, decline reason and original amount", func(t *testing.T) {
+		t.Parallel()
+
+		now, err := time.Parse(time.RFC3339, "2025-04-16T10:30:00Z")
+		require.NoError(t, err)
+
+		formatter, buffer := setup(t)
+
+		err = format.WriteCollection(formatter, []*domain.Transaction{
+			{
+				ID:            "tx_1",
+				CreatedAt:     now,
+				BankName:      "Monzo",
+				Account:       "Personal",
+				Reference:     "Netflix",
+				Category:      "entertainment",
+				Amount:        domain.Money{MinorUnit: -1099, Currency: "GBP"},
+				Status:        domain.TransactionStatusDeclined,
+				DeclineReason: "INSUFFICIENT_FUNDS",
+			},
+			{
+				ID:             "tx_3",
+				CreatedAt:      now,
+				BankName:       "Wise",
+				Account:        "EUR",
+				Reference:      "Starbucks",
+				Amount:         domain.Money{MinorUnit: -920, Currency: "EUR"},
+				OriginalAmount: domain.Money{MinorUnit: -1000, Currency: "USD"},
+				Status:         domain.TransactionStatusSettled,
+			},
+			{
+				ID:        "tx_2",
+				CreatedAt: now,
+				BankName:  "Monzo",
+				Reference: "Salary",
+				Amount:    domain.Money{MinorUnit: 250000, Currency: "GBP"},
+				Status:    domain.TransactionStatusSettled,
+				Notes:     "April",
+			},
+		})
+		require.NoError(t, err)
+
+		expected := `id,date,bank,account,reference,category,amount,currency,status,decline reason,notes,original amount,original currency
+tx_1,2025-04-16T10:30:00Z,Monzo,Personal,Netflix,entertainment,-10.99,GBP,declined,INSUFFICIENT_FUNDS,,,
+tx_3,2025-04-16T10:30:00Z,Wise,EUR,Starbucks,,-9.20,EUR,settled,,,-10.00,USD
+tx_2,2025-04-16T10:30:00Z,Monzo,,Salary,,2500.00,GBP,settled,,April,,
+`
+		require.Equal(t, expected, buffer.String())
+	})
+}
@@ -20,8 +20,8 @@ func TestAll(t *testing.T) {
 
 		formats := format.All()
 
-		require.Len(t, formats, 2)
-		require.Equal(t, []format.FormatType{format.FormatTypeMoneyDance, format.FormatTypeYNAB}, formats)
+		require.Len(t, formats, 5)
+		require.Equal(t, []format.FormatType{format.FormatTypeDetailed, format.FormatTypeLedger, format.FormatTypeMoneyDance, format.FormatTypeQIF, format.FormatTypeYNAB}, formats)
 	})
 }
 
@@ -109,6 +109,23 @@ func testTransactions(t *testing.T, now time.Time) []*domain.Transaction {
 	}
 }
 
+func testSplitTransaction(t *testing.T, now time.Time) *domain.Transaction {
+	t.Helper()
+
+	return &domain.Transaction{
+		CreatedAt: now,
+		Reference: "Supermarket",
+		Category:  "Groceries",
+		Amount:    domain.Money{MinorUnit: -2000, Currency: "GBP"},
+		Notes:     "Weekly shop",
+		BankName:  "Monzo",
+		Splits: []domain.Split{
+			{Category: "Groceries", Amount: domain.Money{MinorUnit: -1500, Currency: "GBP"}},
+			{Category: "Household", Amount: domain.Money{MinorUnit: -500, Currency: "GBP"}},
+		},
+	}
+}
+
 var _ format.Formatter = (*StubFormatter)(nil)
 
 type StubFormatter struct {
@@ -0,0 +1,103 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/samber/lo"
+)
+
+const (
+	ledgerTimeFormat                = "2006-01-02" // date format expected by ledger and hledger (YYYY-MM-DD)
+	ledgerDefaultAccount            = "Bank"
+	ledgerUncategorised             = "Uncategorised"
+	FormatTypeLedger     FormatType = "ledger"
+)
+
+func init() {
+	register(FormatTypeLedger, func(w io.Writer, location *time.Location) (Formatter, error) {
+		return &LedgerFormatter{
+			TextFormatter: NewTextFormatter(w),
+			location:      location,
+		}, nil
+	})
+}
+
+// LedgerFormatter formats transactions as plain text accounting journal entries (ledger, hledger).
+// Each transaction posts its amount to Assets:<bank>:<account> (or Assets:<bank> when the account isn't known) and
+// balances it against an Income or Expenses category account, with one posting per category for split transactions.
+type LedgerFormatter struct {
+	*TextFormatter
+	location *time.Location
+}
+
+func (l *LedgerFormatter) WriteHeader() error {
+	return nil
+}
+
+func (l *LedgerFormatter) WriteTransaction(t *domain.Transaction) error {
+	splits := t.Splits
+	if len(splits) == 0 {
+		splits = []domain.Split{{Category: t.Category, Amount: t.Amount}}
+	}
+
+	// An entry whose postings don't balance is rejected by ledger, so is better not written at all
+	total := lo.SumBy(splits, func(split domain.Split) int64 { return split.Amount.MinorUnit })
+	if total != t.Amount.MinorUnit {
+		return fmt.Errorf("transaction %s: splits sum to %s, not its amount %s", t.ID, domain.Money{MinorUnit: total, Currency: t.Amount.Currency}.String(), t.Amount.String())
+	}
+
+	if _, err := fmt.Fprintf(l.writer, "%s %s\n", t.CreatedAt.In(l.location).Format(ledgerTimeFormat), t.Reference); err != nil {
+		return err
+	}
+
+	if t.Notes != "" {
+		if _, err := fmt.Fprintf(l.writer, "    ; %s\n", t.Notes); err != nil {
+			return err
+		}
+	}
+
+	for _, split := range splits {
+		amount := domain.Money{MinorUnit: -split.Amount.MinorUnit, Currency: split.Amount.Currency}
+		if err := l.writePosting(ledgerCategoryAccount(split), amount); err != nil {
+			return err
+		}
+	}
+
+	bankName := t.BankName
+	if bankName == "" {
+		bankName = ledgerDefaultAccount
+	}
+
+	account := "Assets:" + bankName
+	if t.Account != "" {
+		account += ":" + t.Account
+	}
+
+	if err := l.writePosting(account, t.Amount); err != nil {
+		return err
+	}
+
+	_, err := l.writer.WriteString("\n")
+	return err
+}
+
+func (l *LedgerFormatter) writePosting(account string, amount domain.Money) error {
+	_, err := fmt.Fprintf(l.writer, "    %-40s  %s %s\n", account, amount.String(), amount.Currency)
+	return err
+}
+
+func ledgerCategoryAccount(split domain.Split) string {
+	category := split.Category
+	if category == "" {
+		category = ledgerUncategorised
+	}
+
+	if split.Amount.MinorUnit > 0 {
+		return "Income:" + category
+	}
+
+	return "Expenses:" + category
+}
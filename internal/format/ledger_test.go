@@ -0,0 +1,102 @@
+package format_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerFormatter(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (format.Formatter, *bytes.Buffer) {
+		t.Helper()
+		buffer := bytes.NewBuffer(nil)
+		formatter, err := format.NewFormatter(format.FormatTypeLedger, buffer)
+		require.NoError(t, err)
+
+		return formatter, buffer
+	}
+
+	now, err := time.Parse("2006-01-02", "2025-04-16")
+	require.NoError(t, err)
+
+	t.Run("writes journal entries", func(t *testing.T) {
+		t.Parallel()
+
+		formatter, buffer := setup(t)
+
+		err := format.WriteCollection(formatter, testTransactions(t, now)[:2])
+		require.NoError(t, err)
+
+		expected := `2025-04-16 Test Transaction
+    ; Test Notes
+    Income:Test Category                      -123.45 GBP
+    Assets:Bank                               123.45 GBP
+
+2025-04-16 Another Test Transaction
+    ; More notes
+    Expenses:Another Test Category            123.45 GBP
+    Assets:Bank                               -123.45 GBP
+
+`
+		require.Equal(t, expected, buffer.String())
+	})
+
+	t.Run("writes a posting per split", func(t *testing.T) {
+		t.Parallel()
+
+		formatter, buffer := setup(t)
+
+		err := format.WriteCollection(formatter, []*domain.Transaction{testSplitTransaction(t, now)})
+		require.NoError(t, err)
+
+		expected := `2025-04-16 Supermarket
+    ; Weekly shop
+    Expenses:Groceries                        15.00 GBP
+    Expenses:Household                        5.00 GBP
+    Assets:Monzo                              -20.00 GBP
+
+`
+		require.Equal(t, expected, buffer.String())
+	})
+	t.Run("posts to the transaction's account", func(t *testing.T) {
+		t.Parallel()
+
+		formatter, buffer := setup(t)
+
+		transaction := testSplitTransaction(t, now)
+		transaction.Splits = nil
+		transaction.Account = "Joint"
+
+		err := format.WriteCollection(formatter, []*domain.Transaction{transaction})
+		require.NoError(t, err)
+
+		expected := `2025-04-16 Supermarket
+    ; Weekly shop
+    Expenses:Groceries                        20.00 GBP
+    Assets:Monzo:Joint                        -20.00 GBP
+
+`
+		require.Equal(t, expected, buffer.String())
+	})
+
+	t.Run("returns error when splits don't sum to the amount", func(t *testing.T) {
+		t.Parallel()
+
+		formatter, buffer := setup(t)
+
+		transaction := testSplitTransaction(t, now)
+		transaction.ID = "txn-1"
+		transaction.Splits = transaction.Splits[:1]
+
+		err := formatter.WriteTransaction(transaction)
+
+		require.EqualError(t, err, "transaction txn-1: splits sum to -15.00, not its amount -20.00")
+		require.Empty(t, buffer.String())
+	})
+}
@@ -1,7 +1,9 @@
 package format
 
 import (
+	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"github.com/HallyG/fingrab/internal/domain"
@@ -24,6 +26,7 @@ func init() {
 // MoneyDanceFormatter formats transactions for import into MoneyDance.
 // It outputs CSV with columns: check number, date, description, category, amount, memo.
 // Transactions are marked as "Trn" and deposits as "Dep" in the check number field.
+// Split transactions are written as one row per category, with the split noted in the memo.
 type MoneyDanceFormatter struct {
 	*CSVFormatter
 	location *time.Location
@@ -39,12 +42,33 @@ func (m *MoneyDanceFormatter) WriteTransaction(t *domain.Transaction) error {
 		checkNumber = "Dep"
 	}
 
-	return m.writer.Write([]string{
-		checkNumber,
-		t.CreatedAt.In(m.location).Format(moneyDanceTimeFormat),
-		t.Reference,
-		t.Category,
-		t.Amount.String(),
-		t.Notes,
-	})
+	date := t.CreatedAt.In(m.location).Format(moneyDanceTimeFormat)
+
+	if len(t.Splits) == 0 {
+		return m.writer.Write([]string{
+			checkNumber,
+			date,
+			t.Reference,
+			t.Category,
+			t.Amount.String(),
+			t.Notes,
+		})
+	}
+
+	for i, split := range t.Splits {
+		memo := strings.TrimSpace(fmt.Sprintf("%s (split %d of %d)", t.Notes, i+1, len(t.Splits)))
+
+		if err := m.writer.Write([]string{
+			checkNumber,
+			date,
+			t.Reference,
+			split.Category,
+			split.Amount.String(),
+			memo,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
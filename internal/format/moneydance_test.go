@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/HallyG/fingrab/internal/domain"
 	"github.com/HallyG/fingrab/internal/format"
 	"github.com/stretchr/testify/require"
 )
@@ -36,6 +37,24 @@ func TestMoneyDanceFormatter(t *testing.T) {
 Dep,2025-04-16,Test Transaction,Test Category,123.45,Test Notes
 Trn,2025-04-16,Another Test Transaction,Another Test Category,-123.45,More notes
 Trn,2025-05-04,Transaction With Date Affected By Timezone,Test Category,-1.00,Test Notes
+`
+		require.Equal(t, expected, buffer.String())
+	})
+
+	t.Run("writes a row per split", func(t *testing.T) {
+		t.Parallel()
+
+		now, err := time.Parse("2006-01-02", "2025-04-16")
+		require.NoError(t, err)
+
+		formatter, buffer := setup(t)
+
+		err = format.WriteCollection(formatter, []*domain.Transaction{testSplitTransaction(t, now)})
+		require.NoError(t, err)
+
+		expected := `check number,date,description,category,amount,memo
+Trn,2025-04-16,Supermarket,Groceries,-15.00,Weekly shop (split 1 of 2)
+Trn,2025-04-16,Supermarket,Household,-5.00,Weekly shop (split 2 of 2)
 `
 		require.Equal(t, expected, buffer.String())
 	})
@@ -0,0 +1,65 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+)
+
+const (
+	qifTimeFormat            = "01/02/2006" // date format expected by QIF (MM/DD/YYYY)
+	FormatTypeQIF FormatType = "qif"
+)
+
+func init() {
+	register(FormatTypeQIF, func(w io.Writer, location *time.Location) (Formatter, error) {
+		return &QIFFormatter{
+			TextFormatter: NewTextFormatter(w),
+			location:      location,
+		}, nil
+	})
+}
+
+// QIFFormatter formats transactions as a Quicken Interchange Format (QIF) bank account.
+// Split transactions are written with a split line (S, $) per category.
+type QIFFormatter struct {
+	*TextFormatter
+	location *time.Location
+}
+
+func (q *QIFFormatter) WriteHeader() error {
+	_, err := q.writer.WriteString("!Type:Bank\n")
+	return err
+}
+
+func (q *QIFFormatter) WriteTransaction(t *domain.Transaction) error {
+	fields := []string{
+		"D" + t.CreatedAt.In(q.location).Format(qifTimeFormat),
+		"T" + t.Amount.String(),
+		"P" + t.Reference,
+	}
+
+	if t.Notes != "" {
+		fields = append(fields, "M"+t.Notes)
+	}
+
+	if t.Category != "" && len(t.Splits) == 0 {
+		fields = append(fields, "L"+t.Category)
+	}
+
+	for _, split := range t.Splits {
+		fields = append(fields, "S"+split.Category, "$"+split.Amount.String())
+	}
+
+	fields = append(fields, "^")
+
+	for _, field := range fields {
+		if _, err := fmt.Fprintln(q.writer, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
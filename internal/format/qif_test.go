@@ -0,0 +1,80 @@
+package format_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQIFFormatter(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (format.Formatter, *bytes.Buffer) {
+		t.Helper()
+		buffer := bytes.NewBuffer(nil)
+		formatter, err := format.NewFormatter(format.FormatTypeQIF, buffer)
+		require.NoError(t, err)
+
+		return formatter, buffer
+	}
+
+	now, err := time.Parse("2006-01-02", "2025-04-16")
+	require.NoError(t, err)
+
+	t.Run("writes QIF data", func(t *testing.T) {
+		t.Parallel()
+
+		formatter, buffer := setup(t)
+
+		err := format.WriteCollection(formatter, testTransactions(t, now))
+		require.NoError(t, err)
+
+		expected := `!Type:Bank
+D04/16/2025
+T123.45
+PTest Transaction
+MTest Notes
+LTest Category
+^
+D04/16/2025
+T-123.45
+PAnother Test Transaction
+MMore notes
+LAnother Test Category
+^
+D05/04/2025
+T-1.00
+PTransaction With Date Affected By Timezone
+MTest Notes
+LTest Category
+^
+`
+		require.Equal(t, expected, buffer.String())
+	})
+
+	t.Run("writes split lines", func(t *testing.T) {
+		t.Parallel()
+
+		formatter, buffer := setup(t)
+
+		err := format.WriteCollection(formatter, []*domain.Transaction{testSplitTransaction(t, now)})
+		require.NoError(t, err)
+
+		expected := `!Type:Bank
+D04/16/2025
+T-20.00
+PSupermarket
+MWeekly shop
+SGroceries
+$-15.00
+SHousehold
+$-5.00
+^
+`
+		require.Equal(t, expected, buffer.String())
+	})
+}
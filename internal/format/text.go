@@ -0,0 +1,23 @@
+package format
+
+import (
+	"bufio"
+	"io"
+)
+
+// TextFormatter provides buffered plain text output functionality for transaction formatters.
+// It wraps the standard bufio.Writer.
+type TextFormatter struct {
+	writer *bufio.Writer
+}
+
+// NewTextFormatter creates a new text formatter that writes to the provided io.Writer.
+func NewTextFormatter(w io.Writer) *TextFormatter {
+	return &TextFormatter{
+		writer: bufio.NewWriter(w),
+	}
+}
+
+func (f *TextFormatter) Flush() error {
+	return f.writer.Flush()
+}
@@ -0,0 +1,469 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/gocardless"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/samber/lo"
+)
+
+const (
+	ExportTypeGoCardless   = export.ExportType("gocardless")
+	goCardlessMaxDateRange = time.Duration(0)
+	defaultBankName        = "GoCardless"
+	dateFormat             = "2006-01-02"
+)
+
+var _ export.Exporter = (*TransactionExporter)(nil)
+
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+	Pending:      true,
+}
+
+type TransactionExporter struct {
+	api      gocardless.Client
+	bankName string
+}
+
+type Option func(*TransactionExporter)
+
+// WithBankName configures the bank name given to exported transactions, e.g. Revolut.
+func WithBankName(name string) Option {
+	return func(e *TransactionExporter) {
+		if name != "" {
+			e.bankName = name
+		}
+	}
+}
+
+func New(api gocardless.Client, opts ...Option) (*TransactionExporter, error) {
+	if api == nil {
+		return nil, errors.New("gocardless client is required")
+	}
+
+	exporter := &TransactionExporter{
+		api:      api,
+		bankName: defaultBankName,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(exporter)
+	}
+
+	return exporter, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return ExportTypeGoCardless
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return goCardlessMaxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
+// Link is a requisition, which links an end user's accounts at an institution, with its agreement's terms.
+type Link struct {
+	RequisitionID     string    `json:"requisitionId"`
+	InstitutionID     string    `json:"institutionId"`
+	Status            string    `json:"status"`
+	Reference         string    `json:"reference,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+	AccessExpiresAt   time.Time `json:"accessExpiresAt,omitzero"` // Zero when the agreement hasn't been accepted
+	MaxHistoricalDays int       `json:"maxHistoricalDays,omitempty"`
+	Accounts          []string  `json:"accounts"`
+}
+
+var requisitionStatuses = map[gocardless.RequisitionStatus]string{
+	gocardless.RequisitionCreated:   "created",
+	gocardless.RequisitionLinked:    "linked",
+	gocardless.RequisitionExpired:   "expired",
+	gocardless.RequisitionRejected:  "rejected",
+	gocardless.RequisitionSuspended: "suspended",
+}
+
+// ExportLinks returns every requisition with its agreement, to show which links need renewing.
+func (e *TransactionExporter) ExportLinks(ctx context.Context) ([]*Link, error) {
+	requisitions, agreements, err := e.fetchRequisitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(requisitions, func(requisition *gocardless.Requisition, _ int) *Link {
+		status, ok := requisitionStatuses[requisition.Status]
+		if !ok {
+			status = string(requisition.Status)
+		}
+
+		link := &Link{
+			RequisitionID: requisition.ID,
+			InstitutionID: requisition.InstitutionID,
+			Status:        status,
+			Reference:     requisition.Reference,
+			CreatedAt:     requisition.Created,
+			Accounts:      requisition.Accounts,
+		}
+
+		if agreement, ok := agreements[requisition.Agreement]; ok {
+			link.MaxHistoricalDays = agreement.MaxHistoricalDays
+			if !agreement.Accepted.IsZero() {
+				link.AccessExpiresAt = agreement.Accepted.AddDate(0, 0, agreement.AccessValidForDays)
+			}
+		}
+
+		return link
+	}), nil
+}
+
+// ExportAccounts returns the accounts of every linked requisition.
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	linked, err := e.fetchLinkedAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*domain.Account, 0, len(linked))
+	for _, link := range linked {
+		account, err := e.api.FetchAccount(ctx, link.id)
+		if err != nil {
+			return nil, fmt.Errorf("fetch account %s: %w", link.id, err)
+		}
+
+		details, err := e.api.FetchAccountDetails(ctx, link.id)
+		if err != nil {
+			return nil, fmt.Errorf("fetch account details %s: %w", link.id, err)
+		}
+
+		accounts = append(accounts, toAccount(account, details))
+	}
+
+	return accounts, nil
+}
+
+// ExportTransactions returns the booked and pending transactions of the selected account, or of every linked account
+// when the account is "all". The account is selected by its ID or institution ID, e.g. REVOLUT_REVOGB21.
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if opts.Space != "" {
+		return nil, errors.New("spaces are not supported by gocardless")
+	}
+
+	linked, err := e.fetchLinkedAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectAccounts(linked, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.FromContext(ctx)
+
+	transactions := make([]*domain.Transaction, 0)
+	for _, account := range selected {
+		dateFrom := opts.StartDate
+
+		// Institutions reject requests for more history than the agreement allows
+		if account.maxHistoricalDays > 0 {
+			earliest := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -account.maxHistoricalDays)
+			if dateFrom.Before(earliest) {
+				logger.WarnContext(ctx, "start date is before the agreement's history, exporting from the earliest available date",
+					slog.String("account.id", account.id),
+					slog.Int("agreement.max_historical_days", account.maxHistoricalDays),
+					slog.String("start", earliest.Format(dateFormat)),
+				)
+
+				dateFrom = earliest
+			}
+		}
+
+		results, err := e.api.FetchTransactions(ctx, gocardless.FetchTransactionOptions{
+			AccountID: account.id,
+			DateFrom:  dateFrom,
+			DateTo:    opts.EndDate.Add(-time.Nanosecond),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch transactions: %w", err)
+		}
+
+		count := 0
+		for _, group := range []struct {
+			status       domain.TransactionStatus
+			transactions []*gocardless.Transaction
+		}{
+			{status: domain.TransactionStatusSettled, transactions: results.Booked},
+			{status: domain.TransactionStatusPending, transactions: results.Pending},
+		} {
+			for _, txn := range group.transactions {
+				transaction, err := toTransaction(txn, group.status, account.institutionID, e.bankName)
+				if err != nil {
+					return nil, fmt.Errorf("transaction %s: %w", transaction.ID, err)
+				}
+
+				if transaction.CreatedAt.Before(opts.StartDate) || !transaction.CreatedAt.Before(opts.EndDate) {
+					continue
+				}
+
+				transactions = append(transactions, transaction)
+				count++
+			}
+		}
+
+		logger.InfoContext(ctx, "fetched transactions",
+			slog.String("account.id", account.id),
+			slog.Int("transaction.count", count),
+		)
+	}
+
+	// Booked and pending transactions are returned separately, so order them by date
+	slices.SortStableFunc(transactions, func(a, b *domain.Transaction) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return transactions, nil
+}
+
+// linkedAccount is an account of a linked requisition.
+type linkedAccount struct {
+	id                string
+	institutionID     string
+	maxHistoricalDays int
+}
+
+func (e *TransactionExporter) fetchRequisitions(ctx context.Context) ([]*gocardless.Requisition, map[string]*gocardless.Agreement, error) {
+	requisitions, err := e.api.FetchRequisitions(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch requisitions: %w", err)
+	}
+
+	agreements, err := e.api.FetchAgreements(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fetch agreements: %w", err)
+	}
+
+	return requisitions, lo.KeyBy(agreements, func(agreement *gocardless.Agreement) string {
+		return agreement.ID
+	}), nil
+}
+
+func (e *TransactionExporter) fetchLinkedAccounts(ctx context.Context) ([]*linkedAccount, error) {
+	requisitions, agreements, err := e.fetchRequisitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []*linkedAccount
+	seen := make(map[string]struct{})
+	for _, requisition := range requisitions {
+		if requisition.Status != gocardless.RequisitionLinked {
+			continue
+		}
+
+		maxHistoricalDays := 0
+		if agreement, ok := agreements[requisition.Agreement]; ok {
+			maxHistoricalDays = agreement.MaxHistoricalDays
+		}
+
+		for _, id := range requisition.Accounts {
+			// The same account is listed by every requisition that linked it
+			if _, ok := seen[id]; ok {
+				continue
+			}
+
+			seen[id] = struct{}{}
+			accounts = append(accounts, &linkedAccount{
+				id:                id,
+				institutionID:     requisition.InstitutionID,
+				maxHistoricalDays: maxHistoricalDays,
+			})
+		}
+	}
+
+	if len(accounts) == 0 {
+		return nil, errors.New("no linked accounts, create a requisition and authorise it with the bank first")
+	}
+
+	return accounts, nil
+}
+
+// selectAccounts returns the account matching the selector, or every account when the selector is "all".
+// An empty selector selects the first account.
+func selectAccounts(accounts []*linkedAccount, selector string) ([]*linkedAccount, error) {
+	if strings.EqualFold(selector, export.AllAccounts) {
+		return accounts, nil
+	}
+
+	if selector == "" {
+		return accounts[:1], nil
+	}
+
+	if account, ok := lo.Find(accounts, func(account *linkedAccount) bool {
+		return account.id == selector
+	}); ok {
+		return []*linkedAccount{account}, nil
+	}
+
+	matches := lo.Filter(accounts, func(account *linkedAccount, _ int) bool {
+		return strings.EqualFold(account.institutionID, selector)
+	})
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no account matches %q", selector)
+	case 1:
+		return matches, nil
+	default:
+		ids := lo.Map(matches, func(account *linkedAccount, _ int) string {
+			return account.id
+		})
+
+		return nil, fmt.Errorf("%q matches %d accounts (%s), use an account ID instead", selector, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+var cashAccountTypes = map[string]string{
+	"CACC": "current",
+	"SVGS": "savings",
+	"CARD": "card",
+	"LOAN": "loan",
+}
+
+func toAccount(account *gocardless.Account, details *gocardless.AccountDetails) *domain.Account {
+	name := details.Name
+	if name == "" {
+		name = details.Product
+	}
+
+	accountType, ok := cashAccountTypes[details.CashAccountType]
+	if !ok {
+		accountType = strings.ToLower(details.CashAccountType)
+	}
+
+	iban := details.IBAN
+	if iban == "" {
+		iban = account.IBAN
+	}
+
+	ownerName := details.OwnerName
+	if ownerName == "" {
+		ownerName = account.OwnerName
+	}
+
+	result := &domain.Account{
+		ID:        account.ID,
+		Name:      name,
+		Type:      accountType,
+		Currency:  details.Currency,
+		IBAN:      iban,
+		BIC:       details.BIC,
+		CreatedAt: account.Created,
+	}
+
+	if ownerName != "" {
+		result.Owners = []string{ownerName}
+	}
+
+	return result
+}
+
+func toTransaction(txn *gocardless.Transaction, status domain.TransactionStatus, account string, bankName string) (*domain.Transaction, error) {
+	id := txn.TransactionID
+	if id == "" {
+		id = txn.InternalTransactionID
+	}
+
+	transaction := &domain.Transaction{
+		ID:       id,
+		BankName: bankName,
+		Account:  account,
+		Status:   status,
+	}
+
+	major, err := strconv.ParseFloat(txn.TransactionAmount.Amount, 64)
+	if err != nil {
+		return transaction, fmt.Errorf("parse amount %q: %w", txn.TransactionAmount.Amount, err)
+	}
+
+	createdAt, err := transactionDate(txn)
+	if err != nil {
+		return transaction, err
+	}
+
+	remittance := txn.RemittanceInformationUnstructured
+	if remittance == "" {
+		remittance = strings.Join(txn.RemittanceInformationUnstructuredArray, " ")
+	}
+
+	// Prefer the counterparty's name, keeping the remittance information as a note
+	counterparty := txn.DebtorName
+	if major < 0 {
+		counterparty = txn.CreditorName
+	}
+
+	reference := counterparty
+	notes := remittance
+	if reference == "" {
+		reference = remittance
+		notes = ""
+	}
+
+	if reference == "" {
+		reference = txn.AdditionalInformation
+	}
+
+	transaction.Amount = domain.MoneyFromMajorUnit(major, txn.TransactionAmount.Currency)
+	transaction.Reference = reference
+	transaction.Notes = notes
+	transaction.CreatedAt = createdAt
+	transaction.IsDeposit = major > 0
+
+	return transaction, nil
+}
+
+// transactionDate returns the booking time, falling back to the booking or value date, as pending transactions
+// may only have a value date.
+func transactionDate(txn *gocardless.Transaction) (time.Time, error) {
+	if txn.BookingDateTime != "" {
+		createdAt, err := time.Parse(time.RFC3339, txn.BookingDateTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse booking date time %q: %w", txn.BookingDateTime, err)
+		}
+
+		return createdAt, nil
+	}
+
+	date := txn.BookingDate
+	if date == "" {
+		date = txn.ValueDate
+	}
+
+	createdAt, err := time.Parse(dateFormat, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse date %q: %w", date, err)
+	}
+
+	return createdAt, nil
+}
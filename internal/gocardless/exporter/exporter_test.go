@@ -0,0 +1,326 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/gocardless"
+	gocardlessexporter "github.com/HallyG/fingrab/internal/gocardless/exporter"
+	"github.com/stretchr/testify/require"
+)
+
+type StubClient struct {
+	Agreements     []*gocardless.Agreement
+	Requisitions   []*gocardless.Requisition
+	Accounts       map[string]*gocardless.Account
+	AccountDetails map[string]*gocardless.AccountDetails
+	Transactions   map[string]*gocardless.Transactions
+	FetchTxnsErr   error
+
+	RequestedTransactions []gocardless.FetchTransactionOptions
+}
+
+var _ gocardless.Client = (*StubClient)(nil)
+
+func (c *StubClient) FetchAgreements(ctx context.Context) ([]*gocardless.Agreement, error) {
+	return c.Agreements, nil
+}
+
+func (c *StubClient) FetchRequisitions(ctx context.Context) ([]*gocardless.Requisition, error) {
+	return c.Requisitions, nil
+}
+
+func (c *StubClient) FetchAccount(ctx context.Context, accountID string) (*gocardless.Account, error) {
+	return c.Accounts[accountID], nil
+}
+
+func (c *StubClient) FetchAccountDetails(ctx context.Context, accountID string) (*gocardless.AccountDetails, error) {
+	return c.AccountDetails[accountID], nil
+}
+
+func (c *StubClient) FetchTransactions(ctx context.Context, opts gocardless.FetchTransactionOptions) (*gocardless.Transactions, error) {
+	if c.FetchTxnsErr != nil {
+		return nil, c.FetchTxnsErr
+	}
+
+	c.RequestedTransactions = append(c.RequestedTransactions, opts)
+
+	transactions, ok := c.Transactions[opts.AccountID]
+	if !ok {
+		return &gocardless.Transactions{}, nil
+	}
+
+	return transactions, nil
+}
+
+func newStubClient() *StubClient {
+	return &StubClient{
+		Agreements: []*gocardless.Agreement{
+			{ID: "agreement-1", MaxHistoricalDays: 0, AccessValidForDays: 90, Accepted: time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC)},
+		},
+		Requisitions: []*gocardless.Requisition{
+			{ID: "requisition-1", Status: gocardless.RequisitionLinked, InstitutionID: "REVOLUT_REVOGB21", Agreement: "agreement-1", Accounts: []string{"acc-1"}},
+			{ID: "requisition-2", Status: gocardless.RequisitionExpired, InstitutionID: "MONZO_MONZGB2L", Accounts: []string{"acc-expired"}},
+			{ID: "requisition-3", Status: gocardless.RequisitionLinked, InstitutionID: "N26_NTSBDEB1", Accounts: []string{"acc-2", "acc-1"}},
+		},
+		Transactions: map[string]*gocardless.Transactions{
+			"acc-1": {
+				Booked: []*gocardless.Transaction{
+					{
+						TransactionID:                     "txn-1",
+						BookingDate:                       "2025-03-03",
+						BookingDateTime:                   "2025-03-03T10:15:00+01:00",
+						TransactionAmount:                 gocardless.Amount{Amount: "-12.50", Currency: "EUR"},
+						CreditorName:                      "Bakery GmbH",
+						RemittanceInformationUnstructured: "Card payment",
+					},
+					{
+						InternalTransactionID:                  "internal-2",
+						BookingDate:                            "2025-03-04",
+						TransactionAmount:                      gocardless.Amount{Amount: "2500.00", Currency: "EUR"},
+						RemittanceInformationUnstructuredArray: []string{"Salary", "March"},
+					},
+					{
+						TransactionID:     "txn-outside",
+						BookingDate:       "2025-04-01",
+						TransactionAmount: gocardless.Amount{Amount: "-1.00", Currency: "EUR"},
+					},
+				},
+				Pending: []*gocardless.Transaction{
+					{
+						ValueDate:             "2025-03-02",
+						TransactionAmount:     gocardless.Amount{Amount: "-3.20", Currency: "EUR"},
+						AdditionalInformation: "Coffee Shop",
+					},
+				},
+			},
+			"acc-2": {
+				Booked: []*gocardless.Transaction{
+					{
+						TransactionID:     "txn-3",
+						BookingDate:       "2025-03-05",
+						TransactionAmount: gocardless.Amount{Amount: "4.00", Currency: "EUR"},
+						DebtorName:        "Jane Doe",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when client is nil", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := gocardlessexporter.New(nil)
+
+		require.Nil(t, exporter)
+		require.EqualError(t, err, "gocardless client is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := gocardlessexporter.New(&StubClient{})
+
+		require.NoError(t, err)
+		require.Equal(t, gocardlessexporter.ExportTypeGoCardless, exporter.Type())
+		require.Equal(t, time.Duration(0), exporter.MaxDateRange())
+	})
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	coffee := &domain.Transaction{
+		Amount:    domain.Money{MinorUnit: -320, Currency: "EUR"},
+		Reference: "Coffee Shop",
+		CreatedAt: time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC),
+		BankName:  "Revolut",
+		Account:   "REVOLUT_REVOGB21",
+		Status:    domain.TransactionStatusPending,
+	}
+	bakery := &domain.Transaction{
+		ID:        "txn-1",
+		Amount:    domain.Money{MinorUnit: -1250, Currency: "EUR"},
+		Reference: "Bakery GmbH",
+		Notes:     "Card payment",
+		CreatedAt: time.Date(2025, time.March, 3, 10, 15, 0, 0, time.FixedZone("", 60*60)),
+		BankName:  "Revolut",
+		Account:   "REVOLUT_REVOGB21",
+		Status:    domain.TransactionStatusSettled,
+	}
+	salary := &domain.Transaction{
+		ID:        "internal-2",
+		Amount:    domain.Money{MinorUnit: 250000, Currency: "EUR"},
+		Reference: "Salary March",
+		CreatedAt: time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC),
+		IsDeposit: true,
+		BankName:  "Revolut",
+		Account:   "REVOLUT_REVOGB21",
+		Status:    domain.TransactionStatusSettled,
+	}
+	refund := &domain.Transaction{
+		ID:        "txn-3",
+		Amount:    domain.Money{MinorUnit: 400, Currency: "EUR"},
+		Reference: "Jane Doe",
+		CreatedAt: time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+		IsDeposit: true,
+		BankName:  "Revolut",
+		Account:   "N26_NTSBDEB1",
+		Status:    domain.TransactionStatusSettled,
+	}
+
+	tests := map[string]struct {
+		accountID            string
+		fetchTxnsErr         error
+		expectedTransactions []*domain.Transaction
+		expectedErr          string
+	}{
+		"exports first linked account, ordered by date": {
+			expectedTransactions: []*domain.Transaction{coffee, bakery, salary},
+		},
+		"selects account by institution ID": {
+			accountID:            "n26_ntsbdeb1",
+			expectedTransactions: []*domain.Transaction{refund},
+		},
+		"exports every linked account once": {
+			accountID:            export.AllAccounts,
+			expectedTransactions: []*domain.Transaction{coffee, bakery, salary, refund},
+		},
+		"returns error when account isn't linked": {
+			accountID:   "acc-expired",
+			expectedErr: `no account matches "acc-expired"`,
+		},
+		"returns error when fetching transactions fails": {
+			fetchTxnsErr: errors.New("boom"),
+			expectedErr:  "fetch transactions: boom",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newStubClient()
+			client.FetchTxnsErr = test.fetchTxnsErr
+
+			exporter, err := gocardlessexporter.New(client, gocardlessexporter.WithBankName("Revolut"))
+			require.NoError(t, err)
+
+			transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				AccountID: test.accountID,
+				StartDate: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Options:   export.Options{AuthToken: "test-token"},
+			})
+
+			if test.expectedErr != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransactions, transactions)
+			require.Equal(t, "2025-03-31", client.RequestedTransactions[0].DateTo.Format(time.DateOnly))
+		})
+	}
+
+	t.Run("clamps start date to the agreement's history", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStubClient()
+		client.Agreements[0].MaxHistoricalDays = 90
+
+		exporter, err := gocardlessexporter.New(client)
+		require.NoError(t, err)
+
+		_, err = exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+			StartDate: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:   time.Now().UTC(),
+			Options:   export.Options{AuthToken: "test-token"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -90), client.RequestedTransactions[0].DateFrom)
+	})
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2025, time.January, 10, 9, 5, 0, 0, time.UTC)
+	client := newStubClient()
+	client.Accounts = map[string]*gocardless.Account{
+		"acc-1": {ID: "acc-1", Created: created, IBAN: "GB33BUKB20201555555555", OwnerName: "Jane Doe"},
+		"acc-2": {ID: "acc-2", Created: created},
+	}
+	client.AccountDetails = map[string]*gocardless.AccountDetails{
+		"acc-1": {Currency: "EUR", Name: "Main Account", CashAccountType: "CACC", BIC: "REVOGB21"},
+		"acc-2": {Currency: "EUR", Product: "Spaces", CashAccountType: "OTHR", IBAN: "DE89370400440532013000", OwnerName: "John Doe"},
+	}
+
+	exporter, err := gocardlessexporter.New(client)
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Account{
+		{
+			ID:        "acc-1",
+			Name:      "Main Account",
+			Type:      "current",
+			Currency:  "EUR",
+			IBAN:      "GB33BUKB20201555555555",
+			BIC:       "REVOGB21",
+			Owners:    []string{"Jane Doe"},
+			CreatedAt: created,
+		},
+		{
+			ID:        "acc-2",
+			Name:      "Spaces",
+			Type:      "othr",
+			Currency:  "EUR",
+			IBAN:      "DE89370400440532013000",
+			Owners:    []string{"John Doe"},
+			CreatedAt: created,
+		},
+	}, accounts)
+}
+
+func TestExportLinks(t *testing.T) {
+	t.Parallel()
+
+	client := newStubClient()
+	client.Requisitions = client.Requisitions[:2]
+
+	exporter, err := gocardlessexporter.New(client)
+	require.NoError(t, err)
+
+	links, err := exporter.ExportLinks(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, []*gocardlessexporter.Link{
+		{
+			RequisitionID:   "requisition-1",
+			InstitutionID:   "REVOLUT_REVOGB21",
+			Status:          "linked",
+			AccessExpiresAt: time.Date(2025, time.April, 10, 0, 0, 0, 0, time.UTC),
+			Accounts:        []string{"acc-1"},
+		},
+		{
+			RequisitionID: "requisition-2",
+			InstitutionID: "MONZO_MONZGB2L",
+			Status:        "expired",
+			Accounts:      []string{"acc-expired"},
+		},
+	}, links)
+}
@@ -0,0 +1,82 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/gocardless"
+	"github.com/HallyG/fingrab/internal/log"
+)
+
+// GoCardless issues access tokens in exchange for a user secret, rather than through OAuth
+const (
+	envSecretIDSuffix  = "_SECRET_ID"
+	envSecretKeySuffix = "_SECRET_KEY"
+)
+
+func init() {
+	description := fmt.Sprintf(`Commands for interacting with GoCardless Bank Account Data (formerly Nordigen), which reads accounts at
+thousands of European banks. Accounts are linked to a requisition in the GoCardless portal or API beforehand.
+
+Without a token, one is exchanged for the user secret in these environment variables:
+
+  %s  User secret ID
+  %s User secret key
+  %s  Bank name given to exported transactions, defaults to GoCardless`,
+		export.EnvVarName(ExportTypeGoCardless, envSecretIDSuffix),
+		export.EnvVarName(ExportTypeGoCardless, envSecretKeySuffix),
+		export.EnvVarName(ExportTypeGoCardless, export.EnvBankNameSuffix),
+	)
+
+	export.Register(ExportTypeGoCardless, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName("GoCardless Bank Account Data"),
+		export.WithDescription(description),
+		export.WithTokenSource(exchangeSecret),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+// NewFromOptions returns an exporter of GoCardless Bank Account Data, naming transactions after the bank name from
+// the environment.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+
+	api := gocardless.New(client, api.WithAuthToken(opts.BearerAuthToken()))
+	return New(api, WithBankName(export.Getenv(ExportTypeGoCardless, export.EnvBankNameSuffix)))
+}
+
+// exchangeSecret exchanges the user secret from the environment for an access token, within the command's timeout.
+func exchangeSecret(ctx context.Context, opts export.Options) (string, error) {
+	secretID := export.Getenv(ExportTypeGoCardless, envSecretIDSuffix)
+	secretKey := export.Getenv(ExportTypeGoCardless, envSecretKeySuffix)
+	if secretID == "" || secretKey == "" {
+		return "", fmt.Errorf("%s and %s are required without a token",
+			export.EnvVarName(ExportTypeGoCardless, envSecretIDSuffix),
+			export.EnvVarName(ExportTypeGoCardless, envSecretKeySuffix),
+		)
+	}
+
+	log.FromContext(ctx).DebugContext(ctx, "exchanging user secret for access token",
+		slog.String("bank", string(ExportTypeGoCardless)),
+	)
+
+	token, err := gocardless.New(&http.Client{Timeout: opts.Timeout}).NewToken(ctx, secretID, secretKey)
+	if err != nil {
+		return "", fmt.Errorf("token exchange: %w", err)
+	}
+
+	if token.Access == "" {
+		return "", errors.New("token exchange: no access token returned")
+	}
+
+	return token.Access, nil
+}
@@ -0,0 +1,196 @@
+// Package gocardless is a client for GoCardless Bank Account Data (formerly Nordigen), which provides read access to
+// thousands of European banks through a single API.
+package gocardless
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	resty "resty.dev/v3"
+)
+
+const (
+	prodAPI                = "https://bankaccountdata.gocardless.com/api/v2"
+	postTokenRoute         = "/token/new/"
+	getAgreementsRoute     = "/agreements/enduser/"
+	getRequisitionsRoute   = "/requisitions/"
+	getAccountRoute        = "/accounts/%s/"
+	getAccountDetailsRoute = "/accounts/%s/details/"
+	getTransactionsRoute   = "/accounts/%s/transactions/"
+	dateFormat             = "2006-01-02"
+	maxPages               = 1000
+)
+
+var _ Client = (*client)(nil)
+
+type (
+	Client interface {
+		FetchAgreements(ctx context.Context) ([]*Agreement, error)
+		FetchRequisitions(ctx context.Context) ([]*Requisition, error)
+		FetchAccount(ctx context.Context, accountID string) (*Account, error)
+		FetchAccountDetails(ctx context.Context, accountID string) (*AccountDetails, error)
+		FetchTransactions(ctx context.Context, opts FetchTransactionOptions) (*Transactions, error)
+	}
+	client struct {
+		api *resty.Client
+	}
+)
+
+type FetchTransactionOptions struct {
+	AccountID string
+	DateFrom  time.Time // Inclusive, only the date is used
+	DateTo    time.Time // Inclusive, only the date is used
+}
+
+func New(httpClient *http.Client, opts ...api.Option) *client {
+	c := api.New(
+		prodAPI,
+		httpClient,
+		api.WithError[Error](),
+	)
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(c)
+	}
+
+	return &client{
+		api: c,
+	}
+}
+
+// NewToken exchanges a user secret's ID and key for an access token. It doesn't need the client to have an auth token.
+func (c *client) NewToken(ctx context.Context, secretID string, secretKey string) (*Token, error) {
+	if secretID == "" || secretKey == "" {
+		return nil, errors.New("secret ID and secret key are required")
+	}
+
+	return api.ExecuteRequestWithBody[Token](ctx, c.api,
+		http.MethodPost,
+		postTokenRoute,
+		map[string]string{
+			"secret_id":  secretID,
+			"secret_key": secretKey,
+		},
+	)
+}
+
+func (c *client) FetchAgreements(ctx context.Context) ([]*Agreement, error) {
+	return fetchAll[Agreement](ctx, c, getAgreementsRoute)
+}
+
+func (c *client) FetchRequisitions(ctx context.Context) ([]*Requisition, error) {
+	return fetchAll[Requisition](ctx, c, getRequisitionsRoute)
+}
+
+func (c *client) FetchAccount(ctx context.Context, accountID string) (*Account, error) {
+	return api.ExecuteRequest[Account](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getAccountRoute, url.PathEscape(accountID)),
+		url.Values{},
+	)
+}
+
+func (c *client) FetchAccountDetails(ctx context.Context, accountID string) (*AccountDetails, error) {
+	result, err := api.ExecuteRequest[struct {
+		Account *AccountDetails `json:"account"`
+	}](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getAccountDetailsRoute, url.PathEscape(accountID)),
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Account == nil {
+		return &AccountDetails{}, nil
+	}
+
+	return result.Account, nil
+}
+
+// FetchTransactions fetches the account's booked and pending transactions between the dates.
+func (c *client) FetchTransactions(ctx context.Context, opts FetchTransactionOptions) (*Transactions, error) {
+	if opts.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	values := url.Values{}
+	if !opts.DateFrom.IsZero() {
+		values.Set("date_from", opts.DateFrom.Format(dateFormat))
+	}
+
+	if !opts.DateTo.IsZero() {
+		values.Set("date_to", opts.DateTo.Format(dateFormat))
+	}
+
+	result, err := api.ExecuteRequest[struct {
+		Transactions Transactions `json:"transactions"`
+	}](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getTransactionsRoute, url.PathEscape(opts.AccountID)),
+		values,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Transactions, nil
+}
+
+// fetchAll requests the route and every page after it, by following each page's next link. Next links must be on
+// the same host as the API, so the auth token is never sent elsewhere.
+func fetchAll[T any](ctx context.Context, c *client, route string) ([]*T, error) {
+	base, err := url.Parse(c.api.BaseURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	var all []*T
+
+	seen := make(map[string]struct{})
+	next := route
+	for n := 1; next != ""; n++ {
+		if n > maxPages {
+			return nil, fmt.Errorf("pagination exceeded maximum number of pages (%d)", maxPages)
+		}
+
+		if _, ok := seen[next]; ok {
+			return nil, fmt.Errorf("page %d: next link %q was already fetched", n, next)
+		}
+
+		seen[next] = struct{}{}
+
+		result, err := api.ExecuteRequest[page[T]](ctx, c.api, http.MethodGet, next, url.Values{})
+		if err != nil {
+			if n == 1 {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("page %d: %w", n, err)
+		}
+
+		all = append(all, result.Results...)
+
+		next = result.Next
+		if next == "" {
+			break
+		}
+
+		link, err := url.Parse(next)
+		if err != nil || link.Scheme != base.Scheme || link.Host != base.Host {
+			return nil, fmt.Errorf("page %d: next link %q is not on %s", n, next, base.Host)
+		}
+	}
+
+	return all, nil
+}
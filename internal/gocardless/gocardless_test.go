@@ -0,0 +1,238 @@
+package gocardless_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/gocardless"
+	"github.com/HallyG/fingrab/internal/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token     = "Bearer mock-token"
+	accountID = "7e944232-bda9-40bc-b784-660c7ab5fe78"
+)
+
+func setup(t *testing.T, routes ...testhelper.HTTPTestRoute) gocardless.Client {
+	t.Helper()
+
+	server := testhelper.NewHTTPTestServer(t, routes)
+	client := gocardless.New(&http.Client{},
+		api.WithBaseURL(server.URL),
+		api.WithAuthToken(token),
+	)
+
+	return client
+}
+
+// serveWithServerURL serves the test data file, replacing {{server}} with the test server's URL, as pagination
+// links are absolute.
+func serveWithServerURL(t *testing.T, filename string) http.HandlerFunc {
+	t.Helper()
+
+	data := testhelper.LoadTestDataFile(t, filename)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(bytes.ReplaceAll(data, []byte("{{server}}"), []byte("http://"+r.Host)))
+	}
+}
+
+func TestNewToken(t *testing.T) {
+	t.Parallel()
+
+	t.Run("exchanges secret for token", func(t *testing.T) {
+		t.Parallel()
+
+		server := testhelper.NewHTTPTestServer(t, []testhelper.HTTPTestRoute{
+			{
+				Method: http.MethodPost,
+				URL:    "/token/new/",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					var body map[string]string
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+					require.Equal(t, map[string]string{"secret_id": "id", "secret_key": "key"}, body)
+					require.Empty(t, r.Header.Get("Authorization"))
+
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "token.json")(w, r)
+				},
+			},
+		})
+
+		client := gocardless.New(&http.Client{}, api.WithBaseURL(server.URL))
+
+		result, err := client.NewToken(t.Context(), "id", "key")
+
+		require.NoError(t, err)
+		require.Equal(t, &gocardless.Token{
+			Access:         "access-token",
+			AccessExpires:  86400,
+			Refresh:        "refresh-token",
+			RefreshExpires: 2592000,
+		}, result)
+	})
+
+	t.Run("returns error when secret is missing", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := gocardless.New(&http.Client{}).NewToken(t.Context(), "id", "")
+
+		require.Nil(t, result)
+		require.EqualError(t, err, "secret ID and secret key are required")
+	})
+}
+
+func TestFetchAgreements(t *testing.T) {
+	t.Parallel()
+
+	client := setup(t, testhelper.HTTPTestRoute{
+		Method:  http.MethodGet,
+		URL:     "/agreements/enduser/",
+		Handler: testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "agreements.json"),
+	})
+
+	agreements, err := client.FetchAgreements(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, agreements, 1)
+	require.Equal(t, 90, agreements[0].MaxHistoricalDays)
+	require.Equal(t, time.Date(2025, time.January, 10, 9, 5, 0, 0, time.UTC), agreements[0].Accepted)
+}
+
+func TestFetchRequisitions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		handler        http.HandlerFunc
+		expectedIDs    []string
+		expectedErrMsg string
+	}{
+		"follows next links across pages": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				header := http.Header{}
+				header.Add("Authorization", token)
+				testhelper.AssertRequest(t, r, http.MethodGet, header, url.Values{})
+
+				if r.URL.Query().Get("offset") == "1" {
+					serveWithServerURL(t, "requisitions-page-2.json")(w, r)
+					return
+				}
+
+				serveWithServerURL(t, "requisitions-page-1.json")(w, r)
+			},
+			expectedIDs: []string{"8126e9fb-93c9-4228-937c-68f0383c2df7", "0c5b4a4e-1b0c-4a55-9e0e-2f5b1d8d2a11"},
+		},
+		"returns error when next link is on another host": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"count": 2, "next": "https://attacker.example.com/requisitions/?offset=1", "results": []}`))
+			},
+			expectedErrMsg: `page 1: next link "https://attacker.example.com/requisitions/?offset=1" is not on`,
+		},
+		"returns API error": {
+			handler:        testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json"),
+			expectedErrMsg: "Invalid token: Token is invalid or expired",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     "/requisitions/",
+				Handler: test.handler,
+			})
+
+			requisitions, err := client.FetchRequisitions(t.Context())
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, requisitions)
+				require.ErrorContains(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(requisitions))
+			for _, requisition := range requisitions {
+				ids = append(ids, requisition.ID)
+			}
+
+			require.Equal(t, test.expectedIDs, ids)
+			require.Equal(t, gocardless.RequisitionLinked, requisitions[0].Status)
+			require.Equal(t, []string{accountID}, requisitions[0].Accounts)
+		})
+	}
+}
+
+func TestFetchAccount(t *testing.T) {
+	t.Parallel()
+
+	client := setup(t,
+		testhelper.HTTPTestRoute{
+			Method:  http.MethodGet,
+			URL:     "/accounts/" + accountID + "/",
+			Handler: testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "account.json"),
+		},
+		testhelper.HTTPTestRoute{
+			Method:  http.MethodGet,
+			URL:     "/accounts/" + accountID + "/details/",
+			Handler: testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "account-details.json"),
+		},
+	)
+
+	account, err := client.FetchAccount(t.Context(), accountID)
+	require.NoError(t, err)
+	require.Equal(t, "READY", account.Status)
+	require.Equal(t, "GB33BUKB20201555555555", account.IBAN)
+
+	details, err := client.FetchAccountDetails(t.Context(), accountID)
+	require.NoError(t, err)
+	require.Equal(t, &gocardless.AccountDetails{
+		ResourceID:      "534252452",
+		IBAN:            "GB33BUKB20201555555555",
+		Currency:        "EUR",
+		OwnerName:       "Jane Doe",
+		Name:            "Main Account",
+		Product:         "Personal",
+		CashAccountType: "CACC",
+		BIC:             "REVOGB21",
+	}, details)
+}
+
+func TestFetchTransactions(t *testing.T) {
+	t.Parallel()
+
+	client := setup(t, testhelper.HTTPTestRoute{
+		Method: http.MethodGet,
+		URL:    "/accounts/" + accountID + "/transactions/",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			query := url.Values{}
+			query.Add("date_from", "2025-03-01")
+			query.Add("date_to", "2025-03-31")
+
+			testhelper.AssertRequest(t, r, http.MethodGet, http.Header{}, query)
+			testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "transactions.json")(w, r)
+		},
+	})
+
+	transactions, err := client.FetchTransactions(t.Context(), gocardless.FetchTransactionOptions{
+		AccountID: accountID,
+		DateFrom:  time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		DateTo:    time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+	})
+
+	require.NoError(t, err)
+	require.Len(t, transactions.Booked, 2)
+	require.Len(t, transactions.Pending, 1)
+	require.Equal(t, gocardless.Amount{Amount: "-12.50", Currency: "EUR"}, transactions.Booked[0].TransactionAmount)
+	require.Equal(t, []string{"Salary", "March"}, transactions.Booked[1].RemittanceInformationUnstructuredArray)
+}
@@ -0,0 +1,120 @@
+package gocardless
+
+import (
+	"strings"
+	"time"
+)
+
+type RequisitionStatus string
+
+const (
+	RequisitionCreated   RequisitionStatus = "CR"
+	RequisitionLinked    RequisitionStatus = "LN"
+	RequisitionExpired   RequisitionStatus = "EX"
+	RequisitionRejected  RequisitionStatus = "RJ"
+	RequisitionSuspended RequisitionStatus = "SU"
+)
+
+type Token struct {
+	Access         string `json:"access"`
+	AccessExpires  int    `json:"access_expires"` // Seconds until Access expires
+	Refresh        string `json:"refresh"`
+	RefreshExpires int    `json:"refresh_expires"`
+}
+
+// Agreement is an end user's consent to an institution sharing their data, for a number of days.
+type Agreement struct {
+	ID                 string    `json:"id"`
+	Created            time.Time `json:"created"`
+	InstitutionID      string    `json:"institution_id"`
+	MaxHistoricalDays  int       `json:"max_historical_days"`
+	AccessValidForDays int       `json:"access_valid_for_days"`
+	AccessScope        []string  `json:"access_scope"`
+	Accepted           time.Time `json:"accepted"`
+}
+
+// Requisition links an end user's accounts at an institution, under an agreement.
+type Requisition struct {
+	ID            string            `json:"id"`
+	Created       time.Time         `json:"created"`
+	Status        RequisitionStatus `json:"status"`
+	InstitutionID string            `json:"institution_id"`
+	Agreement     string            `json:"agreement"`
+	Reference     string            `json:"reference"`
+	Accounts      []string          `json:"accounts"`
+}
+
+type Account struct {
+	ID            string    `json:"id"`
+	Created       time.Time `json:"created"`
+	LastAccessed  time.Time `json:"last_accessed"`
+	IBAN          string    `json:"iban"`
+	InstitutionID string    `json:"institution_id"`
+	Status        string    `json:"status"` // e.g. READY, EXPIRED or SUSPENDED
+	OwnerName     string    `json:"owner_name"`
+}
+
+type AccountDetails struct {
+	ResourceID      string `json:"resourceId"`
+	IBAN            string `json:"iban"`
+	BBAN            string `json:"bban"`
+	Currency        string `json:"currency"`
+	OwnerName       string `json:"ownerName"`
+	Name            string `json:"name"`
+	Product         string `json:"product"`
+	CashAccountType string `json:"cashAccountType"` // ISO 20022 code, e.g. CACC (current) or SVGS (savings)
+	BIC             string `json:"bic"`
+}
+
+type Amount struct {
+	Amount   string `json:"amount"` // Signed decimal amount, e.g. "-12.50"
+	Currency string `json:"currency"`
+}
+
+type Transaction struct {
+	TransactionID                          string   `json:"transactionId"`
+	InternalTransactionID                  string   `json:"internalTransactionId"`
+	BookingDate                            string   `json:"bookingDate"` // YYYY-MM-DD
+	BookingDateTime                        string   `json:"bookingDateTime"`
+	ValueDate                              string   `json:"valueDate"`
+	TransactionAmount                      Amount   `json:"transactionAmount"`
+	CreditorName                           string   `json:"creditorName"`
+	DebtorName                             string   `json:"debtorName"`
+	RemittanceInformationUnstructured      string   `json:"remittanceInformationUnstructured"`
+	RemittanceInformationUnstructuredArray []string `json:"remittanceInformationUnstructuredArray"`
+	AdditionalInformation                  string   `json:"additionalInformation"`
+	ProprietaryBankTransactionCode         string   `json:"proprietaryBankTransactionCode"`
+	MerchantCategoryCode                   string   `json:"merchantCategoryCode"`
+}
+
+type Transactions struct {
+	Booked  []*Transaction `json:"booked"`
+	Pending []*Transaction `json:"pending"`
+}
+
+// page is a page of a paginated list response.
+type page[T any] struct {
+	Count    int    `json:"count"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+	Results  []*T   `json:"results"`
+}
+
+type Error struct {
+	Summary    string `json:"summary"`
+	Detail     string `json:"detail"`
+	StatusCode int    `json:"status_code"`
+}
+
+func (err Error) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(err.Summary)
+
+	if err.Detail != "" {
+		sb.WriteString(": ")
+		sb.WriteString(err.Detail)
+	}
+
+	return sb.String()
+}
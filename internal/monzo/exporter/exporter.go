@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/HallyG/fingrab/internal/export"
 	"github.com/HallyG/fingrab/internal/log"
 	"github.com/HallyG/fingrab/internal/monzo"
+	"github.com/HallyG/fingrab/internal/store"
 	"github.com/samber/lo"
 )
 
@@ -25,17 +27,45 @@ const (
 
 var _ export.Exporter = (*TransactionExporter)(nil)
 
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+	Pending:      true,
+	Declines:     true,
+}
+
 type TransactionExporter struct {
-	api monzo.Client
+	api   monzo.Client
+	store *store.Store
 }
 
-func New(api monzo.Client) (*TransactionExporter, error) {
+type Option func(*TransactionExporter)
+
+// WithStore configures the exporter to persist and read backfilled transaction history from the given store.
+func WithStore(s *store.Store) Option {
+	return func(m *TransactionExporter) {
+		m.store = s
+	}
+}
+
+func New(api monzo.Client, opts ...Option) (*TransactionExporter, error) {
 	if api == nil {
 		return nil, errors.New("monzo client is required")
 	}
-	return &TransactionExporter{
+
+	exporter := &TransactionExporter{
 		api: api,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(exporter)
+	}
+
+	return exporter, nil
 }
 
 func (m *TransactionExporter) Type() export.ExportType {
@@ -46,6 +76,10 @@ func (m *TransactionExporter) MaxDateRange() time.Duration {
 	return monzoMaxDateRange
 }
 
+func (m *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
 func (m *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
 	accounts, err := m.api.FetchAccounts(ctx)
 	if err != nil {
@@ -54,8 +88,16 @@ func (m *TransactionExporter) ExportAccounts(ctx context.Context, opts export.Ac
 
 	return lo.Map(accounts, func(account *monzo.Account, _ int) *domain.Account {
 		return &domain.Account{
-			ID:        string(account.ID),
-			Type:      account.Type,
+			ID:            string(account.ID),
+			Name:          account.Description,
+			Type:          account.Type,
+			Currency:      account.Currency,
+			SortCode:      account.SortCode,
+			AccountNumber: account.AccountNumber,
+			Owners: lo.Map(account.Owners, func(owner *monzo.Owner, _ int) string {
+				return owner.PreferredName
+			}),
+			Closed:    account.Closed,
 			CreatedAt: account.CreatedAt,
 		}
 	}), nil
@@ -66,6 +108,10 @@ func (m *TransactionExporter) ExportTransactions(ctx context.Context, opts expor
 		return nil, fmt.Errorf("invalid options: %w", err)
 	}
 
+	if opts.Space != "" {
+		return nil, errors.New("exporting a space is not supported, monzo pots don't have a transaction feed")
+	}
+
 	log.FromContext(ctx).InfoContext(ctx, "starting export of transactions",
 		slog.String("export.start", opts.StartDate.Format(monzoTimeFormat)),
 		slog.String("export.end", opts.EndDate.Format(monzoTimeFormat)),
@@ -76,13 +122,13 @@ func (m *TransactionExporter) ExportTransactions(ctx context.Context, opts expor
 		return nil, err
 	}
 
-	transactions, err := m.fetchTransactions(ctx, account.ID, opts.StartDate, opts.EndDate)
-	if err != nil {
-		return nil, err
+	transactions, err := m.exportTransactions(ctx, account.ID, opts.StartDate, opts.EndDate, opts.Audit)
+	if monzo.IsVerificationRequired(err) {
+		log.FromContext(ctx).WarnContext(ctx, "monzo requires verification for this date range, falling back to backfilled history")
+
+		transactions, err = m.exportFromHistory(ctx, account.ID, opts.StartDate, opts.EndDate, opts.Audit, err)
 	}
 
-	// todo: move into fetch
-	err = m.enrichTransactionDescriptions(ctx, account.ID, transactions)
 	if err != nil {
 		return nil, err
 	}
@@ -91,25 +137,124 @@ func (m *TransactionExporter) ExportTransactions(ctx context.Context, opts expor
 		slog.Int("transaction.count", len(transactions)),
 	)
 
+	return transactions, nil
+}
+
+// exportTransactions fetches the account's transactions in the date range and maps them to domain transactions.
+// Declined transactions and card checks are only included when audit is true.
+func (m *TransactionExporter) exportTransactions(ctx context.Context, accountID monzo.AccountID, startDate time.Time, endDate time.Time, audit bool) ([]*domain.Transaction, error) {
+	transactions, err := m.fetchTransactions(ctx, accountID, startDate, endDate, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	// todo: move into fetch
+	err = m.enrichTransactionDescriptions(ctx, accountID, transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryNames := m.fetchCustomCategoryNames(ctx, accountID, transactions)
+
 	return lo.Map(transactions, func(txn *monzo.Transaction, _ int) *domain.Transaction {
-		reference, notes := m.determineReference(txn)
-		if txn.UserNotes != "" {
-			notes = txn.UserNotes
-		}
+		return m.toDomainTransaction(txn, categoryNames)
+	}), nil
+}
+
+func (m *TransactionExporter) toDomainTransaction(txn *monzo.Transaction, categoryNames map[string]string) *domain.Transaction {
+	reference, notes := m.determineReference(txn)
+	if txn.UserNotes != "" {
+		notes = txn.UserNotes
+	}
+
+	return &domain.Transaction{
+		ID:            string(txn.ID),
+		Amount:        txn.Amount,
+		Reference:     reference,
+		Category:      categoryName(txn.CategoryName, categoryNames),
+		CreatedAt:     txn.CreatedAt,
+		IsDeposit:     txn.LocalAmount.MinorUnit > 0,
+		BankName:      Monzo,
+		Notes:         notes,
+		Splits:        splits(txn, categoryNames),
+		Status:        transactionStatus(txn),
+		DeclineReason: txn.DeclineReason,
+	}
+}
+
+func transactionStatus(txn *monzo.Transaction) domain.TransactionStatus {
+	switch {
+	case txn.DeclineReason != "":
+		return domain.TransactionStatusDeclined
+	case isActiveCardCheck(txn):
+		return domain.TransactionStatusCardCheck
+	case txn.SettledAt == nil || txn.AmountIsPending:
+		return domain.TransactionStatusPending
+	default:
+		return domain.TransactionStatusSettled
+	}
+}
+
+func isActiveCardCheck(txn *monzo.Transaction) bool {
+	return txn.Amount.MinorUnit == 0 && txn.Metadata["notes"] == "Active card check"
+}
+
+// splits returns the transaction's category splits, or nil when the transaction has a single category.
+func splits(txn *monzo.Transaction, categoryNames map[string]string) []domain.Split {
+	if len(txn.Categories) < 2 {
+		return nil
+	}
+
+	categories := lo.Keys(txn.Categories)
+	slices.Sort(categories)
 
-		return &domain.Transaction{
-			Amount:    txn.Amount,
-			Reference: reference,
-			Category:  txn.CategoryName,
-			CreatedAt: txn.CreatedAt,
-			IsDeposit: txn.LocalAmount.MinorUnit > 0,
-			BankName:  Monzo,
-			Notes:     notes,
+	return lo.Map(categories, func(category string, _ int) domain.Split {
+		return domain.Split{
+			Category: categoryName(category, categoryNames),
+			Amount: domain.Money{
+				MinorUnit: txn.Categories[category],
+				Currency:  txn.Amount.Currency,
+			},
 		}
-	}), nil
+	})
+}
+
+func categoryName(category string, categoryNames map[string]string) string {
+	if name, ok := categoryNames[category]; ok && name != "" {
+		return name
+	}
+
+	return category
 }
 
-func (m *TransactionExporter) fetchAccount(ctx context.Context, accountID string) (*monzo.Account, error) {
+// fetchCustomCategoryNames resolves the names of any custom categories used by the transactions.
+// Failing to resolve them isn't fatal, the category IDs are exported instead.
+func (m *TransactionExporter) fetchCustomCategoryNames(ctx context.Context, accountID monzo.AccountID, transactions []*monzo.Transaction) map[string]string {
+	usesCustomCategory := lo.ContainsBy(transactions, func(txn *monzo.Transaction) bool {
+		return monzo.IsCustomCategory(txn.CategoryName) || lo.ContainsBy(lo.Keys(txn.Categories), monzo.IsCustomCategory)
+	})
+	if !usesCustomCategory {
+		return nil
+	}
+
+	categories, err := m.api.FetchCategories(ctx, accountID)
+	if err != nil {
+		log.FromContext(ctx).WarnContext(ctx, "could not fetch custom category names, exporting category IDs instead",
+			slog.String("account.id", string(accountID)),
+			slog.Any("err", err),
+		)
+
+		return nil
+	}
+
+	return lo.SliceToMap(categories, func(category *monzo.Category) (string, string) {
+		return category.ID, category.Name
+	})
+}
+
+// fetchAccount returns the account matching the selector, which may be an account ID, type (e.g. uk_retail_joint, or
+// just joint) or description. An empty selector picks the first open account.
+func (m *TransactionExporter) fetchAccount(ctx context.Context, selector string) (*monzo.Account, error) {
 	accounts, err := m.api.FetchAccounts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("fetch accounts: %w", err)
@@ -119,31 +264,60 @@ func (m *TransactionExporter) fetchAccount(ctx context.Context, accountID string
 		return nil, errors.New("no accounts found, exiting")
 	}
 
-	selectedAccount := accounts[0]
-	accountIDs := make([]monzo.AccountID, 0)
-	for _, account := range accounts {
-		if accountID == string(account.ID) {
-			selectedAccount = account
-		}
-
-		accountIDs = append(accountIDs, account.ID)
-	}
-
 	log.FromContext(ctx).InfoContext(ctx, "found accounts",
-		slog.Int("account.total", len(accountIDs)),
+		slog.Int("account.total", len(accounts)),
 	)
 
+	selectedAccount, err := selectAccount(accounts, selector)
+	if err != nil {
+		return nil, err
+	}
+
 	log.FromContext(ctx).InfoContext(ctx, "selected account",
 		slog.String("account.id", string(selectedAccount.ID)),
+		slog.String("account.type", selectedAccount.Type),
 	)
 
 	return selectedAccount, nil
 }
 
-func (m *TransactionExporter) fetchTransactions(ctx context.Context, accountID monzo.AccountID, startDate time.Time, endDate time.Time) ([]*monzo.Transaction, error) {
-	var transactions []*monzo.Transaction
-	var sinceID monzo.TransactionID
+func selectAccount(accounts []*monzo.Account, selector string) (*monzo.Account, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		if account, ok := lo.Find(accounts, func(account *monzo.Account) bool { return !account.Closed }); ok {
+			return account, nil
+		}
+
+		return accounts[0], nil
+	}
+
+	if account, ok := lo.Find(accounts, func(account *monzo.Account) bool { return string(account.ID) == selector }); ok {
+		return account, nil
+	}
+
+	matches := lo.Filter(accounts, func(account *monzo.Account, _ int) bool {
+		return !account.Closed && (strings.EqualFold(account.Description, selector) || strings.EqualFold(account.Type, selector))
+	})
+
+	// Only fall back to part of a type, e.g. joint, when nothing matches exactly, so uk_retail doesn't match uk_retail_joint
+	if len(matches) == 0 {
+		matches = lo.Filter(accounts, func(account *monzo.Account, _ int) bool {
+			return !account.Closed && strings.Contains(strings.ToLower(account.Type), strings.ToLower(selector))
+		})
+	}
 
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no open account matches %q", selector)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := lo.Map(matches, func(account *monzo.Account, _ int) string { return string(account.ID) })
+		return nil, fmt.Errorf("%q matches %d accounts (%s), use an account ID instead", selector, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+func (m *TransactionExporter) fetchTransactions(ctx context.Context, accountID monzo.AccountID, startDate time.Time, endDate time.Time, audit bool) ([]*monzo.Transaction, error) {
 	endDateExclusive := endDate.AddDate(0, 0, 1)
 	limit := monzoTransactionBatch
 
@@ -152,56 +326,26 @@ func (m *TransactionExporter) fetchTransactions(ctx context.Context, accountID m
 		slog.String("start", startDate.Format(monzoTimeFormat)),
 		slog.String("end", endDate.Format(monzoTimeFormat)),
 		slog.Int("limit", int(limit)),
+		slog.Bool("audit", audit),
 	)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, fmt.Errorf("fetch transactions: %w", ctx.Err())
-		default:
-		}
+	paginator := monzo.NewTransactionPaginator(m.api, monzo.PaginatorOptions{
+		AccountID: accountID,
+		Start:     startDate,
+		End:       endDateExclusive,
+		PageSize:  limit,
+	})
 
-		transactionDtos, err := m.api.FetchTransactionsSince(ctx, monzo.FetchTransactionOptions{
-			AccountID: accountID,
-			Start:     startDate,
-			End:       endDate,
-			SinceID:   sinceID,
-			Limit:     limit,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("fetch transactions: %w", err)
-		}
-
-		if len(transactionDtos) == 0 {
-			break
-		}
-
-		latest := transactionDtos[0]
-		for _, transaction := range transactionDtos {
-			if transaction.CreatedAt.After(latest.CreatedAt) {
-				latest = transaction
-			}
-
-			isActiveCardCheck := transaction.Amount.MinorUnit == 0 && transaction.Metadata["notes"] == "Active card check"
-			if isActiveCardCheck {
-				continue
-			}
-
-			isNotDeclined := transaction.DeclineReason == ""
-			inDesiredDateRange := endDate.IsZero() || !transaction.CreatedAt.After(endDateExclusive)
-
-			if inDesiredDateRange && isNotDeclined {
-				transactions = append(transactions, transaction)
-			}
-		}
+	transactionDtos, err := paginator.All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch transactions: %w", err)
+	}
 
-		sinceID = latest.ID
+	transactions := lo.Filter(transactionDtos, func(transaction *monzo.Transaction, _ int) bool {
+		isNotDeclined := transaction.DeclineReason == ""
 
-		// Monzo doesn't support cursor AND time pagination
-		if latest.CreatedAt.After(endDateExclusive) {
-			break
-		}
-	}
+		return audit || (!isActiveCardCheck(transaction) && isNotDeclined)
+	})
 
 	log.FromContext(ctx).InfoContext(ctx, "fetched transactions",
 		slog.String("account.id", string(accountID)),
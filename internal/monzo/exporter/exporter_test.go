@@ -2,6 +2,7 @@ package exporter_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -34,6 +35,11 @@ func TestNew(t *testing.T) {
 		require.NotNil(t, exporter)
 		require.Equal(t, 90*24*time.Hour, exporter.MaxDateRange())
 		require.Equal(t, monzoexporter.ExportTypeMonzo, exporter.Type())
+		require.Equal(t, []string{"accounts", "transactions", "pending", "declines"}, exporter.Capabilities().Names())
+
+		metadata, registered := export.Lookup(exporter.Type())
+		require.True(t, registered)
+		require.Equal(t, exporter.Capabilities(), metadata.Capabilities)
 	})
 }
 
@@ -49,9 +55,16 @@ func TestExportAccounts(t *testing.T) {
 
 		accounts := []*monzo.Account{
 			{
-				ID:        monzo.AccountID(accountID),
-				CreatedAt: now,
-				Type:      "uk_retail",
+				ID:            monzo.AccountID(accountID),
+				CreatedAt:     now,
+				Type:          "uk_retail",
+				Description:   "user_12345",
+				Currency:      "GBP",
+				SortCode:      "040004",
+				AccountNumber: "12345678",
+				Owners: []*monzo.Owner{
+					{PreferredName: "John Smith"},
+				},
 			},
 		}
 
@@ -74,10 +87,94 @@ func TestExportAccounts(t *testing.T) {
 		require.Len(t, accounts, 1)
 		require.Equal(t, string(accountID), accounts[0].ID)
 		require.Equal(t, "uk_retail", accounts[0].Type)
+		require.Equal(t, "user_12345", accounts[0].Name)
+		require.Equal(t, "GBP", accounts[0].Currency)
+		require.Equal(t, "040004", accounts[0].SortCode)
+		require.Equal(t, "12345678", accounts[0].AccountNumber)
+		require.Equal(t, []string{"John Smith"}, accounts[0].Owners)
+		require.False(t, accounts[0].Closed)
 		require.WithinDuration(t, now, accounts[0].CreatedAt, time.Second)
 	})
 }
 
+func TestExportTransactionsAccountSelection(t *testing.T) {
+	t.Parallel()
+
+	accounts := []*monzo.Account{
+		{ID: "acc_closed", Type: "uk_retail", Description: "user_1", Closed: true},
+		{ID: "acc_retail", Type: "uk_retail", Description: "user_1"},
+		{ID: "acc_joint", Type: "uk_retail_joint", Description: "joint_1"},
+		{ID: "acc_flex", Type: "flex", Description: "flex_1"},
+		{ID: "acc_flex_2", Type: "flex", Description: "flex_2"},
+	}
+
+	tests := map[string]struct {
+		selector          string
+		expectedAccountID monzo.AccountID
+		expectedErrMsg    string
+	}{
+		"selects first open account by default": {
+			expectedAccountID: "acc_retail",
+		},
+		"selects by account ID": {
+			selector:          "acc_closed",
+			expectedAccountID: "acc_closed",
+		},
+		"selects by account type": {
+			selector:          "uk_retail_joint",
+			expectedAccountID: "acc_joint",
+		},
+		"selects by exact account type before partial account types": {
+			selector:          "UK_RETAIL",
+			expectedAccountID: "acc_retail",
+		},
+		"selects by partial account type": {
+			selector:          "Joint",
+			expectedAccountID: "acc_joint",
+		},
+		"selects by description": {
+			selector:          "flex_2",
+			expectedAccountID: "acc_flex_2",
+		},
+		"returns error when selector is ambiguous": {
+			selector:       "flex",
+			expectedErrMsg: `"flex" matches 2 accounts (acc_flex, acc_flex_2), use an account ID instead`,
+		},
+		"returns error when nothing matches": {
+			selector:       "business",
+			expectedErrMsg: `no open account matches "business"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &StubClient{
+				Accounts: accounts,
+			}
+
+			exporter, err := monzoexporter.New(client)
+			require.NoError(t, err)
+
+			_, err = exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				StartDate: time.Now().Add(-24 * time.Hour),
+				EndDate:   time.Now(),
+				AccountID: test.selector,
+				Options: export.Options{
+					AuthToken: "test-token",
+				},
+			})
+
+			if test.expectedErrMsg != "" {
+				require.EqualError(t, err, test.expectedErrMsg)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.expectedAccountID, client.RequestedAccountID)
+			}
+		})
+	}
+}
+
 func TestExportTransactions(t *testing.T) {
 	t.Parallel()
 
@@ -108,8 +205,61 @@ func TestExportTransactions(t *testing.T) {
 
 	tests := map[string]struct {
 		transactions         []*monzo.Transaction
+		audit                bool
 		expectedTransactions []*domain.Transaction
 	}{
+		"includes declined transactions and card checks in audit mode": {
+			audit: true,
+			transactions: []*monzo.Transaction{
+				{
+					ID:            "tx_1",
+					DeclineReason: "INSUFFICIENT_FUNDS",
+					Description:   "declined",
+					CreatedAt:     now,
+					Amount: domain.Money{
+						MinorUnit: -118,
+						Currency:  "GBP",
+					},
+				},
+				{
+					ID:          "tx_2",
+					Description: "active card check",
+					CreatedAt:   now,
+					Amount: domain.Money{
+						MinorUnit: 0,
+						Currency:  "GBP",
+					},
+					Metadata: map[string]string{
+						"notes": "Active card check",
+					},
+				},
+			},
+			expectedTransactions: []*domain.Transaction{
+				{
+					ID: "tx_1",
+					Amount: domain.Money{
+						MinorUnit: -118,
+						Currency:  "GBP",
+					},
+					Reference:     "declined",
+					CreatedAt:     now,
+					BankName:      "Monzo",
+					Status:        domain.TransactionStatusDeclined,
+					DeclineReason: "INSUFFICIENT_FUNDS",
+				},
+				{
+					ID: "tx_2",
+					Amount: domain.Money{
+						MinorUnit: 0,
+						Currency:  "GBP",
+					},
+					Reference: "active card check",
+					CreatedAt: now,
+					BankName:  "Monzo",
+					Status:    domain.TransactionStatusCardCheck,
+				},
+			},
+		},
 		"excludes declined transactions": {
 			transactions: []*monzo.Transaction{
 				{
@@ -188,6 +338,7 @@ func TestExportTransactions(t *testing.T) {
 					CreatedAt: now,
 					IsDeposit: false,
 					BankName:  "Monzo",
+					Status:    domain.TransactionStatusSettled,
 					Notes:     "",
 				},
 			},
@@ -237,6 +388,7 @@ func TestExportTransactions(t *testing.T) {
 					CreatedAt: now,
 					IsDeposit: false,
 					BankName:  "Monzo",
+					Status:    domain.TransactionStatusSettled,
 					Notes:     "Tesco",
 				},
 				{
@@ -249,6 +401,7 @@ func TestExportTransactions(t *testing.T) {
 					CreatedAt: now,
 					IsDeposit: false,
 					BankName:  "Monzo",
+					Status:    domain.TransactionStatusSettled,
 					Notes:     "Beers", // should not overwrite notes when we've set them in the app
 				},
 			},
@@ -266,6 +419,7 @@ func TestExportTransactions(t *testing.T) {
 					StartDate: time.Now().Add(-24 * time.Hour),
 					EndDate:   time.Now(),
 					AccountID: string(account.ID),
+					Audit:     test.audit,
 					Options: export.Options{
 						Timeout:   10 * time.Second,
 						AuthToken: "test-token",
@@ -279,16 +433,96 @@ func TestExportTransactions(t *testing.T) {
 	}
 }
 
+func TestExportTransactionSplits(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	accountID := monzo.AccountID("acc_12345")
+	transaction := &monzo.Transaction{
+		ID:           "tx_1",
+		Description:  "Supermarket",
+		CreatedAt:    now,
+		CategoryName: "category_00009abcdef",
+		Amount: domain.Money{
+			MinorUnit: -2000,
+			Currency:  "GBP",
+		},
+		Categories: map[string]int64{
+			"groceries":            -1500,
+			"category_00009abcdef": -500,
+		},
+	}
+
+	tests := map[string]struct {
+		categories       []*monzo.Category
+		fetchCategoryErr error
+		expectedCategory string
+		expectedSplits   []domain.Split
+	}{
+		"resolves custom category names": {
+			categories: []*monzo.Category{
+				{ID: "category_00009abcdef", Name: "Household", Custom: true},
+			},
+			expectedCategory: "Household",
+			expectedSplits: []domain.Split{
+				{Category: "Household", Amount: domain.Money{MinorUnit: -500, Currency: "GBP"}},
+				{Category: "groceries", Amount: domain.Money{MinorUnit: -1500, Currency: "GBP"}},
+			},
+		},
+		"falls back to category IDs when names cannot be fetched": {
+			fetchCategoryErr: errors.New("not found"),
+			expectedCategory: "category_00009abcdef",
+			expectedSplits: []domain.Split{
+				{Category: "category_00009abcdef", Amount: domain.Money{MinorUnit: -500, Currency: "GBP"}},
+				{Category: "groceries", Amount: domain.Money{MinorUnit: -1500, Currency: "GBP"}},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := &StubClient{
+				Accounts:         []*monzo.Account{{ID: accountID}},
+				Categories:       test.categories,
+				FetchCategoryErr: test.fetchCategoryErr,
+				Transactions:     [][]*monzo.Transaction{{transaction}},
+			}
+
+			exporter, err := monzoexporter.New(client)
+			require.NoError(t, err)
+
+			res, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				StartDate: now.Add(-24 * time.Hour),
+				EndDate:   now,
+				AccountID: string(accountID),
+				Options: export.Options{
+					AuthToken: "test-token",
+				},
+			})
+
+			require.NoError(t, err)
+			require.Len(t, res, 1)
+			require.Equal(t, test.expectedCategory, res[0].Category)
+			require.Equal(t, test.expectedSplits, res[0].Splits)
+		})
+	}
+}
+
 var _ monzo.Client = (*StubClient)(nil)
 
 type StubClient struct {
 	Accounts         []*monzo.Account
 	Pots             []*monzo.Pot
+	Categories       []*monzo.Category
 	Transactions     [][]*monzo.Transaction
 	FetchAccountsErr error
 	FetchPotErr      error
+	FetchCategoryErr error
 	FetchTxnsErr     error
 	callCount        int
+
+	RequestedAccountID monzo.AccountID
 }
 
 func (c *StubClient) FetchTransactionsSince(ctx context.Context, opts monzo.FetchTransactionOptions) ([]*monzo.Transaction, error) {
@@ -296,6 +530,7 @@ func (c *StubClient) FetchTransactionsSince(ctx context.Context, opts monzo.Fetc
 		return nil, c.FetchTxnsErr
 	}
 
+	c.RequestedAccountID = opts.AccountID
 	c.callCount++
 	index := c.callCount - 1
 
@@ -326,3 +561,11 @@ func (c *StubClient) FetchPots(ctx context.Context, accountID monzo.AccountID) (
 
 	return c.Pots, nil
 }
+
+func (c *StubClient) FetchCategories(ctx context.Context, accountID monzo.AccountID) ([]*monzo.Category, error) {
+	if c.FetchCategoryErr != nil {
+		return nil, c.FetchCategoryErr
+	}
+
+	return c.Categories, nil
+}
@@ -0,0 +1,184 @@
+package exporter
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/monzo"
+)
+
+const historyKeyPrefix = "monzo-history-"
+
+// ErrVerificationRequired is returned when Monzo refuses to list transactions older than 90 days.
+// Monzo only allows the full transaction history to be fetched within 5 minutes of authenticating.
+var ErrVerificationRequired = errors.New("monzo only returns transactions older than 90 days within 5 minutes of authenticating, " +
+	"run `fingrab monzo backfill` immediately after logging in to store your full history locally")
+
+// history is the locally persisted transaction history for a single account.
+type history struct {
+	AccountID    string                `json:"accountId"`
+	From         time.Time             `json:"from"` // Start of the backfilled range (the account's creation date)
+	To           time.Time             `json:"to"`   // End of the backfilled range
+	Transactions []*domain.Transaction `json:"transactions"`
+}
+
+// Backfill walks the account's transaction history, from the account's creation date until now, in windows of
+// MaxDateRange and persists it to the exporter's store. It must be run within 5 minutes of authenticating,
+// after which Monzo only returns the last 90 days of transactions.
+// It returns the total number of transactions stored for the account.
+func (m *TransactionExporter) Backfill(ctx context.Context, accountID string) (int, error) {
+	if m.store == nil {
+		return 0, errors.New("history store is required")
+	}
+
+	account, err := m.fetchAccount(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	hist, _, err := m.loadHistory(account.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	start := account.CreatedAt.Truncate(24 * time.Hour)
+	hist.From = start
+
+	log.FromContext(ctx).InfoContext(ctx, "starting backfill of transaction history",
+		slog.String("account.id", string(account.ID)),
+		slog.String("backfill.start", start.Format(monzoTimeFormat)),
+	)
+
+	for windowStart := start; windowStart.Before(now); windowStart = windowStart.Add(monzoMaxDateRange) {
+		windowEnd := windowStart.Add(monzoMaxDateRange)
+		if windowEnd.After(now) {
+			windowEnd = now
+		}
+
+		transactions, err := m.exportTransactions(ctx, account.ID, windowStart, windowEnd, false)
+		if err != nil {
+			if monzo.IsVerificationRequired(err) {
+				return 0, fmt.Errorf("backfill %s: %w: %w", windowStart.Format(monzoTimeFormat), ErrVerificationRequired, err)
+			}
+
+			return 0, fmt.Errorf("backfill %s: %w", windowStart.Format(monzoTimeFormat), err)
+		}
+
+		hist.Transactions = mergeTransactions(hist.Transactions, transactions)
+		hist.To = windowEnd
+
+		// Persist after each window so progress isn't lost if the verification window expires part way through
+		if err := m.store.Save(historyKey(account.ID), hist); err != nil {
+			return 0, fmt.Errorf("save history: %w", err)
+		}
+
+		log.FromContext(ctx).InfoContext(ctx, "backfilled transactions",
+			slog.String("window.start", windowStart.Format(monzoTimeFormat)),
+			slog.String("window.end", windowEnd.Format(monzoTimeFormat)),
+			slog.Int("transaction.count", len(transactions)),
+		)
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "successfully backfilled transaction history",
+		slog.String("account.id", string(account.ID)),
+		slog.Int("transaction.total", len(hist.Transactions)),
+	)
+
+	return len(hist.Transactions), nil
+}
+
+// exportFromHistory serves transactions from the backfilled history, fetching anything newer than the backfill from the API.
+// verificationErr is returned (wrapped) when there is no backfilled history to fall back on.
+// The backfilled history never includes declined transactions, so audit only applies to transactions fetched from the API.
+func (m *TransactionExporter) exportFromHistory(ctx context.Context, accountID monzo.AccountID, startDate time.Time, endDate time.Time, audit bool, verificationErr error) ([]*domain.Transaction, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("%w: %w", ErrVerificationRequired, verificationErr)
+	}
+
+	hist, found, err := m.loadHistory(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %w", ErrVerificationRequired, verificationErr)
+	}
+
+	endDateExclusive := endDate.AddDate(0, 0, 1)
+	transactions := make([]*domain.Transaction, 0)
+	for _, txn := range hist.Transactions {
+		if !txn.CreatedAt.Before(startDate) && txn.CreatedAt.Before(endDateExclusive) {
+			transactions = append(transactions, txn)
+		}
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "loaded transactions from backfilled history",
+		slog.String("account.id", string(accountID)),
+		slog.String("history.to", hist.To.Format(monzoTimeFormat)),
+		slog.Int("transaction.count", len(transactions)),
+	)
+
+	if !endDate.After(hist.To) {
+		return transactions, nil
+	}
+
+	// The requested range extends past the backfill, so the remainder must come from the API
+	recent, err := m.exportTransactions(ctx, accountID, hist.To, endDate, audit)
+	if err != nil {
+		if monzo.IsVerificationRequired(err) {
+			return nil, fmt.Errorf("%w: %w", ErrVerificationRequired, err)
+		}
+
+		return nil, err
+	}
+
+	return mergeTransactions(transactions, recent), nil
+}
+
+func (m *TransactionExporter) loadHistory(accountID monzo.AccountID) (*history, bool, error) {
+	hist := &history{
+		AccountID: string(accountID),
+	}
+
+	found, err := m.store.Load(historyKey(accountID), hist)
+	if err != nil {
+		return nil, false, fmt.Errorf("load history: %w", err)
+	}
+
+	return hist, found, nil
+}
+
+func historyKey(accountID monzo.AccountID) string {
+	return historyKeyPrefix + string(accountID)
+}
+
+// mergeTransactions combines both slices, replacing existing transactions with updated ones of the same ID,
+// and returns them sorted by creation time.
+func mergeTransactions(existing []*domain.Transaction, updated []*domain.Transaction) []*domain.Transaction {
+	merged := make([]*domain.Transaction, 0, len(existing)+len(updated))
+	index := make(map[string]int, len(existing)+len(updated))
+
+	for _, txn := range slices.Concat(existing, updated) {
+		if i, ok := index[txn.ID]; ok && txn.ID != "" {
+			merged[i] = txn
+			continue
+		}
+
+		index[txn.ID] = len(merged)
+		merged = append(merged, txn)
+	}
+
+	slices.SortStableFunc(merged, func(a, b *domain.Transaction) int {
+		return cmp.Compare(a.CreatedAt.UnixNano(), b.CreatedAt.UnixNano())
+	})
+
+	return merged
+}
@@ -0,0 +1,142 @@
+package exporter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/monzo"
+	monzoexporter "github.com/HallyG/fingrab/internal/monzo/exporter"
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfill(t *testing.T) {
+	t.Parallel()
+
+	accountID := monzo.AccountID("acc_12345")
+	now := time.Now()
+	verificationErr := &monzo.Error{
+		Code:    monzo.ErrorCodeVerificationRequired,
+		Message: "Verification required",
+	}
+
+	setup := func(t *testing.T, s *store.Store) (*StubClient, *monzoexporter.TransactionExporter) {
+		t.Helper()
+
+		client := &StubClient{
+			Accounts: []*monzo.Account{
+				{
+					ID:        accountID,
+					CreatedAt: now.AddDate(0, 0, -100),
+				},
+			},
+			Transactions: [][]*monzo.Transaction{
+				{
+					{
+						ID:          "tx_1",
+						Description: "old",
+						CreatedAt:   now.AddDate(0, 0, -95),
+						Amount:      domain.Money{MinorUnit: -100, Currency: "GBP"},
+					},
+				},
+				{
+					{
+						ID:          "tx_2",
+						Description: "recent",
+						CreatedAt:   now.AddDate(0, 0, -2),
+						Amount:      domain.Money{MinorUnit: -200, Currency: "GBP"},
+					},
+				},
+			},
+		}
+
+		exporter, err := monzoexporter.New(client, monzoexporter.WithStore(s))
+		require.NoError(t, err)
+
+		return client, exporter
+	}
+
+	newStore := func(t *testing.T) *store.Store {
+		t.Helper()
+
+		s, err := store.New(t.TempDir())
+		require.NoError(t, err)
+
+		return s
+	}
+
+	transactionOpts := export.TransactionOptions{
+		StartDate: now.AddDate(0, 0, -120),
+		EndDate:   now.Add(-24 * time.Hour),
+		AccountID: string(accountID),
+		Options: export.Options{
+			AuthToken: "test-token",
+		},
+	}
+
+	t.Run("returns error when store is not configured", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := monzoexporter.New(&StubClient{})
+		require.NoError(t, err)
+
+		count, err := exporter.Backfill(t.Context(), string(accountID))
+
+		require.Zero(t, count)
+		require.ErrorContains(t, err, "history store is required")
+	})
+
+	t.Run("stores history in windows from account creation", func(t *testing.T) {
+		t.Parallel()
+
+		_, exporter := setup(t, newStore(t))
+
+		count, err := exporter.Backfill(t.Context(), string(accountID))
+
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("returns verification error when backfill is too late", func(t *testing.T) {
+		t.Parallel()
+
+		client, exporter := setup(t, newStore(t))
+		client.FetchTxnsErr = verificationErr
+
+		_, err := exporter.Backfill(t.Context(), string(accountID))
+
+		require.ErrorIs(t, err, monzoexporter.ErrVerificationRequired)
+	})
+
+	t.Run("falls back to history when verification is required", func(t *testing.T) {
+		t.Parallel()
+
+		client, exporter := setup(t, newStore(t))
+		_, err := exporter.Backfill(t.Context(), string(accountID))
+		require.NoError(t, err)
+
+		client.FetchTxnsErr = verificationErr
+		transactions, err := exporter.ExportTransactions(t.Context(), transactionOpts)
+
+		require.NoError(t, err)
+		require.Len(t, transactions, 2)
+		require.Equal(t, "tx_1", transactions[0].ID)
+		require.Equal(t, "old", transactions[0].Reference)
+		require.Equal(t, "tx_2", transactions[1].ID)
+	})
+
+	t.Run("returns verification error when no history exists", func(t *testing.T) {
+		t.Parallel()
+
+		client, exporter := setup(t, newStore(t))
+		client.FetchTxnsErr = verificationErr
+
+		transactions, err := exporter.ExportTransactions(t.Context(), transactionOpts)
+
+		require.Nil(t, transactions)
+		require.ErrorIs(t, err, monzoexporter.ErrVerificationRequired)
+		require.True(t, monzo.IsVerificationRequired(err))
+	})
+}
@@ -0,0 +1,52 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/monzo"
+	"github.com/HallyG/fingrab/internal/oauth"
+	"github.com/HallyG/fingrab/internal/store"
+)
+
+const description = "Commands for interacting with the Monzo API"
+
+func init() {
+	export.Register(ExportTypeMonzo, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName(Monzo),
+		export.WithDescription(description),
+		export.WithOAuth(func() oauth.Config {
+			return oauth.Config{
+				AuthURL:              "https://auth.monzo.com",
+				TokenURL:             "https://api.monzo.com/oauth2/token",
+				WaitForApprovalInApp: true,
+			}
+		}),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+// NewFromOptions returns an exporter of the Monzo API, which keeps backfilled history in the data directory when
+// there is one.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+
+	var exporterOpts []Option
+	if opts.DataDir != "" {
+		s, err := store.New(opts.DataDir)
+		if err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+
+		exporterOpts = append(exporterOpts, WithStore(s))
+	}
+
+	api := monzo.New(client, api.WithAuthToken(opts.BearerAuthToken()))
+	return New(api, exporterOpts...)
+}
@@ -17,6 +17,7 @@ const (
 	prodAPI              = "https://api.monzo.com"
 	getAccountsRoute     = "/accounts"
 	getPotsRoute         = "/pots"
+	getCategoriesRoute   = "/categories"
 	getTransactionsRoute = "/transactions"
 	getTransactionRoute  = getTransactionsRoute + "/%s"
 	maxResultPerPage     = 100
@@ -28,6 +29,7 @@ type (
 	Client interface {
 		FetchAccounts(ctx context.Context) ([]*Account, error)
 		FetchPots(ctx context.Context, accountID AccountID) ([]*Pot, error)
+		FetchCategories(ctx context.Context, accountID AccountID) ([]*Category, error)
 		FetchTransaction(ctx context.Context, transactionID TransactionID) (*Transaction, error)
 		FetchTransactionsSince(ctx context.Context, opts FetchTransactionOptions) ([]*Transaction, error)
 	}
@@ -81,6 +83,20 @@ func (c *client) FetchPots(ctx context.Context, accountID AccountID) ([]*Pot, er
 	return result.Pots, nil
 }
 
+func (c *client) FetchCategories(ctx context.Context, accountID AccountID) ([]*Category, error) {
+	values := url.Values{}
+	values.Add("account_id", string(accountID))
+
+	result, err := api.ExecuteRequest[struct {
+		Categories []*Category `json:"categories"`
+	}](ctx, c.api, http.MethodGet, getCategoriesRoute, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Categories, nil
+}
+
 func (c *client) FetchTransaction(ctx context.Context, transactionID TransactionID) (*Transaction, error) {
 	result, err := api.ExecuteRequest[struct {
 		Transaction *Transaction `json:"transaction"`
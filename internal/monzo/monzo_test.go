@@ -140,6 +140,7 @@ func TestFetchTransaction(t *testing.T) {
 				t.Helper()
 
 				require.Equal(t, transactionId, item.ID)
+				require.Equal(t, map[string]int64{"transport": -280}, item.Categories)
 			},
 		},
 	}
@@ -219,6 +220,67 @@ func TestFetchPots(t *testing.T) {
 	}
 }
 
+func TestFetchCategories(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		route              testhelper.HTTPTestRoute
+		expectedCategories []*monzo.Category
+		expectedMonzoErr   *monzo.Error
+		expectedErrMsg     string
+	}{
+		"successful fetch": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    "/categories",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					query := url.Values{}
+					header.Add("Authorization", token)
+					query.Add("account_id", string(accountId))
+
+					testhelper.AssertRequest(t, r, http.MethodGet, header, query)
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "categories.json")(w, r)
+				},
+			},
+			expectedCategories: []*monzo.Category{
+				{ID: "category_00009abcdef", Name: "Household", Custom: true},
+				{ID: "groceries", Name: "Groceries"},
+			},
+		},
+		"returns API error": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    "/categories",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusNotFound, "error.json")(w, r)
+				},
+			},
+			expectedMonzoErr: &monzo.Error{
+				Code:    "not_found",
+				Message: "/a not found",
+			},
+			expectedErrMsg: "/a not found (code=not_found)",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, test.route)
+			categories, err := client.FetchCategories(t.Context(), accountId)
+
+			if test.expectedMonzoErr != nil {
+				require.Empty(t, categories)
+				requireMonzoErrorEqual(t, *test.expectedMonzoErr, test.expectedErrMsg, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.expectedCategories, categories)
+			}
+		})
+	}
+}
+
 func TestFetchTransactions(t *testing.T) {
 	t.Parallel()
 
@@ -319,6 +381,38 @@ func TestFetchTransactions(t *testing.T) {
 	}
 }
 
+func TestIsVerificationRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"returns true for verification required error": {
+			err:      &monzo.Error{Code: monzo.ErrorCodeVerificationRequired},
+			expected: true,
+		},
+		"returns true for wrapped verification required error": {
+			err:      fmt.Errorf("fetch transactions: %w", &monzo.Error{Code: monzo.ErrorCodeVerificationRequired}),
+			expected: true,
+		},
+		"returns false for other monzo error": {
+			err: &monzo.Error{Code: "bad_request.invalid_time_range"},
+		},
+		"returns false for other error": {
+			err: errors.New("boom"),
+		},
+		"returns false for nil error": {},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.expected, monzo.IsVerificationRequired(test.err))
+		})
+	}
+}
+
 func requireMonzoErrorEqual(t *testing.T, expectedErr monzo.Error, expectedErrMsg string, err error) {
 	t.Helper()
 
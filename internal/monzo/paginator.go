@@ -0,0 +1,159 @@
+package monzo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const defaultMaxPages = 1000
+
+var (
+	// ErrNoProgress is returned when a page does not advance the cursor, which would otherwise loop forever.
+	ErrNoProgress = errors.New("pagination made no progress")
+	// ErrMaxPages is returned when more pages than PaginatorOptions.MaxPages would be requested.
+	ErrMaxPages = errors.New("pagination exceeded maximum number of pages")
+)
+
+type PaginatorOptions struct {
+	AccountID AccountID
+	Start     time.Time // Inclusive
+	End       time.Time // Exclusive, sent to Monzo as `before`. A zero time fetches up to now.
+	PageSize  uint16    // Defaults to the maximum page size Monzo allows.
+	MaxPages  int       // Defaults to 1000.
+}
+
+func (po PaginatorOptions) Validate(ctx context.Context) error {
+	return validation.ValidateStructWithContext(ctx, &po,
+		validation.Field(&po.AccountID, validation.Required.Error("is required")),
+		validation.Field(&po.Start, validation.Required.Error("is required")),
+		validation.Field(&po.PageSize, validation.Max(uint16(maxResultPerPage)).Error(fmt.Sprintf("must be no greater than %d", maxResultPerPage))),
+		validation.Field(&po.MaxPages, validation.Min(0).Error("must not be negative")),
+	)
+}
+
+// TransactionPaginator pages through an account's transactions in a time range.
+// The first page is requested with the start time as `since`, and each subsequent page with the ID of the
+// last transaction seen. It stops on a short page or once a transaction at or after End is seen, and returns
+// an error rather than looping when a page does not advance the cursor or MaxPages is exceeded.
+type TransactionPaginator struct {
+	client Client
+	opts   PaginatorOptions
+	cursor TransactionID
+	seen   map[TransactionID]struct{}
+	pages  int
+	done   bool
+}
+
+func NewTransactionPaginator(client Client, opts PaginatorOptions) *TransactionPaginator {
+	if opts.PageSize == 0 {
+		opts.PageSize = maxResultPerPage
+	}
+
+	if opts.MaxPages == 0 {
+		opts.MaxPages = defaultMaxPages
+	}
+
+	return &TransactionPaginator{
+		client: client,
+		opts:   opts,
+		seen:   make(map[TransactionID]struct{}),
+	}
+}
+
+// Done reports whether all pages have been fetched.
+func (p *TransactionPaginator) Done() bool {
+	return p.done
+}
+
+// Next fetches the next page of transactions. Transactions already returned by a previous page,
+// and transactions at or after End, are omitted.
+func (p *TransactionPaginator) Next(ctx context.Context) ([]*Transaction, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	if p.pages == 0 {
+		if err := p.opts.Validate(ctx); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	if p.pages >= p.opts.MaxPages {
+		return nil, fmt.Errorf("%w (%d)", ErrMaxPages, p.opts.MaxPages)
+	}
+
+	page, err := p.client.FetchTransactionsSince(ctx, FetchTransactionOptions{
+		AccountID: p.opts.AccountID,
+		Start:     p.opts.Start,
+		End:       p.opts.End,
+		SinceID:   p.cursor,
+		Limit:     p.opts.PageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("page %d: %w", p.pages+1, err)
+	}
+
+	p.pages++
+
+	transactions := make([]*Transaction, 0, len(page))
+	for _, txn := range page {
+		if !p.opts.End.IsZero() && !txn.CreatedAt.Before(p.opts.End) {
+			// `before` wasn't honoured, anything further is also out of range
+			p.done = true
+			continue
+		}
+
+		if _, ok := p.seen[txn.ID]; ok {
+			continue
+		}
+
+		if txn.ID != "" {
+			p.seen[txn.ID] = struct{}{}
+		}
+
+		transactions = append(transactions, txn)
+	}
+
+	if len(page) < int(p.opts.PageSize) {
+		p.done = true
+	}
+
+	if p.done {
+		return transactions, nil
+	}
+
+	next := page[len(page)-1].ID
+	if next == "" || next == p.cursor || len(transactions) == 0 {
+		return nil, fmt.Errorf("%w: page %d (cursor %q)", ErrNoProgress, p.pages, p.cursor)
+	}
+
+	p.cursor = next
+
+	return transactions, nil
+}
+
+// All fetches every remaining page and returns the combined transactions.
+func (p *TransactionPaginator) All(ctx context.Context) ([]*Transaction, error) {
+	var transactions []*Transaction
+
+	for !p.Done() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		page, err := p.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		transactions = append(transactions, page...)
+	}
+
+	return transactions, nil
+}
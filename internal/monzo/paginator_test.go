@@ -0,0 +1,151 @@
+package monzo_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/monzo"
+	"github.com/HallyG/fingrab/internal/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionPaginator(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	// pages serves each fixture in turn, asserting the `since` cursor sent for each page.
+	pages := func(t *testing.T, requests *atomic.Int32, statusCode int, steps ...[2]string) http.HandlerFunc {
+		t.Helper()
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			index := int(requests.Add(1)) - 1
+			require.Less(t, index, len(steps), "unexpected request %d", index+1)
+
+			query := r.URL.Query()
+			require.Equal(t, steps[index][0], query.Get("since"), "since for request %d", index+1)
+			require.NotEmpty(t, query.Get("before"), "before for request %d", index+1)
+			testhelper.ServeJSONTestDataHandler(t, statusCode, steps[index][1])(w, r)
+		}
+	}
+
+	tests := map[string]struct {
+		opts             monzo.PaginatorOptions
+		statusCode       int
+		steps            [][2]string
+		expectedIDs      []monzo.TransactionID
+		expectedRequests int32
+		expectedErr      error
+		expectedErrMsg   string
+	}{
+		"single short page": {
+			opts: monzo.PaginatorOptions{End: end, PageSize: 3},
+			steps: [][2]string{
+				{start.Format(time.RFC3339), "transactions-page-1.json"},
+			},
+			expectedIDs:      []monzo.TransactionID{"tx_00001", "tx_00002"},
+			expectedRequests: 1,
+		},
+		"advances by transaction ID cursor across pages": {
+			opts: monzo.PaginatorOptions{End: end, PageSize: 2},
+			steps: [][2]string{
+				{start.Format(time.RFC3339), "transactions-page-1.json"},
+				{"tx_00002", "transactions-page-2.json"},
+			},
+			expectedIDs:      []monzo.TransactionID{"tx_00001", "tx_00002", "tx_00003"},
+			expectedRequests: 2,
+		},
+		"stops when a full page is followed by an empty page": {
+			opts: monzo.PaginatorOptions{End: end, PageSize: 1},
+			steps: [][2]string{
+				{start.Format(time.RFC3339), "transactions-page-2.json"},
+				{"tx_00003", "transactions-empty.json"},
+			},
+			expectedIDs:      []monzo.TransactionID{"tx_00003"},
+			expectedRequests: 2,
+		},
+		"omits transactions at or after before": {
+			opts: monzo.PaginatorOptions{End: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), PageSize: 2},
+			steps: [][2]string{
+				{start.Format(time.RFC3339), "transactions-page-1.json"},
+			},
+			expectedIDs:      []monzo.TransactionID{"tx_00001"},
+			expectedRequests: 1,
+		},
+		"returns error when page does not progress": {
+			opts: monzo.PaginatorOptions{End: end, PageSize: 2},
+			steps: [][2]string{
+				{start.Format(time.RFC3339), "transactions-page-1.json"},
+				{"tx_00002", "transactions-page-1.json"},
+			},
+			expectedRequests: 2,
+			expectedErr:      monzo.ErrNoProgress,
+		},
+		"returns error when max pages exceeded": {
+			opts: monzo.PaginatorOptions{End: end, PageSize: 2, MaxPages: 1},
+			steps: [][2]string{
+				{start.Format(time.RFC3339), "transactions-page-1.json"},
+			},
+			expectedRequests: 1,
+			expectedErr:      monzo.ErrMaxPages,
+		},
+		"returns API error": {
+			opts:       monzo.PaginatorOptions{End: end},
+			statusCode: http.StatusForbidden,
+			steps: [][2]string{
+				{start.Format(time.RFC3339), "error.json"},
+			},
+			expectedRequests: 1,
+			expectedErrMsg:   "page 1: /a not found (code=not_found)",
+		},
+		"returns error when page size too large": {
+			opts:           monzo.PaginatorOptions{End: end, PageSize: 101},
+			expectedErrMsg: "invalid options: PageSize: must be no greater than 100.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			statusCode := test.statusCode
+			if statusCode == 0 {
+				statusCode = http.StatusOK
+			}
+
+			requests := &atomic.Int32{}
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     "/transactions",
+				Handler: pages(t, requests, statusCode, test.steps...),
+			})
+
+			opts := test.opts
+			opts.AccountID = accountId
+			opts.Start = start
+
+			transactions, err := monzo.NewTransactionPaginator(client, opts).All(t.Context())
+
+			require.Equal(t, test.expectedRequests, requests.Load())
+			switch {
+			case test.expectedErr != nil:
+				require.Nil(t, transactions)
+				require.ErrorIs(t, err, test.expectedErr)
+			case test.expectedErrMsg != "":
+				require.Nil(t, transactions)
+				require.ErrorContains(t, err, test.expectedErrMsg)
+			default:
+				require.NoError(t, err)
+
+				ids := make([]monzo.TransactionID, 0, len(transactions))
+				for _, txn := range transactions {
+					ids = append(ids, txn.ID)
+				}
+
+				require.Equal(t, test.expectedIDs, ids)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package monzo
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/HallyG/fingrab/internal/domain"
@@ -16,6 +18,9 @@ type (
 	PotID         string
 )
 
+// customCategoryPrefix prefixes the IDs of user-defined categories, e.g. category_00009abcdef.
+const customCategoryPrefix = "category_"
+
 type Owner struct {
 	UserID             UserID `json:"user_id"`
 	PreferredName      string `json:"preferred_name"`
@@ -59,22 +64,34 @@ type Pot struct {
 	Currency string `json:"currency"`
 }
 
+type Category struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Custom bool   `json:"is_custom"`
+}
+
+// IsCustomCategory reports whether the category is user-defined, and so must be resolved to a name.
+func IsCustomCategory(category string) bool {
+	return strings.HasPrefix(category, customCategoryPrefix)
+}
+
 type Transaction struct {
-	ID              TransactionID `json:"id"`
-	Description     string        `json:"description"`
-	CreatedAt       time.Time     `json:"created"`
-	Amount          domain.Money  `json:"amount"`
-	UserNotes       string        `json:"notes"`
-	CategoryName    string        `json:"category"`
-	SettledAt       *time.Time    `json:"settled"`
-	LocalAmount     domain.Money  `json:"local_money"`
-	UpdatedAt       time.Time     `json:"updated"`
-	AccountID       AccountID     `json:"account_id"`
-	AmountIsPending bool          `json:"amount_is_pending"`
-	Scheme          string        `json:"scheme"`
-	Merchant        *Merchant     `json:"merchant"`
-	CounterParty    *CounterParty `json:"counterparty"`
-	DeclineReason   string        `json:"decline_reason"`
+	ID              TransactionID    `json:"id"`
+	Description     string           `json:"description"`
+	CreatedAt       time.Time        `json:"created"`
+	Amount          domain.Money     `json:"amount"`
+	UserNotes       string           `json:"notes"`
+	CategoryName    string           `json:"category"`
+	Categories      map[string]int64 `json:"categories"` // Minor unit amount per category, with more than one entry when split
+	SettledAt       *time.Time       `json:"settled"`
+	LocalAmount     domain.Money     `json:"local_money"`
+	UpdatedAt       time.Time        `json:"updated"`
+	AccountID       AccountID        `json:"account_id"`
+	AmountIsPending bool             `json:"amount_is_pending"`
+	Scheme          string           `json:"scheme"`
+	Merchant        *Merchant        `json:"merchant"`
+	CounterParty    *CounterParty    `json:"counterparty"`
+	DeclineReason   string           `json:"decline_reason"`
 	Metadata        map[string]string
 }
 
@@ -123,6 +140,10 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// ErrorCodeVerificationRequired is returned when requesting transactions older than 90 days more than
+// 5 minutes after the user authenticated (strong customer authentication).
+const ErrorCodeVerificationRequired = "forbidden.verification_required"
+
 type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
@@ -136,3 +157,14 @@ Error (statusCode=403, code=forbidden.verification_required, message=Verificatio
 func (err Error) Error() string {
 	return fmt.Sprintf("%s (code=%s)", err.Message, err.Code)
 }
+
+// IsVerificationRequired reports whether err is a Monzo strong customer authentication error,
+// returned when requesting transactions outside of the history window.
+func IsVerificationRequired(err error) bool {
+	var monzoErr *Error
+	if errors.As(err, &monzoErr) {
+		return monzoErr.Code == ErrorCodeVerificationRequired
+	}
+
+	return false
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strings"
 
 	"github.com/HallyG/fingrab/internal/log"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/int128/oauth2cli"
 	"github.com/pkg/browser"
+	"github.com/samber/lo"
 	"golang.org/x/oauth2"
 	"golang.org/x/sync/errgroup"
 )
@@ -22,6 +24,9 @@ type Config struct {
 	TokenURL             string
 	WaitForApprovalInApp bool // Wait for user input after token exchange (useful in the scenarios where additional approval is needed in a mobile app)
 	Scopes               []string
+	AuthParams           map[string]string // Additional query parameters of the authorization URL, e.g. the providers to choose from
+	RefreshToken         bool              // Keep the token between runs, and refresh it when it expires, rather than logging in each time
+	TokenKey             string            // Distinguishes kept tokens of the same client that aren't interchangeable, e.g. by environment
 }
 
 func (c *Config) Validate(ctx context.Context) error {
@@ -33,6 +38,12 @@ func (c *Config) Validate(ctx context.Context) error {
 	)
 }
 
+// StoredTokenKey returns the key the token is kept under, for the named flow. It includes the client ID and token
+// key, so a token issued to one client or environment isn't used with another.
+func (c *Config) StoredTokenKey(name string) string {
+	return strings.Join(lo.Compact([]string{name, c.TokenKey, c.ClientID, "oauth-token"}), "-")
+}
+
 func (c *Config) ToOAuth2Config() oauth2.Config {
 	return oauth2.Config{
 		ClientID:     c.ClientID,
@@ -46,15 +57,31 @@ func (c *Config) ToOAuth2Config() oauth2.Config {
 }
 
 func Exchange(ctx context.Context, cfg *Config, userInput io.Reader) (string, error) {
+	token, err := ExchangeToken(ctx, cfg, userInput)
+	if err != nil {
+		return "", err
+	}
+
+	return token.AccessToken, nil
+}
+
+// ExchangeToken runs the flow like Exchange, but returns the whole token, including its refresh token and expiry.
+func ExchangeToken(ctx context.Context, cfg *Config, userInput io.Reader) (*oauth2.Token, error) {
 	if err := cfg.Validate(ctx); err != nil {
-		return "", fmt.Errorf("invalid oauth2 config: %w", err)
+		return nil, fmt.Errorf("invalid oauth2 config: %w", err)
 	}
 
 	ready := make(chan string, 1)
 	defer close(ready)
 
+	authCodeOptions := make([]oauth2.AuthCodeOption, 0, len(cfg.AuthParams))
+	for key, value := range cfg.AuthParams {
+		authCodeOptions = append(authCodeOptions, oauth2.SetAuthURLParam(key, value))
+	}
+
 	token, err := exchangeToken(ctx, ready, &oauth2cli.Config{
 		OAuth2Config:           cfg.ToOAuth2Config(),
+		AuthCodeOptions:        authCodeOptions,
 		LocalServerReadyChan:   ready,
 		LocalServerBindAddress: []string{"localhost:64131"},
 		Logf: func(format string, args ...any) {
@@ -62,17 +89,17 @@ func Exchange(ctx context.Context, cfg *Config, userInput io.Reader) (string, er
 		},
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	log.FromContext(ctx).DebugContext(ctx, "exchanged oauth token")
 	if cfg.WaitForApprovalInApp {
 		if err := WaitForApprovalInApp(ctx, userInput); err != nil {
-			return "", fmt.Errorf("failed waiting for app approval: %w", err)
+			return nil, fmt.Errorf("failed waiting for app approval: %w", err)
 		}
 	}
 
-	return token.AccessToken, nil
+	return token, nil
 }
 
 func exchangeToken(ctx context.Context, ready chan string, cfg *oauth2cli.Config) (*oauth2.Token, error) {
@@ -96,6 +96,35 @@ func TestConfigToOAuth2Config(t *testing.T) {
 	require.Equal(t, []string{"read", "write"}, oauth2Config.Scopes)
 }
 
+func TestConfigStoredTokenKey(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config      *oauth.Config
+		expectedKey string
+	}{
+		"includes the client ID": {
+			config:      &oauth.Config{ClientID: "test-client"},
+			expectedKey: "bank-test-client-oauth-token",
+		},
+		"includes the token key": {
+			config:      &oauth.Config{ClientID: "test-client", TokenKey: "sandbox"},
+			expectedKey: "bank-sandbox-test-client-oauth-token",
+		},
+		"omits missing parts": {
+			config:      &oauth.Config{},
+			expectedKey: "bank-oauth-token",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.expectedKey, test.config.StoredTokenKey("bank"))
+		})
+	}
+}
+
 func TestExchange(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,79 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/HallyG/fingrab/internal/log"
+	"golang.org/x/oauth2"
+)
+
+// TokenStore keeps tokens between runs, e.g. a store.Store.
+type TokenStore interface {
+	Load(key string, v any) (bool, error)
+	Save(key string, v any) error
+}
+
+// Refresh exchanges the refresh token for a new token. Providers may rotate the refresh token, so the returned
+// token should be kept in place of the old one.
+func Refresh(ctx context.Context, cfg *Config, refreshToken string) (*oauth2.Token, error) {
+	if err := cfg.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid oauth2 config: %w", err)
+	}
+
+	if refreshToken == "" {
+		return nil, errors.New("refresh token is required")
+	}
+
+	oauth2Config := cfg.ToOAuth2Config()
+	token, err := oauth2Config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, fmt.Errorf("could not refresh oauth token: %w", err)
+	}
+
+	log.FromContext(ctx).DebugContext(ctx, "refreshed oauth token")
+	return token, nil
+}
+
+// StoredToken returns the access token kept under the key, refreshing it when it has expired. The flow is only run
+// when there's no token, or it can't be refreshed, e.g. because the user revoked access. New tokens are kept for
+// the next run.
+func StoredToken(ctx context.Context, cfg *Config, tokens TokenStore, key string, userInput io.Reader) (string, error) {
+	logger := log.FromContext(ctx)
+
+	var stored oauth2.Token
+	found, err := tokens.Load(key, &stored)
+	if err != nil {
+		return "", fmt.Errorf("load token: %w", err)
+	}
+
+	if found && stored.Valid() {
+		logger.DebugContext(ctx, "using stored oauth token")
+		return stored.AccessToken, nil
+	}
+
+	var token *oauth2.Token
+	if found && stored.RefreshToken != "" {
+		token, err = Refresh(ctx, cfg, stored.RefreshToken)
+		if err != nil {
+			logger.WarnContext(ctx, "could not refresh stored oauth token, logging in again", slog.Any("err", err))
+		}
+	}
+
+	if token == nil {
+		token, err = ExchangeToken(ctx, cfg, userInput)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// The token is still usable this run, so failing to keep it only means logging in again next time
+	if err := tokens.Save(key, token); err != nil {
+		logger.WarnContext(ctx, "could not store oauth token", slog.Any("err", err))
+	}
+
+	return token.AccessToken, nil
+}
@@ -0,0 +1,153 @@
+package oauth_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/oauth"
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+const tokenKey = "oauth-test"
+
+// newTokenServer returns a config whose token endpoint refreshes "valid-refresh" and rejects any other refresh token.
+func newTokenServer(t *testing.T, response string) *oauth.Config {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "refresh_token", r.PostForm.Get("grant_type"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("refresh_token") != "valid-refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	return &oauth.Config{
+		ClientID:     "test-client",
+		ClientSecret: "test-secret",
+		AuthURL:      server.URL + "/auth",
+		TokenURL:     server.URL + "/token",
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		refreshToken         string
+		response             string
+		expectedAccessToken  string
+		expectedRefreshToken string
+		expectedErr          string
+	}{
+		"returns rotated refresh token": {
+			refreshToken:         "valid-refresh",
+			response:             `{"access_token": "new-access", "token_type": "Bearer", "expires_in": 3600, "refresh_token": "rotated-refresh"}`,
+			expectedAccessToken:  "new-access",
+			expectedRefreshToken: "rotated-refresh",
+		},
+		"keeps refresh token when it isn't rotated": {
+			refreshToken:         "valid-refresh",
+			response:             `{"access_token": "new-access", "token_type": "Bearer", "expires_in": 3600}`,
+			expectedAccessToken:  "new-access",
+			expectedRefreshToken: "valid-refresh",
+		},
+		"returns error when refresh token is rejected": {
+			refreshToken: "revoked-refresh",
+			expectedErr:  "could not refresh oauth token",
+		},
+		"returns error when refresh token is missing": {
+			expectedErr: "refresh token is required",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config := newTokenServer(t, test.response)
+
+			token, err := oauth.Refresh(t.Context(), config, test.refreshToken)
+
+			if test.expectedErr != "" {
+				require.Nil(t, token)
+				require.ErrorContains(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedAccessToken, token.AccessToken)
+			require.Equal(t, test.expectedRefreshToken, token.RefreshToken)
+			require.WithinDuration(t, time.Now().Add(time.Hour), token.Expiry, time.Minute)
+		})
+	}
+}
+
+func TestStoredToken(t *testing.T) {
+	t.Parallel()
+
+	response := `{"access_token": "new-access", "token_type": "Bearer", "expires_in": 3600, "refresh_token": "rotated-refresh"}`
+
+	tests := map[string]struct {
+		stored               *oauth2.Token
+		expectedAccessToken  string
+		expectedRefreshToken string
+	}{
+		"returns stored token when it hasn't expired": {
+			stored: &oauth2.Token{
+				AccessToken:  "stored-access",
+				RefreshToken: "valid-refresh",
+				Expiry:       time.Now().Add(time.Hour),
+			},
+			expectedAccessToken:  "stored-access",
+			expectedRefreshToken: "valid-refresh",
+		},
+		"refreshes and stores expired token": {
+			stored: &oauth2.Token{
+				AccessToken:  "expired-access",
+				RefreshToken: "valid-refresh",
+				Expiry:       time.Now().Add(-time.Hour),
+			},
+			expectedAccessToken:  "new-access",
+			expectedRefreshToken: "rotated-refresh",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			config := newTokenServer(t, response)
+
+			tokens, err := store.New(t.TempDir())
+			require.NoError(t, err)
+
+			if test.stored != nil {
+				require.NoError(t, tokens.Save(tokenKey, test.stored))
+			}
+
+			accessToken, err := oauth.StoredToken(t.Context(), config, tokens, tokenKey, strings.NewReader(""))
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedAccessToken, accessToken)
+
+			var stored oauth2.Token
+			found, err := tokens.Load(tokenKey, &stored)
+			require.NoError(t, err)
+			require.True(t, found)
+			require.Equal(t, test.expectedAccessToken, stored.AccessToken)
+			require.Equal(t, test.expectedRefreshToken, stored.RefreshToken)
+		})
+	}
+}
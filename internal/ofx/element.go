@@ -0,0 +1,132 @@
+package ofx
+
+import (
+	"errors"
+	"strings"
+)
+
+// element is a node of an OFX document. Aggregates have children, and elements have a value.
+type element struct {
+	name     string
+	value    string
+	children []*element
+}
+
+// child returns the first descendant following the path of element names, or nil.
+func (e *element) child(path ...string) *element {
+	current := e
+	for _, name := range path {
+		if current == nil {
+			return nil
+		}
+
+		var next *element
+		for _, child := range current.children {
+			if child.name == name {
+				next = child
+				break
+			}
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// text returns the value of the descendant following the path of element names, or an empty string.
+func (e *element) text(path ...string) string {
+	child := e.child(path...)
+	if child == nil {
+		return ""
+	}
+
+	return child.value
+}
+
+// all returns the children with the name.
+func (e *element) all(name string) []*element {
+	var children []*element
+	for _, child := range e.children {
+		if child.name == name {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
+var entities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ", "&amp;", "&")
+
+// parseElements parses the document body, starting at the OFX element, into a tree. It accepts both OFX 1.x SGML,
+// where elements usually have no end tag, and OFX 2.x XML. An element's end tag, when present, also closes any
+// unclosed elements inside it.
+func parseElements(body string) (*element, error) {
+	start := strings.Index(strings.ToUpper(body), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("no OFX element")
+	}
+
+	root := &element{}
+	stack := []*element{root}
+	top := func() *element { return stack[len(stack)-1] }
+
+	// An element with a value can't contain others, so it's closed by the next tag whether or not it has an end tag
+	closeValue := func() {
+		if len(stack) > 1 && top().value != "" {
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	s := body[start:]
+	for len(s) > 0 {
+		open := strings.IndexByte(s, '<')
+		if open < 0 {
+			break
+		}
+
+		if text := strings.TrimSpace(s[:open]); text != "" && len(stack) > 1 && len(top().children) == 0 {
+			top().value = entities.Replace(text)
+		}
+
+		end := strings.IndexByte(s[open:], '>')
+		if end < 0 {
+			return nil, errors.New("unterminated tag")
+		}
+
+		tag := strings.TrimSpace(s[open+1 : open+end])
+		s = s[open+end+1:]
+
+		switch {
+		case strings.HasPrefix(tag, "?"), strings.HasPrefix(tag, "!"):
+			// Processing instructions, comments and declarations carry no data
+		case strings.HasPrefix(tag, "/"):
+			name := strings.ToUpper(strings.TrimSpace(tag[1:]))
+			closeValue()
+
+			for i := len(stack) - 1; i > 0; i-- {
+				if stack[i].name == name {
+					stack = stack[:i]
+					break
+				}
+			}
+		default:
+			closeValue()
+
+			selfClosing := strings.HasSuffix(tag, "/")
+			child := &element{name: strings.ToUpper(strings.TrimSpace(strings.TrimSuffix(tag, "/")))}
+			top().children = append(top().children, child)
+
+			if !selfClosing {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	ofx := root.child("OFX")
+	if ofx == nil {
+		return nil, errors.New("no OFX element")
+	}
+
+	return ofx, nil
+}
@@ -0,0 +1,188 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/ofx"
+)
+
+const (
+	ExportTypeOFX   = export.ExportType("ofx")
+	ofxMaxDateRange = time.Duration(0)
+	defaultBankName = "OFX"
+)
+
+var _ export.Exporter = (*TransactionExporter)(nil)
+
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+	File:         true,
+}
+
+// TransactionExporter reads accounts and transactions from an OFX or QFX file, rather than a bank's API.
+type TransactionExporter struct {
+	input string
+}
+
+func New(input string) (*TransactionExporter, error) {
+	if input == "" {
+		return nil, errors.New("input file is required")
+	}
+
+	return &TransactionExporter{
+		input: input,
+	}, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return ExportTypeOFX
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return ofxMaxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
+// ExportAccounts returns the account of each statement in the file.
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	file, err := e.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*domain.Account, 0, len(file.Statements))
+	for _, statement := range file.Statements {
+		accounts = append(accounts, toAccount(file, statement))
+	}
+
+	return accounts, nil
+}
+
+// ExportTransactions returns the transactions of the selected account's statement, or every statement's when the
+// account is empty or "all", within the date range, whose end is exclusive. Either end of the range may be left unset
+// to include the whole statement.
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	file, err := e.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	statements, err := selectStatements(file.Statements, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	bankName := file.Organisation
+	if bankName == "" {
+		bankName = defaultBankName
+	}
+
+	total := 0
+	transactions := make([]*domain.Transaction, 0)
+	for _, statement := range statements {
+		total += len(statement.Transactions)
+
+		for _, txn := range statement.Transactions {
+			if !opts.StartDate.IsZero() && txn.Posted.Before(opts.StartDate) {
+				continue
+			}
+
+			if !opts.EndDate.IsZero() && !txn.Posted.Before(opts.EndDate) {
+				continue
+			}
+
+			transactions = append(transactions, toTransaction(txn, statement, bankName))
+		}
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "read transactions from OFX file",
+		slog.String("input", e.input),
+		slog.Int("statement.count", len(statements)),
+		slog.Int("transaction.total", total),
+		slog.Int("transaction.count", len(transactions)),
+	)
+
+	return transactions, nil
+}
+
+func (e *TransactionExporter) parse() (*ofx.File, error) {
+	f, err := os.Open(e.input)
+	if err != nil {
+		return nil, fmt.Errorf("open input: %w", err)
+	}
+	defer f.Close()
+
+	file, err := ofx.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", filepath.Base(e.input), err)
+	}
+
+	return file, nil
+}
+
+func selectStatements(statements []*ofx.Statement, accountID string) ([]*ofx.Statement, error) {
+	if accountID == "" || strings.EqualFold(accountID, export.AllAccounts) {
+		return statements, nil
+	}
+
+	for _, statement := range statements {
+		if statement.Account.AccountID == accountID {
+			return []*ofx.Statement{statement}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no statement for account %q", accountID)
+}
+
+func toAccount(file *ofx.File, statement *ofx.Statement) *domain.Account {
+	account := &domain.Account{
+		ID:            statement.Account.AccountID,
+		Name:          file.Organisation,
+		Type:          strings.ToLower(statement.Account.Type),
+		Currency:      statement.Currency,
+		AccountNumber: statement.Account.AccountID,
+	}
+
+	// UK banks put the sort code where others put a routing number
+	if statement.Currency == "GBP" {
+		account.SortCode = statement.Account.BankID
+	}
+
+	return account
+}
+
+func toTransaction(txn *ofx.Transaction, statement *ofx.Statement, bankName string) *domain.Transaction {
+	// Amounts are in the statement's currency unless the transaction states its own
+	currency := statement.Currency
+	if txn.Currency != "" {
+		currency = txn.Currency
+	}
+
+	amount := domain.MoneyFromMajorUnit(txn.Amount, currency)
+
+	return &domain.Transaction{
+		ID:        txn.FITID,
+		Amount:    amount,
+		Reference: txn.Name,
+		CreatedAt: txn.Posted,
+		IsDeposit: amount.MinorUnit > 0,
+		BankName:  bankName,
+		Account:   statement.Account.AccountID,
+		Notes:     txn.Memo,
+		Status:    domain.TransactionStatusSettled,
+	}
+}
@@ -0,0 +1,152 @@
+package exporter_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	ofxexporter "github.com/HallyG/fingrab/internal/ofx/exporter"
+	"github.com/stretchr/testify/require"
+)
+
+var bankStatement = filepath.Join("..", "testdata", "bank-v1.ofx")
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when input is empty", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := ofxexporter.New("")
+
+		require.Nil(t, exporter)
+		require.EqualError(t, err, "input file is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := ofxexporter.New(bankStatement)
+
+		require.NoError(t, err)
+		require.Equal(t, ofxexporter.ExportTypeOFX, exporter.Type())
+		require.Equal(t, time.Duration(0), exporter.MaxDateRange())
+	})
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	salary := &domain.Transaction{
+		ID:        "202503040001",
+		Amount:    domain.Money{MinorUnit: 150000, Currency: "GBP"},
+		Reference: "ACME LTD SALARY",
+		CreatedAt: time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC),
+		IsDeposit: true,
+		BankName:  "Example Bank",
+		Account:   "12345678",
+		Status:    domain.TransactionStatusSettled,
+	}
+
+	tests := map[string]struct {
+		input                string
+		accountID            string
+		startDate            time.Time
+		endDate              time.Time
+		expectedTransactions []*domain.Transaction
+		expectedErr          string
+	}{
+		"returns transactions within range using fitid and statement currency": {
+			input:                bankStatement,
+			startDate:            time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC),
+			endDate:              time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+			expectedTransactions: []*domain.Transaction{salary},
+		},
+		"uses transaction currency when it differs from the statement's": {
+			input:     bankStatement,
+			accountID: "12345678",
+			startDate: time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+			expectedTransactions: []*domain.Transaction{
+				{
+					ID:        "202503050001",
+					Amount:    domain.Money{MinorUnit: -2500, Currency: "EUR"},
+					Reference: "HOTEL PARIS",
+					CreatedAt: time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+					BankName:  "Example Bank",
+					Account:   "12345678",
+					Status:    domain.TransactionStatusSettled,
+				},
+			},
+		},
+		"defaults bank name when file doesn't name the institution": {
+			input: filepath.Join("..", "testdata", "creditcard-v2.qfx"),
+			expectedTransactions: []*domain.Transaction{
+				{
+					ID:        "320250310",
+					Amount:    domain.Money{MinorUnit: -5420, Currency: "USD"},
+					Reference: "WHOLE FOODS",
+					CreatedAt: time.Date(2025, time.March, 10, 12, 0, 0, 0, time.FixedZone("EST", -5*60*60)),
+					BankName:  "OFX",
+					Account:   "XXXX1111",
+					Status:    domain.TransactionStatusSettled,
+				},
+			},
+		},
+		"returns error when account has no statement": {
+			input:       bankStatement,
+			accountID:   "87654321",
+			expectedErr: `no statement for account "87654321"`,
+		},
+		"returns error when input is missing": {
+			input:       filepath.Join(t.TempDir(), "missing.ofx"),
+			expectedErr: "open input",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter, err := ofxexporter.New(test.input)
+			require.NoError(t, err)
+
+			transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				AccountID: test.accountID,
+				StartDate: test.startDate,
+				EndDate:   test.endDate,
+			})
+
+			if test.expectedErr != "" {
+				require.Nil(t, transactions)
+				require.ErrorContains(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransactions, transactions)
+		})
+	}
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	exporter, err := ofxexporter.New(bankStatement)
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Account{
+		{
+			ID:            "12345678",
+			Name:          "Example Bank",
+			Type:          "checking",
+			Currency:      "GBP",
+			SortCode:      "200000",
+			AccountNumber: "12345678",
+		},
+	}, accounts)
+}
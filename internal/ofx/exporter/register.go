@@ -0,0 +1,15 @@
+package exporter
+
+import (
+	"github.com/HallyG/fingrab/internal/export"
+)
+
+func init() {
+	export.Register(ExportTypeOFX, func(opts export.Options) (export.Exporter, error) {
+		return New(opts.Input)
+	},
+		export.WithDisplayName("OFX and QFX file"),
+		export.WithDescription("Commands for converting OFX (1.x and 2.x) and QFX files downloaded from other banks"),
+		export.WithCapabilities(capabilities),
+	)
+}
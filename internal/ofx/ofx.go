@@ -0,0 +1,284 @@
+// Package ofx parses Open Financial Exchange statement downloads: OFX 1.x (SGML), OFX 2.x (XML) and Quicken's QFX,
+// which is OFX with additional Intuit elements.
+package ofx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccountTypeCreditCard is the account type of credit card statements, which don't state one.
+const AccountTypeCreditCard = "CREDITCARD"
+
+type File struct {
+	Organisation string // The financial institution, if the file names it
+	Statements   []*Statement
+}
+
+type Statement struct {
+	Account      Account
+	Currency     string // ISO 4217 currency of the statement's amounts
+	StartDate    time.Time
+	EndDate      time.Time
+	Transactions []*Transaction
+	Balance      *Balance // The ledger balance, if the file includes it
+}
+
+type Account struct {
+	BankID    string // Routing number or, for UK banks, sort code
+	BranchID  string
+	AccountID string
+	Type      string // e.g. CHECKING, SAVINGS, CREDITLINE or CREDITCARD
+}
+
+type Transaction struct {
+	FITID    string // The financial institution's unique ID for the transaction
+	Type     string // e.g. DEBIT, CREDIT, POS, ATM or FEE
+	Posted   time.Time
+	Amount   float64 // Signed amount in major units, negative when money leaves the account
+	Currency string  // Currency of Amount, when it differs from the statement's
+	Name     string
+	Memo     string
+}
+
+type Balance struct {
+	Amount float64
+	AsOf   time.Time
+}
+
+// Parse reads every bank and credit card statement in the OFX or QFX file.
+func Parse(r io.Reader) (*File, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read: %w", err)
+	}
+
+	root, err := parseElements(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	if status := root.child("SIGNONMSGSRSV1", "SONRS", "STATUS"); status != nil {
+		if err := checkStatus(status); err != nil {
+			return nil, fmt.Errorf("sign on: %w", err)
+		}
+	}
+
+	file := &File{
+		Organisation: root.text("SIGNONMSGSRSV1", "SONRS", "FI", "ORG"),
+	}
+
+	responses := []struct {
+		messages, response, statement string
+	}{
+		{"BANKMSGSRSV1", "STMTTRNRS", "STMTRS"},
+		{"CREDITCARDMSGSRSV1", "CCSTMTTRNRS", "CCSTMTRS"},
+	}
+
+	for _, response := range responses {
+		messages := root.child(response.messages)
+		if messages == nil {
+			continue
+		}
+
+		for _, trnrs := range messages.all(response.response) {
+			if status := trnrs.child("STATUS"); status != nil {
+				if err := checkStatus(status); err != nil {
+					return nil, fmt.Errorf("statement: %w", err)
+				}
+			}
+
+			stmtrs := trnrs.child(response.statement)
+			if stmtrs == nil {
+				continue
+			}
+
+			statement, err := parseStatement(stmtrs)
+			if err != nil {
+				return nil, fmt.Errorf("statement: %w", err)
+			}
+
+			file.Statements = append(file.Statements, statement)
+		}
+	}
+
+	if len(file.Statements) == 0 {
+		return nil, errors.New("no bank or credit card statements")
+	}
+
+	return file, nil
+}
+
+func checkStatus(status *element) error {
+	code := status.text("CODE")
+	if code == "" || code == "0" {
+		return nil
+	}
+
+	message := status.text("MESSAGE")
+	if message == "" {
+		message = status.text("SEVERITY")
+	}
+
+	return fmt.Errorf("status %s: %s", code, message)
+}
+
+func parseStatement(stmtrs *element) (*Statement, error) {
+	statement := &Statement{
+		Currency: strings.ToUpper(stmtrs.text("CURDEF")),
+	}
+
+	if from := stmtrs.child("BANKACCTFROM"); from != nil {
+		statement.Account = Account{
+			BankID:    from.text("BANKID"),
+			BranchID:  from.text("BRANCHID"),
+			AccountID: from.text("ACCTID"),
+			Type:      strings.ToUpper(from.text("ACCTTYPE")),
+		}
+	} else if from := stmtrs.child("CCACCTFROM"); from != nil {
+		statement.Account = Account{
+			AccountID: from.text("ACCTID"),
+			Type:      AccountTypeCreditCard,
+		}
+	}
+
+	if statement.Account.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	var err error
+	list := stmtrs.child("BANKTRANLIST")
+	if list != nil {
+		if statement.StartDate, err = parseOptionalDate(list.text("DTSTART")); err != nil {
+			return nil, fmt.Errorf("start date: %w", err)
+		}
+
+		if statement.EndDate, err = parseOptionalDate(list.text("DTEND")); err != nil {
+			return nil, fmt.Errorf("end date: %w", err)
+		}
+
+		for _, stmttrn := range list.all("STMTTRN") {
+			transaction, err := parseTransaction(stmttrn)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %s: %w", stmttrn.text("FITID"), err)
+			}
+
+			statement.Transactions = append(statement.Transactions, transaction)
+		}
+	}
+
+	if ledger := stmtrs.child("LEDGERBAL"); ledger != nil {
+		amount, err := parseAmount(ledger.text("BALAMT"))
+		if err != nil {
+			return nil, fmt.Errorf("ledger balance: %w", err)
+		}
+
+		asOf, err := parseOptionalDate(ledger.text("DTASOF"))
+		if err != nil {
+			return nil, fmt.Errorf("ledger balance: %w", err)
+		}
+
+		statement.Balance = &Balance{Amount: amount, AsOf: asOf}
+	}
+
+	return statement, nil
+}
+
+func parseTransaction(stmttrn *element) (*Transaction, error) {
+	posted, err := parseDate(stmttrn.text("DTPOSTED"))
+	if err != nil {
+		return nil, fmt.Errorf("posted date: %w", err)
+	}
+
+	amount, err := parseAmount(stmttrn.text("TRNAMT"))
+	if err != nil {
+		return nil, err
+	}
+
+	// The payee is either a plain name or, in some files, an aggregate with an address
+	name := stmttrn.text("NAME")
+	if name == "" {
+		name = stmttrn.text("PAYEE", "NAME")
+	}
+
+	return &Transaction{
+		FITID:    stmttrn.text("FITID"),
+		Type:     strings.ToUpper(stmttrn.text("TRNTYPE")),
+		Posted:   posted,
+		Amount:   amount,
+		Currency: strings.ToUpper(stmttrn.text("CURRENCY", "CURSYM")),
+		Name:     name,
+		Memo:     stmttrn.text("MEMO"),
+	}, nil
+}
+
+// parseAmount parses an OFX amount, which may use a comma as the decimal separator or, alongside a point, to
+// separate thousands.
+func parseAmount(amount string) (float64, error) {
+	cleaned := strings.TrimSpace(amount)
+	if strings.Contains(cleaned, ".") {
+		cleaned = strings.ReplaceAll(cleaned, ",", "")
+	} else {
+		cleaned = strings.ReplaceAll(cleaned, ",", ".")
+	}
+
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse amount %q: %w", amount, err)
+	}
+
+	return value, nil
+}
+
+func parseOptionalDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+
+	return parseDate(date)
+}
+
+// parseDate parses an OFX date, YYYYMMDD[HHMMSS[.XXX]][[offset:name]], e.g. 20250314120000.000[-5:EST].
+// Dates without an offset are UTC.
+func parseDate(date string) (time.Time, error) {
+	value, zone, _ := strings.Cut(strings.TrimSpace(date), "[")
+	value, _, _ = strings.Cut(value, ".")
+
+	location := time.UTC
+	if zone != "" {
+		offset, name, _ := strings.Cut(strings.TrimSuffix(zone, "]"), ":")
+
+		hours, err := strconv.ParseFloat(offset, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parse date %q: invalid offset %q", date, offset)
+		}
+
+		if name == "" {
+			name = offset
+		}
+
+		location = time.FixedZone(name, int(hours*float64(time.Hour/time.Second)))
+	}
+
+	layouts := map[int]string{
+		8:  "20060102",
+		12: "200601021504",
+		14: "20060102150405",
+	}
+
+	layout, ok := layouts[len(value)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("parse date %q: unsupported format", date)
+	}
+
+	parsed, err := time.ParseInLocation(layout, value, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse date %q: %w", date, err)
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,160 @@
+package ofx_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/ofx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	est := time.FixedZone("EST", -5*60*60)
+	gmt := time.FixedZone("GMT", 0)
+
+	tests := map[string]struct {
+		input        string
+		expectedFile *ofx.File
+	}{
+		"ofx 1.x sgml bank statement": {
+			input: "bank-v1.ofx",
+			expectedFile: &ofx.File{
+				Organisation: "Example Bank",
+				Statements: []*ofx.Statement{
+					{
+						Account: ofx.Account{
+							BankID:    "200000",
+							AccountID: "12345678",
+							Type:      "CHECKING",
+						},
+						Currency:  "GBP",
+						StartDate: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+						EndDate:   time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+						Transactions: []*ofx.Transaction{
+							{
+								FITID:  "202503030001",
+								Type:   "POS",
+								Posted: time.Date(2025, time.March, 3, 12, 0, 0, 0, gmt),
+								Amount: -12.99,
+								Name:   "MARKS & SPENCER",
+								Memo:   "CARD 1234",
+							},
+							{
+								FITID:  "202503040001",
+								Type:   "CREDIT",
+								Posted: time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC),
+								Amount: 1500,
+								Name:   "ACME LTD SALARY",
+							},
+							{
+								FITID:    "202503050001",
+								Type:     "POS",
+								Posted:   time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC),
+								Amount:   -25,
+								Currency: "EUR",
+								Name:     "HOTEL PARIS",
+							},
+						},
+						Balance: &ofx.Balance{
+							Amount: 2345.67,
+							AsOf:   time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC),
+						},
+					},
+				},
+			},
+		},
+		"ofx 2.x xml credit card statement from quicken": {
+			input: "creditcard-v2.qfx",
+			expectedFile: &ofx.File{
+				Statements: []*ofx.Statement{
+					{
+						Account: ofx.Account{
+							AccountID: "XXXX1111",
+							Type:      ofx.AccountTypeCreditCard,
+						},
+						Currency:  "USD",
+						StartDate: time.Date(2025, time.March, 1, 0, 0, 0, 0, est),
+						EndDate:   time.Date(2025, time.March, 31, 0, 0, 0, 0, est),
+						Transactions: []*ofx.Transaction{
+							{
+								FITID:  "320250310",
+								Type:   "DEBIT",
+								Posted: time.Date(2025, time.March, 10, 12, 0, 0, 0, est),
+								Amount: -54.20,
+								Name:   "WHOLE FOODS",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := os.Open(filepath.Join("testdata", test.input))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = file.Close() })
+
+			parsed, err := ofx.Parse(file)
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedFile, parsed)
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	statement := func(transaction string) string {
+		return "<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>GBP<BANKACCTFROM><ACCTID>1</BANKACCTFROM>" +
+			"<BANKTRANLIST><STMTTRN>" + transaction + "</STMTTRN></BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>"
+	}
+
+	tests := map[string]struct {
+		input       string
+		expectedErr string
+	}{
+		"not ofx": {
+			input:       "Date,Amount\n",
+			expectedErr: "no OFX element",
+		},
+		"no statements": {
+			input:       "<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</STATUS></SONRS></SIGNONMSGSRSV1></OFX>",
+			expectedErr: "no bank or credit card statements",
+		},
+		"sign on failed": {
+			input:       "<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>15500<SEVERITY>ERROR<MESSAGE>Signon invalid</STATUS></SONRS></SIGNONMSGSRSV1></OFX>",
+			expectedErr: "sign on: status 15500: Signon invalid",
+		},
+		"missing account": {
+			input:       "<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><CURDEF>GBP</STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>",
+			expectedErr: "statement: account ID is required",
+		},
+		"invalid amount": {
+			input:       statement("<FITID>1<DTPOSTED>20250301<TRNAMT>abc"),
+			expectedErr: `statement: transaction 1: parse amount "abc"`,
+		},
+		"invalid date": {
+			input:       statement("<FITID>1<DTPOSTED>2025-03-01<TRNAMT>1.00"),
+			expectedErr: `statement: transaction 1: posted date: parse date "2025-03-01": unsupported format`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := ofx.Parse(strings.NewReader(test.input))
+
+			require.Nil(t, file)
+			require.ErrorContains(t, err, test.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,375 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/openbanking"
+	"github.com/samber/lo"
+)
+
+const (
+	ExportTypeOpenBanking   = export.ExportType("openbanking")
+	openBankingMaxDateRange = time.Duration(0)
+	defaultBankName         = "Open Banking"
+	accountStatusEnabled    = "Enabled"
+)
+
+var (
+	_ export.Exporter               = (*TransactionExporter)(nil)
+	_ export.ClosingBalanceExporter = (*TransactionExporter)(nil)
+)
+
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+	Balances:     true,
+	Pending:      true,
+	Declines:     true,
+}
+
+type TransactionExporter struct {
+	api      openbanking.Client
+	bankName string
+}
+
+type Option func(*TransactionExporter)
+
+// WithBankName configures the bank name given to exported transactions, e.g. NatWest.
+func WithBankName(name string) Option {
+	return func(e *TransactionExporter) {
+		if name != "" {
+			e.bankName = name
+		}
+	}
+}
+
+func New(api openbanking.Client, opts ...Option) (*TransactionExporter, error) {
+	if api == nil {
+		return nil, errors.New("open banking client is required")
+	}
+
+	exporter := &TransactionExporter{
+		api:      api,
+		bankName: defaultBankName,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(exporter)
+	}
+
+	return exporter, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return ExportTypeOpenBanking
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return openBankingMaxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	accounts, err := e.api.FetchAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	return lo.Map(accounts, func(account *openbanking.Account, _ int) *domain.Account {
+		return toAccount(account)
+	}), nil
+}
+
+// ExportTransactions returns the transactions of the selected account, or of every account when the account is "all".
+// Rejected transactions are excluded unless auditing.
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if opts.Space != "" {
+		return nil, errors.New("spaces are not supported by open banking")
+	}
+
+	accounts, err := e.fetchAccounts(ctx, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.FromContext(ctx)
+
+	transactions := make([]*domain.Transaction, 0)
+	for _, account := range accounts {
+		results, err := e.fetchTransactions(ctx, account.AccountID, opts.StartDate, opts.EndDate)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, txn := range results {
+			if !opts.Audit && txn.Status == openbanking.StatusRejected {
+				continue
+			}
+
+			transaction, err := toTransaction(txn, accountName(account), e.bankName)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %s: %w", txn.TransactionID, err)
+			}
+
+			transactions = append(transactions, transaction)
+		}
+
+		logger.InfoContext(ctx, "fetched transactions",
+			slog.String("account.id", account.AccountID),
+			slog.Int("transaction.count", len(results)),
+		)
+	}
+
+	return transactions, nil
+}
+
+// ExportClosingBalance returns the account's booked balance at the end of the export's date range. Banks report the
+// current booked balance, so transactions booked since the end date are unwound from it.
+func (e *TransactionExporter) ExportClosingBalance(ctx context.Context, opts export.TransactionOptions) (domain.Money, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return domain.Money{}, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if opts.Space != "" {
+		return domain.Money{}, fmt.Errorf("%w for a space", export.ErrClosingBalanceUnsupported)
+	}
+
+	accounts, err := e.fetchAccounts(ctx, opts.AccountID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	if len(accounts) != 1 {
+		return domain.Money{}, errors.New("a single account is required")
+	}
+
+	account := accounts[0]
+
+	balances, err := e.api.FetchBalances(ctx, account.AccountID)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("fetch balances: %w", err)
+	}
+
+	balance, ok := bookedBalance(balances)
+	if !ok {
+		return domain.Money{}, fmt.Errorf("%w: no booked balance", export.ErrClosingBalanceUnsupported)
+	}
+
+	closing, err := signedAmount(balance.Amount, balance.CreditDebitIndicator)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("balance: %w", err)
+	}
+
+	if opts.EndDate.Before(balance.DateTime.Time) {
+		transactions, err := e.fetchTransactions(ctx, account.AccountID, opts.EndDate, balance.DateTime.Add(time.Second))
+		if err != nil {
+			return domain.Money{}, err
+		}
+
+		for _, txn := range transactions {
+			if txn.Status != openbanking.StatusBooked {
+				continue
+			}
+
+			amount, err := signedAmount(txn.Amount, txn.CreditDebitIndicator)
+			if err != nil {
+				return domain.Money{}, fmt.Errorf("transaction %s: %w", txn.TransactionID, err)
+			}
+
+			closing.MinorUnit -= amount.MinorUnit
+		}
+	}
+
+	return closing, nil
+}
+
+// fetchTransactions returns the account's transactions booked at or after start and before end.
+func (e *TransactionExporter) fetchTransactions(ctx context.Context, accountID string, start time.Time, end time.Time) ([]*openbanking.Transaction, error) {
+	// toBookingDateTime is inclusive and only precise to the second
+	transactions, err := e.api.FetchTransactions(ctx, openbanking.FetchTransactionOptions{
+		AccountID: accountID,
+		Start:     start,
+		End:       end.Add(-time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch transactions: %w", err)
+	}
+
+	return lo.Filter(transactions, func(txn *openbanking.Transaction, _ int) bool {
+		return !txn.BookingDateTime.Before(start) && txn.BookingDateTime.Before(end)
+	}), nil
+}
+
+// fetchAccounts returns the account matching the selector, or every account when the selector is "all".
+// An empty selector selects the first enabled account.
+func (e *TransactionExporter) fetchAccounts(ctx context.Context, selector string) ([]*openbanking.Account, error) {
+	accounts, err := e.api.FetchAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	if strings.EqualFold(selector, export.AllAccounts) {
+		return accounts, nil
+	}
+
+	if selector == "" {
+		account, ok := lo.Find(accounts, func(account *openbanking.Account) bool {
+			return account.Status == accountStatusEnabled
+		})
+		if !ok {
+			return nil, errors.New("no enabled accounts")
+		}
+
+		return []*openbanking.Account{account}, nil
+	}
+
+	if account, ok := lo.Find(accounts, func(account *openbanking.Account) bool {
+		return account.AccountID == selector
+	}); ok {
+		return []*openbanking.Account{account}, nil
+	}
+
+	matches := lo.Filter(accounts, func(account *openbanking.Account, _ int) bool {
+		return strings.EqualFold(account.Nickname, selector) || strings.EqualFold(account.AccountSubType, selector)
+	})
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no account matches %q", selector)
+	case 1:
+		return matches, nil
+	default:
+		ids := lo.Map(matches, func(account *openbanking.Account, _ int) string {
+			return account.AccountID
+		})
+
+		return nil, fmt.Errorf("%q matches %d accounts (%s), use an account ID instead", selector, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+func bookedBalance(balances []*openbanking.Balance) (*openbanking.Balance, bool) {
+	for _, balanceType := range []openbanking.BalanceType{openbanking.BalanceInterimBooked, openbanking.BalanceClosingBooked} {
+		if balance, ok := lo.Find(balances, func(balance *openbanking.Balance) bool {
+			return balance.Type == balanceType
+		}); ok {
+			return balance, true
+		}
+	}
+
+	return nil, false
+}
+
+func toAccount(account *openbanking.Account) *domain.Account {
+	result := &domain.Account{
+		ID:         account.AccountID,
+		Name:       accountName(account),
+		Type:       strings.ToLower(account.AccountSubType),
+		Currency:   account.Currency,
+		HolderType: strings.ToLower(account.AccountType),
+		Closed:     account.Status != "" && account.Status != accountStatusEnabled,
+		CreatedAt:  account.OpeningDate.Time,
+	}
+
+	for _, identification := range account.Account {
+		switch identification.SchemeName {
+		case openbanking.SchemeSortCodeAccountNumber:
+			// Identification is the 6 digit sort code followed by the 8 digit account number
+			if len(identification.Identification) == 14 {
+				result.SortCode = identification.Identification[:6]
+				result.AccountNumber = identification.Identification[6:]
+			}
+		case openbanking.SchemeIBAN:
+			result.IBAN = identification.Identification
+		}
+
+		if identification.Name != "" && !lo.Contains(result.Owners, identification.Name) {
+			result.Owners = append(result.Owners, identification.Name)
+		}
+	}
+
+	if account.Servicer != nil && account.Servicer.SchemeName == openbanking.SchemeBIC {
+		result.BIC = account.Servicer.Identification
+	}
+
+	return result
+}
+
+func accountName(account *openbanking.Account) string {
+	if account.Nickname != "" {
+		return account.Nickname
+	}
+
+	return account.AccountSubType
+}
+
+func toTransaction(txn *openbanking.Transaction, account string, bankName string) (*domain.Transaction, error) {
+	amount, err := signedAmount(txn.Amount, txn.CreditDebitIndicator)
+	if err != nil {
+		return nil, err
+	}
+
+	// The merchant's name is cleaner than the bank's description, which is kept as a note
+	reference := txn.TransactionInformation
+	notes := ""
+	if txn.MerchantDetails != nil && txn.MerchantDetails.MerchantName != "" {
+		reference = txn.MerchantDetails.MerchantName
+		notes = txn.TransactionInformation
+	}
+
+	return &domain.Transaction{
+		ID:        txn.TransactionID,
+		Amount:    amount,
+		Reference: reference,
+		CreatedAt: txn.BookingDateTime.Time,
+		IsDeposit: txn.CreditDebitIndicator == openbanking.Credit,
+		BankName:  bankName,
+		Account:   account,
+		Notes:     notes,
+		Status:    transactionStatus(txn.Status),
+	}, nil
+}
+
+func transactionStatus(status openbanking.TransactionStatus) domain.TransactionStatus {
+	switch status {
+	case openbanking.StatusPending:
+		return domain.TransactionStatusPending
+	case openbanking.StatusRejected:
+		return domain.TransactionStatusDeclined
+	default:
+		return domain.TransactionStatusSettled
+	}
+}
+
+// signedAmount converts the unsigned amount to money, negative when it's a debit.
+func signedAmount(amount openbanking.Amount, indicator openbanking.CreditDebitIndicator) (domain.Money, error) {
+	major, err := strconv.ParseFloat(amount.Amount, 64)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("parse amount %q: %w", amount.Amount, err)
+	}
+
+	if indicator == openbanking.Debit {
+		major = -major
+	}
+
+	return domain.MoneyFromMajorUnit(major, amount.Currency), nil
+}
@@ -0,0 +1,357 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/openbanking"
+	openbankingexporter "github.com/HallyG/fingrab/internal/openbanking/exporter"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+type StubClient struct {
+	Accounts         []*openbanking.Account
+	Balances         []*openbanking.Balance
+	Transactions     map[string][]*openbanking.Transaction
+	FetchAccountsErr error
+	FetchTxnsErr     error
+
+	RequestedTransactions []openbanking.FetchTransactionOptions
+}
+
+var _ openbanking.Client = (*StubClient)(nil)
+
+func (c *StubClient) FetchAccounts(ctx context.Context) ([]*openbanking.Account, error) {
+	if c.FetchAccountsErr != nil {
+		return nil, c.FetchAccountsErr
+	}
+
+	return c.Accounts, nil
+}
+
+func (c *StubClient) FetchBalances(ctx context.Context, accountID string) ([]*openbanking.Balance, error) {
+	return c.Balances, nil
+}
+
+func (c *StubClient) FetchTransactions(ctx context.Context, opts openbanking.FetchTransactionOptions) ([]*openbanking.Transaction, error) {
+	if c.FetchTxnsErr != nil {
+		return nil, c.FetchTxnsErr
+	}
+
+	c.RequestedTransactions = append(c.RequestedTransactions, opts)
+
+	return lo.Filter(c.Transactions[opts.AccountID], func(txn *openbanking.Transaction, _ int) bool {
+		return !txn.BookingDateTime.Before(opts.Start) && !txn.BookingDateTime.After(opts.End)
+	}), nil
+}
+
+func dateTime(day int, hour int) openbanking.DateTime {
+	return openbanking.DateTime{Time: time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC)}
+}
+
+func newStubClient() *StubClient {
+	return &StubClient{
+		Accounts: []*openbanking.Account{
+			{AccountID: "closed", Status: "Disabled", Currency: "GBP", AccountSubType: "CurrentAccount"},
+			{AccountID: "22289", Status: "Enabled", Currency: "GBP", AccountSubType: "CurrentAccount", Nickname: "Bills"},
+			{AccountID: "31820", Status: "Enabled", Currency: "GBP", AccountSubType: "Savings"},
+		},
+		Transactions: map[string][]*openbanking.Transaction{
+			"22289": {
+				{
+					TransactionID:          "123",
+					CreditDebitIndicator:   openbanking.Debit,
+					Status:                 openbanking.StatusBooked,
+					BookingDateTime:        dateTime(3, 10),
+					TransactionInformation: "CARD PAYMENT TO TESCO",
+					Amount:                 openbanking.Amount{Amount: "42.18", Currency: "GBP"},
+					MerchantDetails:        &openbanking.MerchantDetails{MerchantName: "Tesco Stores"},
+				},
+				{
+					TransactionID:          "124",
+					CreditDebitIndicator:   openbanking.Credit,
+					Status:                 openbanking.StatusBooked,
+					BookingDateTime:        dateTime(4, 9),
+					TransactionInformation: "ACME LTD SALARY",
+					Amount:                 openbanking.Amount{Amount: "1500.00", Currency: "GBP"},
+				},
+				{
+					TransactionID:          "125",
+					CreditDebitIndicator:   openbanking.Debit,
+					Status:                 openbanking.StatusRejected,
+					BookingDateTime:        dateTime(5, 18),
+					TransactionInformation: "COSTA COFFEE",
+					Amount:                 openbanking.Amount{Amount: "3.45", Currency: "GBP"},
+				},
+			},
+			"31820": {
+				{
+					TransactionID:          "200",
+					CreditDebitIndicator:   openbanking.Credit,
+					Status:                 openbanking.StatusPending,
+					BookingDateTime:        dateTime(6, 12),
+					TransactionInformation: "INTEREST",
+					Amount:                 openbanking.Amount{Amount: "1.20", Currency: "GBP"},
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when client is nil", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := openbankingexporter.New(nil)
+
+		require.Nil(t, exporter)
+		require.EqualError(t, err, "open banking client is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := openbankingexporter.New(&StubClient{})
+
+		require.NoError(t, err)
+		require.Equal(t, openbankingexporter.ExportTypeOpenBanking, exporter.Type())
+		require.Equal(t, time.Duration(0), exporter.MaxDateRange())
+	})
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	tesco := &domain.Transaction{
+		ID:        "123",
+		Amount:    domain.Money{MinorUnit: -4218, Currency: "GBP"},
+		Reference: "Tesco Stores",
+		CreatedAt: dateTime(3, 10).Time,
+		BankName:  "NatWest",
+		Account:   "Bills",
+		Notes:     "CARD PAYMENT TO TESCO",
+		Status:    domain.TransactionStatusSettled,
+	}
+	salary := &domain.Transaction{
+		ID:        "124",
+		Amount:    domain.Money{MinorUnit: 150000, Currency: "GBP"},
+		Reference: "ACME LTD SALARY",
+		CreatedAt: dateTime(4, 9).Time,
+		IsDeposit: true,
+		BankName:  "NatWest",
+		Account:   "Bills",
+		Status:    domain.TransactionStatusSettled,
+	}
+	declined := &domain.Transaction{
+		ID:        "125",
+		Amount:    domain.Money{MinorUnit: -345, Currency: "GBP"},
+		Reference: "COSTA COFFEE",
+		CreatedAt: dateTime(5, 18).Time,
+		BankName:  "NatWest",
+		Account:   "Bills",
+		Status:    domain.TransactionStatusDeclined,
+	}
+	interest := &domain.Transaction{
+		ID:        "200",
+		Amount:    domain.Money{MinorUnit: 120, Currency: "GBP"},
+		Reference: "INTEREST",
+		CreatedAt: dateTime(6, 12).Time,
+		IsDeposit: true,
+		BankName:  "NatWest",
+		Account:   "Savings",
+		Status:    domain.TransactionStatusPending,
+	}
+
+	tests := map[string]struct {
+		accountID            string
+		audit                bool
+		endDate              time.Time
+		fetchTxnsErr         error
+		expectedTransactions []*domain.Transaction
+		expectedErr          string
+	}{
+		"exports first enabled account, excluding rejected transactions": {
+			expectedTransactions: []*domain.Transaction{tesco, salary},
+		},
+		"includes rejected transactions when auditing": {
+			audit:                true,
+			expectedTransactions: []*domain.Transaction{tesco, salary, declined},
+		},
+		"excludes transactions booked at the end date": {
+			endDate:              dateTime(4, 9).Time,
+			expectedTransactions: []*domain.Transaction{tesco},
+		},
+		"selects account by sub type": {
+			accountID:            "savings",
+			expectedTransactions: []*domain.Transaction{interest},
+		},
+		"exports every account": {
+			accountID:            export.AllAccounts,
+			expectedTransactions: []*domain.Transaction{tesco, salary, interest},
+		},
+		"returns error when selector is ambiguous": {
+			accountID:   "currentaccount",
+			expectedErr: `"currentaccount" matches 2 accounts (closed, 22289), use an account ID instead`,
+		},
+		"returns error when no account matches": {
+			accountID:   "isa",
+			expectedErr: `no account matches "isa"`,
+		},
+		"returns error when fetching transactions fails": {
+			fetchTxnsErr: errors.New("boom"),
+			expectedErr:  "fetch transactions: boom",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newStubClient()
+			client.FetchTxnsErr = test.fetchTxnsErr
+
+			exporter, err := openbankingexporter.New(client, openbankingexporter.WithBankName("NatWest"))
+			require.NoError(t, err)
+
+			endDate := test.endDate
+			if endDate.IsZero() {
+				endDate = time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+			}
+
+			transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				AccountID: test.accountID,
+				Audit:     test.audit,
+				StartDate: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   endDate,
+				Options:   export.Options{AuthToken: "test-token"},
+			})
+
+			if test.expectedErr != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransactions, transactions)
+			require.Equal(t, endDate.Add(-time.Second), client.RequestedTransactions[0].End)
+		})
+	}
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	client := &StubClient{
+		Accounts: []*openbanking.Account{
+			{
+				AccountID:      "22289",
+				Status:         "Enabled",
+				Currency:       "GBP",
+				AccountType:    "Personal",
+				AccountSubType: "CurrentAccount",
+				Nickname:       "Bills",
+				OpeningDate:    dateTime(1, 0),
+				Account: []*openbanking.AccountIdentification{
+					{SchemeName: openbanking.SchemeSortCodeAccountNumber, Identification: "80200110203345", Name: "Mr Kevin"},
+					{SchemeName: openbanking.SchemeIBAN, Identification: "GB29NWBK60161331926819", Name: "Mr Kevin"},
+				},
+				Servicer: &openbanking.AccountIdentification{SchemeName: openbanking.SchemeBIC, Identification: "NWBKGB2L"},
+			},
+			{
+				AccountID:      "31820",
+				Status:         "Disabled",
+				Currency:       "GBP",
+				AccountType:    "Business",
+				AccountSubType: "Savings",
+			},
+		},
+	}
+
+	exporter, err := openbankingexporter.New(client)
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Account{
+		{
+			ID:            "22289",
+			Name:          "Bills",
+			Type:          "currentaccount",
+			Currency:      "GBP",
+			SortCode:      "802001",
+			AccountNumber: "10203345",
+			IBAN:          "GB29NWBK60161331926819",
+			BIC:           "NWBKGB2L",
+			HolderType:    "personal",
+			Owners:        []string{"Mr Kevin"},
+			CreatedAt:     dateTime(1, 0).Time,
+		},
+		{
+			ID:         "31820",
+			Name:       "Savings",
+			Type:       "savings",
+			Currency:   "GBP",
+			HolderType: "business",
+			Closed:     true,
+		},
+	}, accounts)
+}
+
+func TestExportClosingBalance(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		balances        []*openbanking.Balance
+		expectedBalance domain.Money
+		expectedErr     string
+	}{
+		"unwinds booked transactions since the end date": {
+			balances: []*openbanking.Balance{
+				{Type: openbanking.BalanceInterimAvailable, Amount: openbanking.Amount{Amount: "9999.00", Currency: "GBP"}, CreditDebitIndicator: openbanking.Credit, DateTime: dateTime(31, 0)},
+				{Type: openbanking.BalanceInterimBooked, Amount: openbanking.Amount{Amount: "2000.00", Currency: "GBP"}, CreditDebitIndicator: openbanking.Credit, DateTime: dateTime(31, 0)},
+			},
+			expectedBalance: domain.Money{MinorUnit: 200000 + 4218 - 150000, Currency: "GBP"},
+		},
+		"returns error without a booked balance": {
+			balances: []*openbanking.Balance{
+				{Type: openbanking.BalanceExpected, Amount: openbanking.Amount{Amount: "1.00", Currency: "GBP"}, DateTime: dateTime(31, 0)},
+			},
+			expectedErr: "closing balance is not supported: no booked balance",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newStubClient()
+			client.Balances = test.balances
+
+			exporter, err := openbankingexporter.New(client)
+			require.NoError(t, err)
+
+			balance, err := exporter.ExportClosingBalance(t.Context(), export.TransactionOptions{
+				StartDate: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				Options:   export.Options{AuthToken: "test-token"},
+			})
+
+			if test.expectedErr != "" {
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedBalance, balance)
+		})
+	}
+}
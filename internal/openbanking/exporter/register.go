@@ -0,0 +1,88 @@
+package exporter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/oauth"
+	"github.com/HallyG/fingrab/internal/openbanking"
+)
+
+// Open Banking banks only differ by configuration, which is read from environment variables
+const (
+	envBaseURLSuffix     = "_BASE_URL"
+	envFinancialIDSuffix = "_FINANCIAL_ID"
+	envAuthURLSuffix     = "_AUTH_URL"
+	envTokenURLSuffix    = "_TOKEN_URL"
+	envScopesSuffix      = "_SCOPES"
+	defaultScopes        = "openid accounts"
+)
+
+func init() {
+	description := fmt.Sprintf(`Commands for interacting with any bank implementing the Open Banking UK Account and Transaction API v3.1.
+The bank is configured with environment variables:
+
+  %s     Account and Transaction API base URL, e.g. https://api.bank.co.uk/open-banking/v3.1/aisp
+  %s Financial institution ID sent as x-fapi-financial-id, if the bank requires it
+  %s    Bank name given to exported transactions
+  %s     OAuth2 authorization endpoint
+  %s    OAuth2 token endpoint
+  %s       OAuth2 scopes, defaults to "%s"`,
+		export.EnvVarName(ExportTypeOpenBanking, envBaseURLSuffix),
+		export.EnvVarName(ExportTypeOpenBanking, envFinancialIDSuffix),
+		export.EnvVarName(ExportTypeOpenBanking, export.EnvBankNameSuffix),
+		export.EnvVarName(ExportTypeOpenBanking, envAuthURLSuffix),
+		export.EnvVarName(ExportTypeOpenBanking, envTokenURLSuffix),
+		export.EnvVarName(ExportTypeOpenBanking, envScopesSuffix),
+		defaultScopes,
+	)
+
+	export.Register(ExportTypeOpenBanking, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName("Open Banking UK"),
+		export.WithDescription(description),
+		// Open Banking endpoints depend on the bank, so they're configured rather than known
+		export.WithOAuth(oauthConfig),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+// NewFromOptions returns an exporter of the bank configured by the environment variables.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	baseURL := export.Getenv(ExportTypeOpenBanking, envBaseURLSuffix)
+	if baseURL == "" {
+		return nil, errors.New(export.EnvVarName(ExportTypeOpenBanking, envBaseURLSuffix) + " is required")
+	}
+
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+
+	api, err := openbanking.New(client, baseURL,
+		api.WithAuthToken(opts.BearerAuthToken()),
+		openbanking.WithFinancialID(export.Getenv(ExportTypeOpenBanking, envFinancialIDSuffix)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(api, WithBankName(export.Getenv(ExportTypeOpenBanking, export.EnvBankNameSuffix)))
+}
+
+func oauthConfig() oauth.Config {
+	scopes := export.Getenv(ExportTypeOpenBanking, envScopesSuffix)
+	if scopes == "" {
+		scopes = defaultScopes
+	}
+
+	return oauth.Config{
+		AuthURL:  export.Getenv(ExportTypeOpenBanking, envAuthURLSuffix),
+		TokenURL: export.Getenv(ExportTypeOpenBanking, envTokenURLSuffix),
+		Scopes:   strings.Fields(scopes),
+	}
+}
@@ -0,0 +1,184 @@
+// Package openbanking is a client for the Open Banking UK (OBIE) Account and Transaction API v3.1, implemented by
+// every UK bank regulated under PSD2. Banks only differ by base URL and OAuth endpoints.
+package openbanking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	resty "resty.dev/v3"
+)
+
+const (
+	getAccountsRoute     = "/accounts"
+	getBalancesRoute     = "/accounts/%s/balances"
+	getTransactionsRoute = "/accounts/%s/transactions"
+	financialIDHeader    = "x-fapi-financial-id"
+	dateTimeFormat       = "2006-01-02T15:04:05"
+	maxPages             = 1000
+)
+
+var _ Client = (*client)(nil)
+
+type (
+	Client interface {
+		FetchAccounts(ctx context.Context) ([]*Account, error)
+		FetchBalances(ctx context.Context, accountID string) ([]*Balance, error)
+		FetchTransactions(ctx context.Context, opts FetchTransactionOptions) ([]*Transaction, error)
+	}
+	client struct {
+		api     *resty.Client
+		baseURL *url.URL
+	}
+)
+
+type FetchTransactionOptions struct {
+	AccountID string
+	Start     time.Time // Inclusive
+	End       time.Time // Inclusive, as OBIE's toBookingDateTime is
+}
+
+// WithFinancialID configures the client to send the bank's financial institution ID, which some banks require.
+func WithFinancialID(financialID string) api.Option {
+	return func(c *resty.Client) {
+		if financialID != "" {
+			c.SetHeader(financialIDHeader, financialID)
+		}
+	}
+}
+
+// New returns a client of the Account and Transaction API at the base URL,
+// e.g. https://api.bank.co.uk/open-banking/v3.1/aisp.
+func New(httpClient *http.Client, baseURL string, opts ...api.Option) (*client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+
+	c := api.New(
+		baseURL,
+		httpClient,
+		api.WithError[Error](),
+	)
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(c)
+	}
+
+	// Relative links resolve against the base URL's directory, which would otherwise drop its last segment, e.g. aisp
+	if !strings.HasSuffix(parsed.Path, "/") {
+		parsed.Path += "/"
+	}
+
+	return &client{
+		api:     c,
+		baseURL: parsed,
+	}, nil
+}
+
+func (c *client) FetchAccounts(ctx context.Context) ([]*Account, error) {
+	return fetchAll(ctx, c, getAccountsRoute, url.Values{}, func(data struct {
+		Account []*Account `json:"Account"`
+	}) []*Account {
+		return data.Account
+	})
+}
+
+func (c *client) FetchBalances(ctx context.Context, accountID string) ([]*Balance, error) {
+	return fetchAll(ctx, c, fmt.Sprintf(getBalancesRoute, url.PathEscape(accountID)), url.Values{}, func(data struct {
+		Balance []*Balance `json:"Balance"`
+	}) []*Balance {
+		return data.Balance
+	})
+}
+
+// FetchTransactions fetches the account's transactions booked within the time range, following every page.
+func (c *client) FetchTransactions(ctx context.Context, opts FetchTransactionOptions) ([]*Transaction, error) {
+	if opts.AccountID == "" {
+		return nil, errors.New("account ID is required")
+	}
+
+	// OBIE filter date times mustn't include a time zone, so the range is sent as UTC times without one
+	values := url.Values{}
+	if !opts.Start.IsZero() {
+		values.Set("fromBookingDateTime", opts.Start.UTC().Format(dateTimeFormat))
+	}
+
+	if !opts.End.IsZero() {
+		values.Set("toBookingDateTime", opts.End.UTC().Format(dateTimeFormat))
+	}
+
+	return fetchAll(ctx, c, fmt.Sprintf(getTransactionsRoute, url.PathEscape(opts.AccountID)), values, func(data struct {
+		Transaction []*Transaction `json:"Transaction"`
+	}) []*Transaction {
+		return data.Transaction
+	})
+}
+
+// fetchAll requests the route and every page after it, by following the Links.Next of each response.
+// Next links must be on the same host as the base URL, so the auth token is never sent elsewhere.
+func fetchAll[D any, T any](ctx context.Context, c *client, route string, values url.Values, items func(D) []*T) ([]*T, error) {
+	var all []*T
+
+	seen := make(map[string]struct{})
+	next := route
+	for page := 1; next != ""; page++ {
+		if page > maxPages {
+			return nil, fmt.Errorf("pagination exceeded maximum number of pages (%d)", maxPages)
+		}
+
+		result, err := api.ExecuteRequest[response[D]](ctx, c.api, http.MethodGet, next, values)
+		if err != nil {
+			if page == 1 {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		all = append(all, items(result.Data)...)
+
+		// The next link carries its own query parameters
+		values = url.Values{}
+		seen[next] = struct{}{}
+		if result.Links.Next == "" {
+			break
+		}
+
+		next, err = c.resolveLink(result.Links.Next)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", page, err)
+		}
+
+		if _, ok := seen[next]; ok {
+			return nil, fmt.Errorf("page %d: next link %q was already fetched", page, next)
+		}
+	}
+
+	return all, nil
+}
+
+// resolveLink returns the absolute URL of the link, which may be relative to the base URL.
+func (c *client) resolveLink(link string) (string, error) {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %w", link, err)
+	}
+
+	resolved := c.baseURL.ResolveReference(parsed)
+	if resolved.Scheme != c.baseURL.Scheme || resolved.Host != c.baseURL.Host {
+		return "", fmt.Errorf("next link %q is not on %s", link, c.baseURL.Host)
+	}
+
+	return resolved.String(), nil
+}
@@ -0,0 +1,227 @@
+package openbanking_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/openbanking"
+	"github.com/HallyG/fingrab/internal/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token       = "mock-token"
+	financialID = "0015800001041RHAAY"
+	basePath    = "/open-banking/v3.1/aisp"
+	accountID   = "22289"
+)
+
+func setup(t *testing.T, routes ...testhelper.HTTPTestRoute) openbanking.Client {
+	t.Helper()
+
+	server := testhelper.NewHTTPTestServer(t, routes)
+	client, err := openbanking.New(&http.Client{}, server.URL+basePath,
+		api.WithAuthToken(token),
+		openbanking.WithFinancialID(financialID),
+	)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		baseURL     string
+		expectedErr string
+	}{
+		"success": {
+			baseURL: "https://api.bank.co.uk/open-banking/v3.1/aisp",
+		},
+		"returns error when base URL is empty": {
+			expectedErr: `invalid base URL ""`,
+		},
+		"returns error when base URL is relative": {
+			baseURL:     "/open-banking/v3.1/aisp",
+			expectedErr: `invalid base URL "/open-banking/v3.1/aisp"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := openbanking.New(nil, test.baseURL)
+
+			if test.expectedErr != "" {
+				require.Nil(t, client)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, client)
+		})
+	}
+}
+
+func TestFetchAccounts(t *testing.T) {
+	t.Parallel()
+
+	client := setup(t, testhelper.HTTPTestRoute{
+		Method: http.MethodGet,
+		URL:    basePath + "/accounts",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			header := http.Header{}
+			header.Add("Authorization", token)
+			header.Add("x-fapi-financial-id", financialID)
+
+			testhelper.AssertRequest(t, r, http.MethodGet, header, url.Values{})
+			testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "accounts.json")(w, r)
+		},
+	})
+
+	accounts, err := client.FetchAccounts(t.Context())
+
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	require.Equal(t, accountID, accounts[0].AccountID)
+	require.Equal(t, "CurrentAccount", accounts[0].AccountSubType)
+	require.Equal(t, time.Date(2019, time.April, 1, 0, 0, 0, 0, time.UTC), accounts[0].OpeningDate.UTC())
+	require.Equal(t, openbanking.SchemeIBAN, accounts[0].Account[1].SchemeName)
+	require.Equal(t, "NWBKGB2L", accounts[0].Servicer.Identification)
+}
+
+func TestFetchBalances(t *testing.T) {
+	t.Parallel()
+
+	client := setup(t, testhelper.HTTPTestRoute{
+		Method:  http.MethodGet,
+		URL:     basePath + "/accounts/22289/balances",
+		Handler: testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "balances.json"),
+	})
+
+	balances, err := client.FetchBalances(t.Context(), accountID)
+
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	require.Equal(t, openbanking.BalanceInterimBooked, balances[1].Type)
+	require.Equal(t, openbanking.Amount{Amount: "1180.50", Currency: "GBP"}, balances[1].Amount)
+	require.Equal(t, openbanking.Credit, balances[1].CreditDebitIndicator)
+}
+
+func TestFetchTransactions(t *testing.T) {
+	t.Parallel()
+
+	opts := openbanking.FetchTransactionOptions{
+		AccountID: accountID,
+		Start:     time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		End:       time.Date(2025, time.March, 31, 23, 59, 59, 0, time.UTC),
+	}
+
+	tests := map[string]struct {
+		handler     http.HandlerFunc
+		opts        openbanking.FetchTransactionOptions
+		expectedIDs []string
+		expectedErr string
+	}{
+		"follows next links across pages": {
+			opts: opts,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") == "2" {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "transactions-page-2.json")(w, r)
+					return
+				}
+
+				query := url.Values{}
+				query.Add("fromBookingDateTime", "2025-03-01T00:00:00")
+				query.Add("toBookingDateTime", "2025-03-31T23:59:59")
+
+				testhelper.AssertRequest(t, r, http.MethodGet, http.Header{}, query)
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "transactions-page-1.json")(w, r)
+			},
+			expectedIDs: []string{"123", "124", "125"},
+		},
+		"follows next links relative to the base URL": {
+			opts: opts,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("page") == "2" {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "transactions-page-2.json")(w, r)
+					return
+				}
+
+				page, err := os.ReadFile(filepath.Join("testdata", "api", "transactions-page-1.json"))
+				require.NoError(t, err)
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write(bytes.ReplaceAll(page, []byte(`"`+basePath+`/accounts`), []byte(`"accounts`)))
+			},
+			expectedIDs: []string{"123", "124", "125"},
+		},
+		"returns error when next link is on another host": {
+			opts: opts,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"Data": {"Transaction": []}, "Links": {"Next": "https://attacker.example.com/transactions?page=2"}}`))
+			},
+			expectedErr: `page 1: next link "https://attacker.example.com/transactions?page=2" is not on`,
+		},
+		"returns error when next link repeats": {
+			opts: opts,
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"Data": {"Transaction": []}, "Links": {"Next": "/open-banking/v3.1/aisp/accounts/22289/transactions?page=2"}}`))
+			},
+			expectedErr: "page 2: next link",
+		},
+		"returns API error": {
+			opts:        opts,
+			handler:     testhelper.ServeJSONTestDataHandler(t, http.StatusBadRequest, "error.json"),
+			expectedErr: "There was a problem with the request[UK.OBIE.Field.Invalid: fromBookingDateTime is invalid]",
+		},
+		"returns error when account ID is empty": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				t.Error("unexpected request")
+			},
+			expectedErr: "account ID is required",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     basePath + "/accounts/22289/transactions",
+				Handler: test.handler,
+			})
+
+			transactions, err := client.FetchTransactions(t.Context(), test.opts)
+
+			if test.expectedErr != "" {
+				require.Nil(t, transactions)
+				require.ErrorContains(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(transactions))
+			for _, txn := range transactions {
+				ids = append(ids, txn.TransactionID)
+			}
+
+			require.Equal(t, test.expectedIDs, ids)
+			require.Equal(t, "Tesco Stores", transactions[0].MerchantDetails.MerchantName)
+			require.Equal(t, time.Date(2025, time.March, 4, 9, 0, 0, 0, time.UTC), transactions[1].BookingDateTime.Time)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package openbanking
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type CreditDebitIndicator string
+
+const (
+	Credit CreditDebitIndicator = "Credit"
+	Debit  CreditDebitIndicator = "Debit"
+)
+
+type TransactionStatus string
+
+const (
+	StatusBooked   TransactionStatus = "Booked"
+	StatusPending  TransactionStatus = "Pending"
+	StatusRejected TransactionStatus = "Rejected"
+)
+
+type BalanceType string
+
+const (
+	BalanceClosingBooked    BalanceType = "ClosingBooked"
+	BalanceInterimBooked    BalanceType = "InterimBooked"
+	BalanceClosingAvailable BalanceType = "ClosingAvailable"
+	BalanceInterimAvailable BalanceType = "InterimAvailable"
+	BalanceExpected         BalanceType = "Expected"
+)
+
+// Scheme names of account identifications.
+const (
+	SchemeSortCodeAccountNumber = "UK.OBIE.SortCodeAccountNumber"
+	SchemeIBAN                  = "UK.OBIE.IBAN"
+	SchemeBIC                   = "UK.OBIE.BICFI"
+)
+
+// DateTime is an ISO 8601 date time. Banks differ on whether they include a time zone, so times without one are UTC.
+type DateTime struct {
+	time.Time
+}
+
+var dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"}
+
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		return nil
+	}
+
+	for _, layout := range dateTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			d.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("parse date time %q", value)
+}
+
+// Amount is a decimal amount, e.g. "10.50", without its sign, which is given by a CreditDebitIndicator.
+type Amount struct {
+	Amount   string `json:"Amount"`
+	Currency string `json:"Currency"`
+}
+
+type AccountIdentification struct {
+	SchemeName              string `json:"SchemeName"`
+	Identification          string `json:"Identification"`
+	Name                    string `json:"Name"`
+	SecondaryIdentification string `json:"SecondaryIdentification"`
+}
+
+type Account struct {
+	AccountID      string                   `json:"AccountId"`
+	Status         string                   `json:"Status"` // e.g. Enabled, Disabled, Deleted, ProForma or Pending
+	Currency       string                   `json:"Currency"`
+	AccountType    string                   `json:"AccountType"`    // Business or Personal
+	AccountSubType string                   `json:"AccountSubType"` // e.g. CurrentAccount, Savings or CreditCard
+	Nickname       string                   `json:"Nickname"`
+	OpeningDate    DateTime                 `json:"OpeningDate"`
+	Account        []*AccountIdentification `json:"Account"`
+	Servicer       *AccountIdentification   `json:"Servicer"`
+}
+
+type Balance struct {
+	AccountID            string               `json:"AccountId"`
+	Amount               Amount               `json:"Amount"`
+	CreditDebitIndicator CreditDebitIndicator `json:"CreditDebitIndicator"`
+	Type                 BalanceType          `json:"Type"`
+	DateTime             DateTime             `json:"DateTime"`
+}
+
+type BankTransactionCode struct {
+	Code    string `json:"Code"`
+	SubCode string `json:"SubCode"`
+}
+
+type ProprietaryBankTransactionCode struct {
+	Code   string `json:"Code"`
+	Issuer string `json:"Issuer"`
+}
+
+type MerchantDetails struct {
+	MerchantName         string `json:"MerchantName"`
+	MerchantCategoryCode string `json:"MerchantCategoryCode"`
+}
+
+type Transaction struct {
+	AccountID                      string                          `json:"AccountId"`
+	TransactionID                  string                          `json:"TransactionId"`
+	TransactionReference           string                          `json:"TransactionReference"`
+	CreditDebitIndicator           CreditDebitIndicator            `json:"CreditDebitIndicator"`
+	Status                         TransactionStatus               `json:"Status"`
+	BookingDateTime                DateTime                        `json:"BookingDateTime"`
+	ValueDateTime                  DateTime                        `json:"ValueDateTime"`
+	TransactionInformation         string                          `json:"TransactionInformation"`
+	Amount                         Amount                          `json:"Amount"`
+	BankTransactionCode            *BankTransactionCode            `json:"BankTransactionCode"`
+	ProprietaryBankTransactionCode *ProprietaryBankTransactionCode `json:"ProprietaryBankTransactionCode"`
+	MerchantDetails                *MerchantDetails                `json:"MerchantDetails"`
+	CreditorAccount                *AccountIdentification          `json:"CreditorAccount"`
+	DebtorAccount                  *AccountIdentification          `json:"DebtorAccount"`
+}
+
+type Links struct {
+	Self  string `json:"Self"`
+	First string `json:"First"`
+	Prev  string `json:"Prev"`
+	Next  string `json:"Next"`
+	Last  string `json:"Last"`
+}
+
+type Meta struct {
+	TotalPages int `json:"TotalPages"`
+}
+
+// response is the envelope of every Account and Transaction API response.
+type response[T any] struct {
+	Data  T     `json:"Data"`
+	Links Links `json:"Links"`
+	Meta  Meta  `json:"Meta"`
+}
+
+type ErrorDetail struct {
+	ErrorCode string `json:"ErrorCode"`
+	Message   string `json:"Message"`
+	Path      string `json:"Path"`
+}
+
+type Error struct {
+	Code    string        `json:"Code"`
+	ID      string        `json:"Id"`
+	Message string        `json:"Message"`
+	Errors  []ErrorDetail `json:"Errors"`
+}
+
+func (err Error) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString(err.Message)
+
+	if len(err.Errors) > 0 {
+		details := make([]string, len(err.Errors))
+		for i, e := range err.Errors {
+			details[i] = e.ErrorCode + ": " + e.Message
+		}
+
+		sb.WriteString("[")
+		sb.WriteString(strings.Join(details, ", "))
+		sb.WriteString("]")
+	}
+
+	return sb.String()
+}
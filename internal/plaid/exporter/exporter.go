@@ -0,0 +1,341 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/plaid"
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/samber/lo"
+)
+
+const (
+	ExportTypePlaid   = export.ExportType("plaid")
+	plaidMaxDateRange = time.Duration(0)
+	defaultBankName   = "Plaid"
+	dateFormat        = "2006-01-02"
+)
+
+var _ export.Exporter = (*TransactionExporter)(nil)
+
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+	Pending:      true,
+	Cursors:      true,
+}
+
+type TransactionExporter struct {
+	api      plaid.Client
+	bankName string
+	store    *store.Store
+}
+
+type Option func(*TransactionExporter)
+
+// WithBankName configures the bank name given to exported transactions, e.g. Chase.
+func WithBankName(name string) Option {
+	return func(e *TransactionExporter) {
+		if name != "" {
+			e.bankName = name
+		}
+	}
+}
+
+// WithStore configures the exporter to persist the cursors of transaction syncs in the given store.
+func WithStore(s *store.Store) Option {
+	return func(e *TransactionExporter) {
+		e.store = s
+	}
+}
+
+func New(api plaid.Client, opts ...Option) (*TransactionExporter, error) {
+	if api == nil {
+		return nil, errors.New("plaid client is required")
+	}
+
+	exporter := &TransactionExporter{
+		api:      api,
+		bankName: defaultBankName,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(exporter)
+	}
+
+	return exporter, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return ExportTypePlaid
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return plaidMaxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	accounts, err := e.api.FetchAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	return lo.Map(accounts.Accounts, func(account *plaid.Account, _ int) *domain.Account {
+		accountType := account.Subtype
+		if accountType == "" {
+			accountType = account.Type
+		}
+
+		return &domain.Account{
+			ID:       account.AccountID,
+			Name:     accountName(account),
+			Type:     accountType,
+			Currency: currency(account.Balances.ISOCurrencyCode, account.Balances.UnofficialCurrencyCode),
+		}
+	}), nil
+}
+
+// ExportTransactions returns the selected account's transactions between the dates, or every account's when the
+// account is "all". Plaid only returns changes since a cursor, so the item's whole history is synced without
+// storing the cursor. Accounts are selected by ID, name or mask (the end of the account number).
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if opts.Space != "" {
+		return nil, errors.New("spaces are not supported by plaid")
+	}
+
+	accounts, err := e.api.FetchAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	selected, err := selectAccounts(accounts.Accounts, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := e.api.SyncTransactions(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("sync transactions: %w", err)
+	}
+
+	// Later pages can modify or remove transactions added by earlier ones
+	latest := make(map[string]*plaid.Transaction)
+	for _, txn := range slices.Concat(changes.Added, changes.Modified) {
+		latest[txn.TransactionID] = txn
+	}
+
+	for _, removed := range changes.Removed {
+		delete(latest, removed.TransactionID)
+	}
+
+	names := accountNames(accounts.Accounts)
+
+	transactions := make([]*domain.Transaction, 0)
+	for _, txn := range slices.Concat(changes.Added, changes.Modified) {
+		if latest[txn.TransactionID] != txn {
+			continue
+		}
+
+		if !lo.ContainsBy(selected, func(account *plaid.Account) bool {
+			return account.AccountID == txn.AccountID
+		}) {
+			continue
+		}
+
+		transaction, err := toTransaction(txn, names[txn.AccountID], e.bankName)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", txn.TransactionID, err)
+		}
+
+		if transaction.CreatedAt.Before(opts.StartDate) || !transaction.CreatedAt.Before(opts.EndDate) {
+			continue
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	slices.SortStableFunc(transactions, func(a, b *domain.Transaction) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	log.FromContext(ctx).InfoContext(ctx, "fetched transactions",
+		slog.Int("transaction.count", len(transactions)),
+	)
+
+	return transactions, nil
+}
+
+// selectAccounts returns the account matching the selector, or every account when the selector is "all".
+// An empty selector selects the first account.
+func selectAccounts(accounts []*plaid.Account, selector string) ([]*plaid.Account, error) {
+	if len(accounts) == 0 {
+		return nil, errors.New("no accounts found")
+	}
+
+	if strings.EqualFold(selector, export.AllAccounts) {
+		return accounts, nil
+	}
+
+	if selector == "" {
+		return accounts[:1], nil
+	}
+
+	if account, ok := lo.Find(accounts, func(account *plaid.Account) bool {
+		return account.AccountID == selector
+	}); ok {
+		return []*plaid.Account{account}, nil
+	}
+
+	matches := lo.Filter(accounts, func(account *plaid.Account, _ int) bool {
+		return strings.EqualFold(account.Name, selector) ||
+			strings.EqualFold(account.OfficialName, selector) ||
+			(account.Mask != "" && account.Mask == selector)
+	})
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no account matches %q", selector)
+	case 1:
+		return matches, nil
+	default:
+		ids := lo.Map(matches, func(account *plaid.Account, _ int) string {
+			return account.AccountID
+		})
+
+		return nil, fmt.Errorf("%q matches %d accounts (%s), use an account ID instead", selector, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+func accountName(account *plaid.Account) string {
+	if account.Name != "" {
+		return account.Name
+	}
+
+	return account.OfficialName
+}
+
+func accountNames(accounts []*plaid.Account) map[string]string {
+	return lo.SliceToMap(accounts, func(account *plaid.Account) (string, string) {
+		return account.AccountID, accountName(account)
+	})
+}
+
+func currency(isoCode string, unofficialCode string) string {
+	if isoCode != "" {
+		return isoCode
+	}
+
+	return unofficialCode
+}
+
+func toTransaction(txn *plaid.Transaction, account string, bankName string) (*domain.Transaction, error) {
+	createdAt, err := transactionDate(txn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Plaid's amounts are positive when money leaves the account
+	amount := -txn.Amount
+
+	reference := txn.MerchantName
+	notes := txn.Name
+	if reference == "" {
+		reference = txn.Name
+		notes = ""
+	}
+
+	status := domain.TransactionStatusSettled
+	if txn.Pending {
+		status = domain.TransactionStatusPending
+	}
+
+	return &domain.Transaction{
+		ID:        txn.TransactionID,
+		Amount:    domain.MoneyFromMajorUnit(amount, currency(txn.ISOCurrencyCode, txn.UnofficialCurrencyCode)),
+		Reference: reference,
+		Category:  category(txn.PersonalFinanceCategory),
+		CreatedAt: createdAt,
+		IsDeposit: amount > 0,
+		BankName:  bankName,
+		Account:   account,
+		Notes:     notes,
+		Status:    status,
+	}, nil
+}
+
+// transactionDate returns the time the transaction posted, falling back to the posted date, which is the only one
+// most institutions provide.
+func transactionDate(txn *plaid.Transaction) (time.Time, error) {
+	if txn.Datetime != nil {
+		return *txn.Datetime, nil
+	}
+
+	date, err := time.Parse(dateFormat, txn.Date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parse date %q: %w", txn.Date, err)
+	}
+
+	return date, nil
+}
+
+// categories maps Plaid's primary personal finance categories to domain categories.
+var categories = map[string]string{
+	"INCOME":                    domain.CategoryIncome,
+	"TRANSFER_IN":               domain.CategoryTransfers,
+	"TRANSFER_OUT":              domain.CategoryTransfers,
+	"LOAN_PAYMENTS":             domain.CategoryFinances,
+	"BANK_FEES":                 domain.CategoryFinances,
+	"ENTERTAINMENT":             domain.CategoryEntertainment,
+	"FOOD_AND_DRINK":            domain.CategoryEatingOut,
+	"GENERAL_MERCHANDISE":       domain.CategoryShopping,
+	"HOME_IMPROVEMENT":          domain.CategoryShopping,
+	"MEDICAL":                   domain.CategoryPersonalCare,
+	"PERSONAL_CARE":             domain.CategoryPersonalCare,
+	"GENERAL_SERVICES":          domain.CategoryGeneral,
+	"GOVERNMENT_AND_NON_PROFIT": domain.CategoryGeneral,
+	"TRANSPORTATION":            domain.CategoryTransport,
+	"TRAVEL":                    domain.CategoryHolidays,
+	"RENT_AND_UTILITIES":        domain.CategoryBills,
+}
+
+// detailedCategories overrides the primary category's mapping for detailed categories which have their own.
+var detailedCategories = map[string]string{
+	"FOOD_AND_DRINK_GROCERIES":            domain.CategoryGroceries,
+	"GOVERNMENT_AND_NON_PROFIT_DONATIONS": domain.CategoryCharity,
+}
+
+func category(pfc *plaid.PersonalFinanceCategory) string {
+	if pfc == nil {
+		return ""
+	}
+
+	if category, ok := detailedCategories[pfc.Detailed]; ok {
+		return category
+	}
+
+	if category, ok := categories[pfc.Primary]; ok {
+		return category
+	}
+
+	return strings.ToLower(pfc.Primary)
+}
@@ -0,0 +1,349 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/plaid"
+	plaidexporter "github.com/HallyG/fingrab/internal/plaid/exporter"
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+type StubClient struct {
+	Accounts *plaid.Accounts
+	Changes  map[string]*plaid.TransactionsSync // By cursor
+	SyncErr  error
+
+	RequestedCursors []string
+}
+
+var _ plaid.Client = (*StubClient)(nil)
+
+func (c *StubClient) FetchAccounts(ctx context.Context) (*plaid.Accounts, error) {
+	return c.Accounts, nil
+}
+
+func (c *StubClient) SyncTransactions(ctx context.Context, cursor string) (*plaid.TransactionsSync, error) {
+	if c.SyncErr != nil {
+		return nil, c.SyncErr
+	}
+
+	c.RequestedCursors = append(c.RequestedCursors, cursor)
+
+	changes, ok := c.Changes[cursor]
+	if !ok {
+		return &plaid.TransactionsSync{NextCursor: cursor}, nil
+	}
+
+	return changes, nil
+}
+
+func newStubClient() *StubClient {
+	posted := time.Date(2025, time.March, 3, 11, 0, 0, 0, time.UTC)
+
+	return &StubClient{
+		Accounts: &plaid.Accounts{
+			Item: plaid.Item{ItemID: "item-1"},
+			Accounts: []*plaid.Account{
+				{AccountID: "checking", Name: "Plaid Checking", Mask: "0000", Type: "depository", Subtype: "checking", Balances: plaid.Balances{ISOCurrencyCode: "USD"}},
+				{AccountID: "credit", Name: "Plaid Credit Card", OfficialName: "Plaid Diamond Credit Card", Mask: "3333", Type: "credit", Balances: plaid.Balances{ISOCurrencyCode: "USD"}},
+			},
+		},
+		Changes: map[string]*plaid.TransactionsSync{
+			"": {
+				Added: []*plaid.Transaction{
+					{
+						TransactionID:           "pending-groceries",
+						AccountID:               "checking",
+						Amount:                  70,
+						ISOCurrencyCode:         "USD",
+						Date:                    "2025-03-02",
+						Name:                    "WHOLE FOODS MARKET #10234",
+						MerchantName:            "Whole Foods",
+						Pending:                 true,
+						PersonalFinanceCategory: &plaid.PersonalFinanceCategory{Primary: "FOOD_AND_DRINK", Detailed: "FOOD_AND_DRINK_GROCERIES"},
+					},
+					{
+						TransactionID:           "groceries",
+						AccountID:               "checking",
+						Amount:                  72.1,
+						ISOCurrencyCode:         "USD",
+						Date:                    "2025-03-03",
+						Datetime:                &posted,
+						Name:                    "WHOLE FOODS MARKET #10234",
+						MerchantName:            "Whole Foods",
+						PendingTransactionID:    "pending-groceries",
+						PersonalFinanceCategory: &plaid.PersonalFinanceCategory{Primary: "FOOD_AND_DRINK", Detailed: "FOOD_AND_DRINK_GROCERIES"},
+					},
+					{
+						TransactionID:           "salary",
+						AccountID:               "checking",
+						Amount:                  -2500,
+						ISOCurrencyCode:         "USD",
+						Date:                    "2025-03-04",
+						Name:                    "ACME CORP PAYROLL",
+						PersonalFinanceCategory: &plaid.PersonalFinanceCategory{Primary: "INCOME", Detailed: "INCOME_WAGES"},
+					},
+					{
+						TransactionID:           "uber",
+						AccountID:               "credit",
+						Amount:                  6.33,
+						ISOCurrencyCode:         "USD",
+						Date:                    "2025-03-01",
+						Name:                    "Uber 063015 SF**POOL**",
+						MerchantName:            "Uber",
+						Pending:                 true,
+						PersonalFinanceCategory: &plaid.PersonalFinanceCategory{Primary: "TRANSPORTATION"},
+					},
+					{
+						TransactionID:   "april",
+						AccountID:       "checking",
+						Amount:          1,
+						ISOCurrencyCode: "USD",
+						Date:            "2025-04-01",
+						Name:            "AFTER THE END DATE",
+					},
+				},
+				Modified: []*plaid.Transaction{
+					{
+						TransactionID:           "uber",
+						AccountID:               "credit",
+						Amount:                  7.5,
+						ISOCurrencyCode:         "USD",
+						Date:                    "2025-03-01",
+						Name:                    "Uber 063015 SF**POOL**",
+						MerchantName:            "Uber",
+						Pending:                 true,
+						PersonalFinanceCategory: &plaid.PersonalFinanceCategory{Primary: "TRANSPORTATION"},
+					},
+				},
+				Removed: []*plaid.RemovedTransaction{
+					{TransactionID: "pending-groceries", AccountID: "checking"},
+				},
+				NextCursor: "cursor-1",
+			},
+			"cursor-1": {
+				Added: []*plaid.Transaction{
+					{
+						TransactionID:           "donation",
+						AccountID:               "checking",
+						Amount:                  25,
+						ISOCurrencyCode:         "USD",
+						Date:                    "2025-03-10",
+						Name:                    "RED CROSS",
+						PersonalFinanceCategory: &plaid.PersonalFinanceCategory{Primary: "GOVERNMENT_AND_NON_PROFIT", Detailed: "GOVERNMENT_AND_NON_PROFIT_DONATIONS"},
+					},
+				},
+				Modified: []*plaid.Transaction{
+					{
+						TransactionID:           "uber",
+						AccountID:               "credit",
+						Amount:                  7.5,
+						ISOCurrencyCode:         "USD",
+						Date:                    "2025-03-02",
+						Name:                    "Uber 063015 SF**POOL**",
+						MerchantName:            "Uber",
+						PersonalFinanceCategory: &plaid.PersonalFinanceCategory{Primary: "TRANSPORTATION"},
+					},
+				},
+				Removed:    []*plaid.RemovedTransaction{{TransactionID: "salary", AccountID: "checking"}},
+				NextCursor: "cursor-2",
+			},
+		},
+	}
+}
+
+var (
+	groceries = &domain.Transaction{
+		ID:        "groceries",
+		Amount:    domain.Money{MinorUnit: -7210, Currency: "USD"},
+		Reference: "Whole Foods",
+		Category:  domain.CategoryGroceries,
+		CreatedAt: time.Date(2025, time.March, 3, 11, 0, 0, 0, time.UTC),
+		BankName:  "Chase",
+		Account:   "Plaid Checking",
+		Notes:     "WHOLE FOODS MARKET #10234",
+		Status:    domain.TransactionStatusSettled,
+	}
+	salary = &domain.Transaction{
+		ID:        "salary",
+		Amount:    domain.Money{MinorUnit: 250000, Currency: "USD"},
+		Reference: "ACME CORP PAYROLL",
+		Category:  domain.CategoryIncome,
+		CreatedAt: time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC),
+		IsDeposit: true,
+		BankName:  "Chase",
+		Account:   "Plaid Checking",
+		Status:    domain.TransactionStatusSettled,
+	}
+	pendingUber = &domain.Transaction{
+		ID:        "uber",
+		Amount:    domain.Money{MinorUnit: -750, Currency: "USD"},
+		Reference: "Uber",
+		Category:  domain.CategoryTransport,
+		CreatedAt: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		BankName:  "Chase",
+		Account:   "Plaid Credit Card",
+		Notes:     "Uber 063015 SF**POOL**",
+		Status:    domain.TransactionStatusPending,
+	}
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when client is nil", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := plaidexporter.New(nil)
+
+		require.Nil(t, exporter)
+		require.EqualError(t, err, "plaid client is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := plaidexporter.New(&StubClient{})
+
+		require.NoError(t, err)
+		require.Equal(t, plaidexporter.ExportTypePlaid, exporter.Type())
+		require.Equal(t, time.Duration(0), exporter.MaxDateRange())
+	})
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		accountID            string
+		syncErr              error
+		expectedTransactions []*domain.Transaction
+		expectedErr          string
+	}{
+		"exports first account, without removed transactions": {
+			expectedTransactions: []*domain.Transaction{groceries, salary},
+		},
+		"selects account by mask": {
+			accountID:            "3333",
+			expectedTransactions: []*domain.Transaction{pendingUber},
+		},
+		"exports every account, with the latest modification": {
+			accountID:            export.AllAccounts,
+			expectedTransactions: []*domain.Transaction{pendingUber, groceries, salary},
+		},
+		"returns error when no account matches": {
+			accountID:   "savings",
+			expectedErr: `no account matches "savings"`,
+		},
+		"returns error when sync fails": {
+			syncErr:     errors.New("boom"),
+			expectedErr: "sync transactions: boom",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newStubClient()
+			client.SyncErr = test.syncErr
+
+			exporter, err := plaidexporter.New(client, plaidexporter.WithBankName("Chase"))
+			require.NoError(t, err)
+
+			transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				AccountID: test.accountID,
+				StartDate: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+				Options:   export.Options{AuthToken: "test-token"},
+			})
+
+			if test.expectedErr != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransactions, transactions)
+		})
+	}
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	exporter, err := plaidexporter.New(newStubClient())
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Account{
+		{ID: "checking", Name: "Plaid Checking", Type: "checking", Currency: "USD"},
+		{ID: "credit", Name: "Plaid Credit Card", Type: "credit", Currency: "USD"},
+	}, accounts)
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error without a store", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := plaidexporter.New(newStubClient())
+		require.NoError(t, err)
+
+		result, err := exporter.Sync(t.Context())
+
+		require.Nil(t, result)
+		require.EqualError(t, err, "sync store is required")
+	})
+
+	t.Run("continues from the committed cursor", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := store.New(t.TempDir())
+		require.NoError(t, err)
+
+		client := newStubClient()
+		exporter, err := plaidexporter.New(client, plaidexporter.WithBankName("Chase"), plaidexporter.WithStore(s))
+		require.NoError(t, err)
+
+		first, err := exporter.Sync(t.Context())
+		require.NoError(t, err)
+		require.Len(t, first.New, 5)
+		require.Equal(t, []*domain.Transaction{pendingUber}, first.Modified)
+		require.Equal(t, []string{"pending-groceries"}, first.Removed)
+
+		// Until it's committed, the sync is fetched again
+		_, err = exporter.Sync(t.Context())
+		require.NoError(t, err)
+		require.NoError(t, exporter.CommitSync(first))
+
+		second, err := exporter.Sync(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, []string{"", "", "cursor-1"}, client.RequestedCursors)
+		require.Equal(t, []*domain.Transaction{
+			{
+				ID:        "donation",
+				Amount:    domain.Money{MinorUnit: -2500, Currency: "USD"},
+				Reference: "RED CROSS",
+				Category:  domain.CategoryCharity,
+				CreatedAt: time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC),
+				BankName:  "Chase",
+				Account:   "Plaid Checking",
+				Status:    domain.TransactionStatusSettled,
+			},
+		}, second.New)
+		require.Len(t, second.Modified, 1)
+		require.Equal(t, domain.TransactionStatusSettled, second.Modified[0].Status)
+		require.Equal(t, []string{"salary"}, second.Removed)
+	})
+}
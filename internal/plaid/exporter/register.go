@@ -0,0 +1,95 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/plaid"
+	"github.com/HallyG/fingrab/internal/store"
+)
+
+const (
+	envSecretSuffix       = "_SECRET"
+	sandboxEnvironment    = "sandbox"
+	productionEnvironment = "production"
+)
+
+func init() {
+	description := fmt.Sprintf(`Commands for interacting with US and Canadian banks through Plaid. The token is the access token of an item
+(a login at a bank), created with Plaid Link. Plaid is configured with environment variables:
+
+  %s Team client ID
+  %s    Team secret for the environment
+  %s       Environment, %s or %s (default)
+  %s Bank name given to exported transactions, defaults to Plaid`,
+		export.EnvVarName(ExportTypePlaid, export.EnvClientIDSuffix),
+		export.EnvVarName(ExportTypePlaid, envSecretSuffix),
+		export.EnvVarName(ExportTypePlaid, export.EnvEnvironmentSuffix),
+		sandboxEnvironment,
+		productionEnvironment,
+		export.EnvVarName(ExportTypePlaid, export.EnvBankNameSuffix),
+	)
+
+	export.Register(ExportTypePlaid, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName("Plaid"),
+		export.WithDescription(description),
+		// Plaid's access tokens are created by linking an item with Plaid Link, which needs a web page
+		export.WithTokenHelp("link an item with Plaid Link"),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+// NewFromOptions returns an exporter of the item whose access token is the auth token, in the environment and with
+// the team credentials from the environment variables. Its sync cursors are kept in the data directory when there
+// is one.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	clientID := export.Getenv(ExportTypePlaid, export.EnvClientIDSuffix)
+	secret := export.Getenv(ExportTypePlaid, envSecretSuffix)
+	if clientID == "" || secret == "" {
+		return nil, fmt.Errorf("%s and %s are required",
+			export.EnvVarName(ExportTypePlaid, export.EnvClientIDSuffix),
+			export.EnvVarName(ExportTypePlaid, envSecretSuffix),
+		)
+	}
+
+	var baseURL string
+	switch environment := strings.ToLower(export.Getenv(ExportTypePlaid, export.EnvEnvironmentSuffix)); environment {
+	case "", productionEnvironment:
+		baseURL = plaid.ProductionAPI
+	case sandboxEnvironment:
+		baseURL = plaid.SandboxAPI
+	default:
+		return nil, fmt.Errorf("unknown %s %q (options: %s, %s)", export.EnvVarName(ExportTypePlaid, export.EnvEnvironmentSuffix), environment, sandboxEnvironment, productionEnvironment)
+	}
+
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+
+	exporterOpts := []Option{
+		WithBankName(export.Getenv(ExportTypePlaid, export.EnvBankNameSuffix)),
+	}
+	if opts.DataDir != "" {
+		s, err := store.New(opts.DataDir)
+		if err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+
+		exporterOpts = append(exporterOpts, WithStore(s))
+	}
+
+	api, err := plaid.New(client, strings.TrimSpace(opts.AuthToken),
+		api.WithBaseURL(baseURL),
+		plaid.WithCredentials(clientID, secret),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(api, exporterOpts...)
+}
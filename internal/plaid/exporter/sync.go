@@ -0,0 +1,127 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/plaid"
+)
+
+const syncKeyPrefix = "plaid-sync-"
+
+// SyncResult is the changes to every account of the item since the previous sync. Modified transactions (e.g. a
+// pending transaction whose amount changed) and removed ones were returned by an earlier sync, so are kept apart
+// from new ones for downstream tools to update rather than import twice.
+type SyncResult struct {
+	New      []*domain.Transaction
+	Modified []*domain.Transaction
+	Removed  []string // The IDs of removed transactions, e.g. pending transactions which have since posted
+
+	cursor *syncCursor
+}
+
+// syncCursor is the persisted cursor of an item's transactions.
+type syncCursor struct {
+	ItemID string `json:"itemId"`
+	Cursor string `json:"cursor"`
+}
+
+// Sync fetches the changes to the item's transactions since its stored cursor, or its whole history when it hasn't
+// been synced before. The cursor isn't advanced until CommitSync is called with the result, so a sync whose
+// transactions failed to be written is fetched again next time.
+func (e *TransactionExporter) Sync(ctx context.Context) (*SyncResult, error) {
+	if e.store == nil {
+		return nil, errors.New("sync store is required")
+	}
+
+	accounts, err := e.api.FetchAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	cursor := &syncCursor{
+		ItemID: accounts.Item.ItemID,
+	}
+	if _, err := e.store.Load(cursor.key(), cursor); err != nil {
+		return nil, fmt.Errorf("load sync state: %w", err)
+	}
+
+	logger := log.FromContext(ctx)
+	logger.InfoContext(ctx, "fetching transaction changes",
+		slog.String("item.id", cursor.ItemID),
+		slog.Bool("initial", cursor.Cursor == ""),
+	)
+
+	changes, err := e.api.SyncTransactions(ctx, cursor.Cursor)
+	if err != nil {
+		return nil, fmt.Errorf("sync transactions: %w", err)
+	}
+
+	names := accountNames(accounts.Accounts)
+	toTransactions := func(txns []*plaid.Transaction) ([]*domain.Transaction, error) {
+		transactions := make([]*domain.Transaction, 0, len(txns))
+		for _, txn := range txns {
+			transaction, err := toTransaction(txn, names[txn.AccountID], e.bankName)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %s: %w", txn.TransactionID, err)
+			}
+
+			transactions = append(transactions, transaction)
+		}
+
+		return transactions, nil
+	}
+
+	added, err := toTransactions(changes.Added)
+	if err != nil {
+		return nil, err
+	}
+
+	modified, err := toTransactions(changes.Modified)
+	if err != nil {
+		return nil, err
+	}
+
+	removed := make([]string, 0, len(changes.Removed))
+	for _, txn := range changes.Removed {
+		removed = append(removed, txn.TransactionID)
+	}
+
+	logger.InfoContext(ctx, "synced transactions",
+		slog.Int("transaction.new", len(added)),
+		slog.Int("transaction.modified", len(modified)),
+		slog.Int("transaction.removed", len(removed)),
+	)
+
+	return &SyncResult{
+		New:      added,
+		Modified: modified,
+		Removed:  removed,
+		cursor: &syncCursor{
+			ItemID: cursor.ItemID,
+			Cursor: changes.NextCursor,
+		},
+	}, nil
+}
+
+// CommitSync persists the result's cursor, so the next sync only returns later changes.
+// It should be called once the result's transactions have been written.
+func (e *TransactionExporter) CommitSync(result *SyncResult) error {
+	if e.store == nil {
+		return errors.New("sync store is required")
+	}
+
+	if err := e.store.Save(result.cursor.key(), result.cursor); err != nil {
+		return fmt.Errorf("save sync state: %w", err)
+	}
+
+	return nil
+}
+
+func (c *syncCursor) key() string {
+	return syncKeyPrefix + c.ItemID
+}
@@ -0,0 +1,143 @@
+// Package plaid is a client for Plaid, which reads accounts at US and Canadian banks. Requests are authenticated with
+// the team's client ID and secret, and read the item (a login at an institution) of an access token.
+package plaid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/HallyG/fingrab/internal/api"
+	resty "resty.dev/v3"
+)
+
+const (
+	SandboxAPI            = "https://sandbox.plaid.com"
+	ProductionAPI         = "https://production.plaid.com"
+	postAccountsRoute     = "/accounts/get"
+	postSyncRoute         = "/transactions/sync"
+	clientIDHeader        = "PLAID-CLIENT-ID"
+	secretHeader          = "PLAID-SECRET"
+	syncPageSize          = 500
+	maxPages              = 1000
+	maxPaginationRestarts = 3
+)
+
+var _ Client = (*client)(nil)
+
+type (
+	Client interface {
+		FetchAccounts(ctx context.Context) (*Accounts, error)
+		SyncTransactions(ctx context.Context, cursor string) (*TransactionsSync, error)
+	}
+	client struct {
+		api         *resty.Client
+		accessToken string
+	}
+)
+
+// WithCredentials configures the client to authenticate as the team with the client ID and secret.
+func WithCredentials(clientID string, secret string) api.Option {
+	return func(c *resty.Client) {
+		c.SetHeader(clientIDHeader, clientID)
+		c.SetHeader(secretHeader, secret)
+	}
+}
+
+// New returns a client of the item the access token belongs to, in production unless a base URL option is given.
+func New(httpClient *http.Client, accessToken string, opts ...api.Option) (*client, error) {
+	if accessToken == "" {
+		return nil, errors.New("access token is required")
+	}
+
+	c := api.New(
+		ProductionAPI,
+		httpClient,
+		api.WithError[Error](),
+	)
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(c)
+	}
+
+	return &client{
+		api:         c,
+		accessToken: accessToken,
+	}, nil
+}
+
+func (c *client) FetchAccounts(ctx context.Context) (*Accounts, error) {
+	return api.ExecuteRequestWithBody[Accounts](ctx, c.api,
+		http.MethodPost,
+		postAccountsRoute,
+		map[string]any{
+			"access_token": c.accessToken,
+		},
+	)
+}
+
+// SyncTransactions fetches every page of changes since the cursor, or the item's whole history when the cursor is
+// empty. When Plaid reports the transactions changed mid-way, the pages are fetched again from the cursor.
+func (c *client) SyncTransactions(ctx context.Context, cursor string) (*TransactionsSync, error) {
+	for restarts := 0; ; restarts++ {
+		result, err := c.syncPages(ctx, cursor)
+
+		var plaidErr *Error
+		if errors.As(err, &plaidErr) && plaidErr.ErrorCode == ErrorCodeMutationDuringPagination && restarts < maxPaginationRestarts {
+			continue
+		}
+
+		return result, err
+	}
+}
+
+func (c *client) syncPages(ctx context.Context, cursor string) (*TransactionsSync, error) {
+	result := &TransactionsSync{
+		Added:      make([]*Transaction, 0),
+		Modified:   make([]*Transaction, 0),
+		Removed:    make([]*RemovedTransaction, 0),
+		NextCursor: cursor,
+	}
+
+	for n := 1; ; n++ {
+		if n > maxPages {
+			return nil, fmt.Errorf("pagination exceeded maximum number of pages (%d)", maxPages)
+		}
+
+		body := map[string]any{
+			"access_token": c.accessToken,
+			"count":        syncPageSize,
+			"options": map[string]any{
+				"include_personal_finance_category": true,
+				"include_original_description":      true,
+			},
+		}
+
+		if result.NextCursor != "" {
+			body["cursor"] = result.NextCursor
+		}
+
+		page, err := api.ExecuteRequestWithBody[syncPage](ctx, c.api, http.MethodPost, postSyncRoute, body)
+		if err != nil {
+			if n == 1 {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("page %d: %w", n, err)
+		}
+
+		result.Added = append(result.Added, page.Added...)
+		result.Modified = append(result.Modified, page.Modified...)
+		result.Removed = append(result.Removed, page.Removed...)
+		result.NextCursor = page.NextCursor
+
+		if !page.HasMore {
+			return result, nil
+		}
+	}
+}
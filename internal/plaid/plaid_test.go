@@ -0,0 +1,166 @@
+package plaid_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/plaid"
+	"github.com/HallyG/fingrab/internal/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+const accessToken = "access-sandbox-de3ce8ef-33f8-452c-a685-8671031fc0f6"
+
+func setup(t *testing.T, routes ...testhelper.HTTPTestRoute) plaid.Client {
+	t.Helper()
+
+	server := testhelper.NewHTTPTestServer(t, routes)
+	client, err := plaid.New(&http.Client{}, accessToken,
+		api.WithBaseURL(server.URL),
+		plaid.WithCredentials("client-id", "secret"),
+	)
+	require.NoError(t, err)
+
+	return client
+}
+
+// decodeBody decodes the request's JSON body, asserting it's authenticated.
+func decodeBody(t *testing.T, r *http.Request) map[string]any {
+	t.Helper()
+
+	header := http.Header{}
+	header.Add("PLAID-CLIENT-ID", "client-id")
+	header.Add("PLAID-SECRET", "secret")
+	testhelper.AssertRequest(t, r, http.MethodPost, header, nil)
+
+	var body map[string]any
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	require.Equal(t, accessToken, body["access_token"])
+
+	return body
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	client, err := plaid.New(&http.Client{}, "")
+
+	require.Nil(t, client)
+	require.EqualError(t, err, "access token is required")
+}
+
+func TestFetchAccounts(t *testing.T) {
+	t.Parallel()
+
+	client := setup(t, testhelper.HTTPTestRoute{
+		Method: http.MethodPost,
+		URL:    "/accounts/get",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			decodeBody(t, r)
+			testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "accounts.json")(w, r)
+		},
+	})
+
+	accounts, err := client.FetchAccounts(t.Context())
+
+	require.NoError(t, err)
+	require.Equal(t, "Ed6bjNrDLJfGvZWwnkQlfxwoNz54B5C97ejBr", accounts.Item.ItemID)
+	require.Len(t, accounts.Accounts, 2)
+	require.Equal(t, "0000", accounts.Accounts[0].Mask)
+	require.Equal(t, "credit card", accounts.Accounts[1].Subtype)
+	require.Nil(t, accounts.Accounts[1].Balances.Available)
+}
+
+func TestSyncTransactions(t *testing.T) {
+	t.Parallel()
+
+	// pages serves the first page without a cursor, and the second for cursor-1
+	pages := func(t *testing.T, r *http.Request) string {
+		body := decodeBody(t, r)
+		require.InDelta(t, 500, body["count"], 0)
+		require.Equal(t, map[string]any{"include_personal_finance_category": true, "include_original_description": true}, body["options"])
+
+		switch body["cursor"] {
+		case nil:
+			return "sync-page-1.json"
+		case "cursor-1":
+			return "sync-page-2.json"
+		default:
+			t.Fatalf("unexpected cursor %v", body["cursor"])
+			return ""
+		}
+	}
+
+	tests := map[string]struct {
+		handler        func(t *testing.T) http.HandlerFunc
+		expectedErrMsg string
+	}{
+		"follows pages until there are no more": {
+			handler: func(t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, pages(t, r))(w, r)
+				}
+			},
+		},
+		"restarts when transactions change during pagination": {
+			handler: func(t *testing.T) http.HandlerFunc {
+				var requests atomic.Int32
+
+				return func(w http.ResponseWriter, r *http.Request) {
+					page := pages(t, r)
+					if requests.Add(1) == 2 {
+						testhelper.ServeJSONTestDataHandler(t, http.StatusBadRequest, "error-mutation.json")(w, r)
+						return
+					}
+
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, page)(w, r)
+				}
+			},
+		},
+		"returns API error": {
+			handler: func(t *testing.T) http.HandlerFunc {
+				return testhelper.ServeJSONTestDataHandler(t, http.StatusBadRequest, "error.json")
+			},
+			expectedErrMsg: "provided access token is in an invalid format. expected format: access-<environment>-<identifier> (code=INVALID_ACCESS_TOKEN)",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method:  http.MethodPost,
+				URL:     "/transactions/sync",
+				Handler: test.handler(t),
+			})
+
+			result, err := client.SyncTransactions(t.Context(), "")
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, result)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "cursor-2", result.NextCursor)
+			require.Len(t, result.Added, 2)
+			require.Len(t, result.Modified, 1)
+			require.Equal(t, []*plaid.RemovedTransaction{
+				{TransactionID: "no86Eox18VHMvaOVL7gPUM9ap3aR1LsAVZ5nc", AccountID: "BxBXxLj1m4HMXBm9WZZmCWVbPjX16EHwv99vp"},
+			}, result.Removed)
+
+			groceries := result.Added[0]
+			require.InDelta(t, 72.1, groceries.Amount, 0.001)
+			require.Equal(t, "Whole Foods", groceries.MerchantName)
+			require.Equal(t, "FOOD_AND_DRINK_GROCERIES", groceries.PersonalFinanceCategory.Detailed)
+			require.NotNil(t, groceries.Datetime)
+			require.Nil(t, result.Added[1].Datetime)
+			require.True(t, result.Modified[0].Pending)
+		})
+	}
+}
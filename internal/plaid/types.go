@@ -0,0 +1,96 @@
+package plaid
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrorCodeMutationDuringPagination is returned when transactions change while a sync is paginating, which must
+// then be restarted from its first cursor.
+const ErrorCodeMutationDuringPagination = "TRANSACTIONS_SYNC_MUTATION_DURING_PAGINATION"
+
+type Item struct {
+	ItemID        string `json:"item_id"`
+	InstitutionID string `json:"institution_id"`
+}
+
+type Balances struct {
+	Available              *float64 `json:"available"`
+	Current                *float64 `json:"current"`
+	ISOCurrencyCode        string   `json:"iso_currency_code"`
+	UnofficialCurrencyCode string   `json:"unofficial_currency_code"` // e.g. crypto currencies, when there's no ISO code
+}
+
+type Account struct {
+	AccountID    string   `json:"account_id"`
+	Balances     Balances `json:"balances"`
+	Mask         string   `json:"mask"` // The last 2-4 characters of the account number
+	Name         string   `json:"name"`
+	OfficialName string   `json:"official_name"`
+	Type         string   `json:"type"`    // e.g. depository, credit or loan
+	Subtype      string   `json:"subtype"` // e.g. checking, savings or credit card
+}
+
+type Accounts struct {
+	Accounts []*Account `json:"accounts"`
+	Item     Item       `json:"item"`
+}
+
+type PersonalFinanceCategory struct {
+	Primary         string `json:"primary"`  // e.g. FOOD_AND_DRINK
+	Detailed        string `json:"detailed"` // e.g. FOOD_AND_DRINK_GROCERIES
+	ConfidenceLevel string `json:"confidence_level"`
+}
+
+type Transaction struct {
+	TransactionID           string                   `json:"transaction_id"`
+	AccountID               string                   `json:"account_id"`
+	Amount                  float64                  `json:"amount"` // Positive when money leaves the account
+	ISOCurrencyCode         string                   `json:"iso_currency_code"`
+	UnofficialCurrencyCode  string                   `json:"unofficial_currency_code"`
+	Date                    string                   `json:"date"` // YYYY-MM-DD, the posted date, or the authorised date while pending
+	Datetime                *time.Time               `json:"datetime"`
+	AuthorizedDate          string                   `json:"authorized_date"`
+	AuthorizedDatetime      *time.Time               `json:"authorized_datetime"`
+	Name                    string                   `json:"name"`
+	MerchantName            string                   `json:"merchant_name"`
+	OriginalDescription     string                   `json:"original_description"`
+	PaymentChannel          string                   `json:"payment_channel"` // online, in store or other
+	Pending                 bool                     `json:"pending"`
+	PendingTransactionID    string                   `json:"pending_transaction_id"` // The pending transaction this posted transaction replaced
+	PersonalFinanceCategory *PersonalFinanceCategory `json:"personal_finance_category"`
+}
+
+type RemovedTransaction struct {
+	TransactionID string `json:"transaction_id"`
+	AccountID     string `json:"account_id"`
+}
+
+// TransactionsSync is every change to an item's transactions since a cursor.
+type TransactionsSync struct {
+	Added      []*Transaction
+	Modified   []*Transaction
+	Removed    []*RemovedTransaction
+	NextCursor string // Where the next sync continues from
+}
+
+// syncPage is a single page of /transactions/sync.
+type syncPage struct {
+	Added      []*Transaction        `json:"added"`
+	Modified   []*Transaction        `json:"modified"`
+	Removed    []*RemovedTransaction `json:"removed"`
+	NextCursor string                `json:"next_cursor"`
+	HasMore    bool                  `json:"has_more"`
+}
+
+type Error struct {
+	ErrorType      string `json:"error_type"`
+	ErrorCode      string `json:"error_code"`
+	ErrorMessage   string `json:"error_message"`
+	DisplayMessage string `json:"display_message"`
+	RequestID      string `json:"request_id"`
+}
+
+func (err Error) Error() string {
+	return fmt.Sprintf("%s (code=%s)", err.ErrorMessage, err.ErrorCode)
+}
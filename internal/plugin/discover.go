@@ -0,0 +1,129 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ExecutablePrefix is the prefix of plugin executables' names, which is followed by the plugin's name,
+	// e.g. fingrab-exporter-acme.
+	ExecutablePrefix = "fingrab-exporter-"
+	// EnvDir overrides the directory searched for plugins.
+	EnvDir = "FINGRAB_PLUGIN_DIR"
+	// EnvSearchPath, when true, also searches PATH for plugins after the plugin directory.
+	EnvSearchPath = "FINGRAB_PLUGIN_SEARCH_PATH"
+	appName       = "fingrab"
+)
+
+// validName matches names that can be used as a command and, once upper-cased, an environment variable prefix.
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Plugin is a discovered plugin executable.
+type Plugin struct {
+	Name string
+	Path string
+}
+
+// DefaultDir returns the per-user directory searched for plugins (e.g. ~/.config/fingrab/plugins).
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, appName, "plugins"), nil
+}
+
+// Dirs returns the directories searched for plugins, in order of precedence: the plugin directory (EnvDir, or
+// DefaultDir when it's not set) followed by PATH, when EnvSearchPath opts in to searching it.
+func Dirs() []string {
+	var dirs []string
+
+	if dir := os.Getenv(EnvDir); dir != "" {
+		dirs = append(dirs, dir)
+	} else if dir, err := DefaultDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+
+	if searchPath, _ := strconv.ParseBool(os.Getenv(EnvSearchPath)); !searchPath {
+		return dirs
+	}
+
+	return append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+}
+
+// Discover returns the plugin executables in the directories, sorted by name. When several directories contain a
+// plugin of the same name the first is used, as with PATH. Directories which can't be read are skipped.
+func Discover(dirs []string) []*Plugin {
+	plugins := make([]*Plugin, 0)
+	seen := make(map[string]bool)
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok || seen[name] {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+
+			seen[name] = true
+			plugins = append(plugins, &Plugin{
+				Name: name,
+				Path: path,
+			})
+		}
+	}
+
+	slices.SortFunc(plugins, func(a, b *Plugin) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return plugins
+}
+
+func pluginName(filename string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		filename = strings.TrimSuffix(strings.ToLower(filename), ".exe")
+	}
+
+	name, ok := strings.CutPrefix(filename, ExecutablePrefix)
+	if !ok || !validName.MatchString(name) {
+		return "", false
+	}
+
+	return name, true
+}
+
+// isExecutable reports whether path is a file which can be run, following symlinks.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+
+	// Windows has no executable bit, the extension is what matters
+	if runtime.GOOS == "windows" {
+		return strings.EqualFold(filepath.Ext(path), ".exe")
+	}
+
+	return info.Mode().Perm()&0o111 != 0
+}
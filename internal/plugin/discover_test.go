@@ -0,0 +1,70 @@
+package plugin_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/plugin"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, dir string, name string, perm os.FileMode) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), perm))
+
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	first := t.TempDir()
+	second := t.TempDir()
+
+	acme := writeFile(t, first, "fingrab-exporter-acme", 0o755)
+	writeFile(t, first, "fingrab-exporter-readme", 0o644) // Not executable
+	writeFile(t, first, "fingrab-exporter-", 0o755)       // No name
+	writeFile(t, first, "fingrab-exporter-Acme", 0o755)   // Not a valid command name
+	writeFile(t, first, "fingrab-importer-other", 0o755)  // Not a plugin
+	writeFile(t, second, "fingrab-exporter-acme", 0o755)  // Shadowed by the first directory's
+	bank := writeFile(t, second, "fingrab-exporter-bank", 0o700)
+	require.NoError(t, os.Mkdir(filepath.Join(second, "fingrab-exporter-dir"), 0o755))
+
+	plugins := plugin.Discover([]string{"", filepath.Join(first, "missing"), first, second})
+
+	require.Equal(t, []*plugin.Plugin{
+		{Name: "acme", Path: acme},
+		{Name: "bank", Path: bank},
+	}, plugins)
+}
+
+func TestDirs(t *testing.T) {
+	tests := map[string]struct {
+		searchPath   string
+		expectedDirs []string
+	}{
+		"returns the plugin directory": {
+			expectedDirs: []string{"/opt/fingrab/plugins"},
+		},
+		"returns the plugin directory followed by PATH when opted in": {
+			searchPath:   "true",
+			expectedDirs: []string{"/opt/fingrab/plugins", "/usr/local/bin", "/usr/bin"},
+		},
+		"returns the plugin directory when opted out": {
+			searchPath:   "0",
+			expectedDirs: []string{"/opt/fingrab/plugins"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(plugin.EnvDir, "/opt/fingrab/plugins")
+			t.Setenv(plugin.EnvSearchPath, test.searchPath)
+			t.Setenv("PATH", "/usr/local/bin"+string(os.PathListSeparator)+"/usr/bin")
+
+			require.Equal(t, test.expectedDirs, plugin.Dirs())
+		})
+	}
+}
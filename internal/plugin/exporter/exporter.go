@@ -0,0 +1,131 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/plugin"
+)
+
+var _ export.Exporter = (*TransactionExporter)(nil)
+
+// TransactionExporter exports from an out-of-process plugin, so banks can be added without changing fingrab.
+type TransactionExporter struct {
+	api          plugin.Client
+	exportType   export.ExportType
+	bankName     string
+	maxDateRange time.Duration
+	capabilities export.Capabilities
+}
+
+// New describes the plugin, failing when it speaks a different version of the protocol. The export type is the
+// plugin's name, which is also the bank name given to its transactions unless it describes itself with another.
+func New(ctx context.Context, exportType export.ExportType, api plugin.Client) (*TransactionExporter, error) {
+	if api == nil {
+		return nil, errors.New("plugin client is required")
+	}
+
+	description, err := api.Describe(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("describe: %w", err)
+	}
+
+	bankName := description.Name
+	if bankName == "" {
+		bankName = string(exportType)
+	}
+
+	return &TransactionExporter{
+		api:          api,
+		exportType:   exportType,
+		bankName:     bankName,
+		maxDateRange: time.Duration(description.MaxDateRangeDays) * 24 * time.Hour,
+		capabilities: capabilities(description.Capabilities),
+	}, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return e.exportType
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return e.maxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return e.capabilities
+}
+
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	accounts, err := e.api.FetchAccounts(ctx, plugin.Params{
+		AuthToken: opts.AuthToken,
+		TimeoutMS: opts.Timeout.Milliseconds(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// ExportTransactions returns the transactions the plugin streams for the selected account. The plugin is trusted to
+// select the account, but transactions outside the dates, or declined ones when not auditing, are dropped so every
+// plugin behaves alike.
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	params := plugin.Params{
+		AuthToken: opts.AuthToken,
+		TimeoutMS: opts.Timeout.Milliseconds(),
+		AccountID: opts.AccountID,
+		Space:     opts.Space,
+		StartDate: &opts.StartDate,
+		EndDate:   &opts.EndDate,
+		Audit:     opts.Audit,
+	}
+
+	transactions := make([]*domain.Transaction, 0)
+	err := e.api.StreamTransactions(ctx, params, func(txn *plugin.Transaction) error {
+		transaction, err := txn.ToTransaction(e.bankName)
+		if err != nil {
+			return err
+		}
+
+		if transaction.CreatedAt.Before(opts.StartDate) || !transaction.CreatedAt.Before(opts.EndDate) {
+			return nil
+		}
+
+		if !opts.Audit && !isBooked(transaction.Status) {
+			return nil
+		}
+
+		transactions = append(transactions, transaction)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("stream transactions: %w", err)
+	}
+
+	slices.SortStableFunc(transactions, func(a, b *domain.Transaction) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	log.FromContext(ctx).InfoContext(ctx, "fetched transactions",
+		slog.Int("transaction.count", len(transactions)),
+	)
+
+	return transactions, nil
+}
+
+func isBooked(status domain.TransactionStatus) bool {
+	return status == domain.TransactionStatusSettled || status == domain.TransactionStatusPending
+}
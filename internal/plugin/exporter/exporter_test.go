@@ -0,0 +1,234 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/plugin"
+	pluginexporter "github.com/HallyG/fingrab/internal/plugin/exporter"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+const exportType = export.ExportType("acme")
+
+type StubClient struct {
+	Description    *plugin.Description
+	DescribeErr    error
+	Accounts       []*domain.Account
+	Transactions   []*plugin.Transaction
+	TransactionErr error
+
+	RequestedParams []plugin.Params
+}
+
+var _ plugin.Client = (*StubClient)(nil)
+
+func (c *StubClient) Describe(ctx context.Context) (*plugin.Description, error) {
+	if c.DescribeErr != nil {
+		return nil, c.DescribeErr
+	}
+
+	return c.Description, nil
+}
+
+func (c *StubClient) FetchAccounts(ctx context.Context, params plugin.Params) ([]*domain.Account, error) {
+	c.RequestedParams = append(c.RequestedParams, params)
+	return c.Accounts, nil
+}
+
+func (c *StubClient) StreamTransactions(ctx context.Context, params plugin.Params, fn func(*plugin.Transaction) error) error {
+	c.RequestedParams = append(c.RequestedParams, params)
+
+	for _, txn := range c.Transactions {
+		if err := fn(txn); err != nil {
+			return err
+		}
+	}
+
+	return c.TransactionErr
+}
+
+func newStubClient() *StubClient {
+	return &StubClient{
+		Description: &plugin.Description{
+			ProtocolVersion:  plugin.ProtocolVersion,
+			Name:             "Acme Bank",
+			MaxDateRangeDays: 90,
+		},
+		Accounts: []*domain.Account{
+			{ID: "acc-1", Name: "Current", Type: "current", Currency: "GBP"},
+		},
+		Transactions: []*plugin.Transaction{
+			{ID: "coffee", Amount: domain.Money{MinorUnit: -1250, Currency: "GBP"}, Reference: "Coffee Shop", CreatedAt: time.Date(2025, time.March, 2, 9, 30, 0, 0, time.UTC)},
+			{ID: "salary", Amount: domain.Money{MinorUnit: 250000, Currency: "GBP"}, Reference: "Salary", CreatedAt: time.Date(2025, time.March, 1, 8, 0, 0, 0, time.UTC)},
+			{ID: "declined", Amount: domain.Money{MinorUnit: -9999, Currency: "GBP"}, Reference: "Electronics", CreatedAt: time.Date(2025, time.March, 3, 12, 0, 0, 0, time.UTC), Status: domain.TransactionStatusDeclined, DeclineReason: "insufficient funds"},
+			{ID: "too-late", Amount: domain.Money{MinorUnit: -500, Currency: "GBP"}, Reference: "Bakery", CreatedAt: time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		client               func() plugin.Client
+		expectedMaxDateRange time.Duration
+		expectedCapabilities []string
+		expectedErrMsg       string
+	}{
+		"uses the plugin's max date range": {
+			client: func() plugin.Client {
+				return newStubClient()
+			},
+			expectedMaxDateRange: 90 * 24 * time.Hour,
+			expectedCapabilities: []string{"accounts", "transactions"},
+		},
+		"uses the plugin's capabilities": {
+			client: func() plugin.Client {
+				client := newStubClient()
+				client.Description.Capabilities = &plugin.Capabilities{Transactions: true, Declines: true}
+				return client
+			},
+			expectedMaxDateRange: 90 * 24 * time.Hour,
+			expectedCapabilities: []string{"transactions", "declines"},
+		},
+		"returns error when describe fails": {
+			client: func() plugin.Client {
+				client := newStubClient()
+				client.DescribeErr = errors.New("unsupported protocol version 2, expected 1")
+				return client
+			},
+			expectedErrMsg: "describe: unsupported protocol version 2, expected 1",
+		},
+		"returns error when client is nil": {
+			client: func() plugin.Client {
+				return nil
+			},
+			expectedErrMsg: "plugin client is required",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter, err := pluginexporter.New(t.Context(), exportType, test.client())
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, exporter)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, exportType, exporter.Type())
+			require.Equal(t, test.expectedMaxDateRange, exporter.MaxDateRange())
+			require.Equal(t, test.expectedCapabilities, exporter.Capabilities().Names())
+		})
+	}
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	client := newStubClient()
+	exporter, err := pluginexporter.New(t.Context(), exportType, client)
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{
+		Options: export.Options{AuthToken: "token", Timeout: 5 * time.Second},
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, client.Accounts, accounts)
+	require.Equal(t, []plugin.Params{{AuthToken: "token", TimeoutMS: 5000}}, client.RequestedParams)
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		client         func() *StubClient
+		bankName       string
+		audit          bool
+		expectedIDs    []string
+		expectedErrMsg string
+	}{
+		"returns booked transactions between the dates": {
+			client:      newStubClient,
+			bankName:    "Acme Bank",
+			expectedIDs: []string{"salary", "coffee"},
+		},
+		"includes declined transactions when auditing": {
+			client:      newStubClient,
+			bankName:    "Acme Bank",
+			audit:       true,
+			expectedIDs: []string{"salary", "coffee", "declined"},
+		},
+		"uses the plugin name when it doesn't describe its own": {
+			client: func() *StubClient {
+				client := newStubClient()
+				client.Description.Name = ""
+				return client
+			},
+			bankName:    "acme",
+			expectedIDs: []string{"salary", "coffee"},
+		},
+		"returns error when transaction is invalid": {
+			client: func() *StubClient {
+				client := newStubClient()
+				client.Transactions[1].Status = "bounced"
+				return client
+			},
+			expectedErrMsg: `stream transactions: transaction "salary": unknown status "bounced"`,
+		},
+		"returns reported error": {
+			client: func() *StubClient {
+				client := newStubClient()
+				client.TransactionErr = &plugin.Error{Code: "unauthorized", Message: "token has expired"}
+				return client
+			},
+			expectedErrMsg: "stream transactions: token has expired (code=unauthorized)",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := test.client()
+			exporter, err := pluginexporter.New(t.Context(), exportType, client)
+			require.NoError(t, err)
+
+			transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				AccountID: "acc-1",
+				StartDate: start,
+				EndDate:   end,
+				Audit:     test.audit,
+				Options:   export.Options{AuthToken: "token"},
+			})
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedIDs, lo.Map(transactions, func(txn *domain.Transaction, _ int) string { return txn.ID }))
+			for _, transaction := range transactions {
+				require.Equal(t, test.bankName, transaction.BankName)
+			}
+
+			require.Equal(t, []plugin.Params{{AuthToken: "token", AccountID: "acc-1", StartDate: &start, EndDate: &end, Audit: test.audit}}, client.RequestedParams)
+		})
+	}
+}
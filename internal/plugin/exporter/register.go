@@ -0,0 +1,81 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/plugin"
+)
+
+// Register describes the plugin and registers it as an exporter with the name and capabilities it describes, which
+// gives it a command like any other exporter. A plugin which fails to describe itself is still registered, with the
+// accounts and transactions every plugin answers, so the failure is reported when one of its commands is run.
+func Register(ctx context.Context, p *plugin.Plugin) {
+	exportType := export.ExportType(p.Name)
+	path := p.Path
+	help := fmt.Sprintf(`Commands for the %s exporter plugin at %s. The token is passed to the plugin as is.`, p.Name, p.Path)
+
+	var described *plugin.Capabilities
+	opts := make([]export.RegisterOption, 0)
+	if description, err := describe(ctx, path); err == nil {
+		described = description.Capabilities
+
+		if description.Name != "" {
+			opts = append(opts, export.WithDisplayName(description.Name))
+		}
+
+		if description.Description != "" {
+			help = description.Description + "\n\n" + help
+		}
+	}
+
+	opts = append(opts, export.WithDescription(help), export.WithCapabilities(capabilities(described)))
+
+	export.Register(exportType, func(opts export.Options) (export.Exporter, error) {
+		return newFromOptions(exportType, path, opts)
+	}, opts...)
+}
+
+func describe(ctx context.Context, path string) (*plugin.Description, error) {
+	client, err := plugin.New(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.Describe(ctx)
+}
+
+// capabilities returns what the plugin describes itself as supporting, defaulting to accounts and transactions.
+func capabilities(described *plugin.Capabilities) export.Capabilities {
+	if described == nil {
+		return export.Capabilities{
+			Accounts:     true,
+			Transactions: true,
+		}
+	}
+
+	return export.Capabilities{
+		Accounts:     described.Accounts,
+		Transactions: described.Transactions,
+		Spaces:       described.Spaces,
+		Pending:      described.Pending,
+		Declines:     described.Declines,
+	}
+}
+
+func newFromOptions(exportType export.ExportType, path string, opts export.Options) (*TransactionExporter, error) {
+	client, err := plugin.New(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	return New(ctx, exportType, client)
+}
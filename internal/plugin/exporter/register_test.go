@@ -0,0 +1,49 @@
+package exporter_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/plugin"
+	pluginexporter "github.com/HallyG/fingrab/internal/plugin/exporter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		plugin               string
+		expectedDisplayName  string
+		expectedCapabilities []string
+	}{
+		"registers the described name and capabilities": {
+			plugin:               "acme",
+			expectedDisplayName:  "Acme Bank",
+			expectedCapabilities: []string{"accounts", "transactions", "pending"},
+		},
+		"registers accounts and transactions when the plugin fails to describe itself": {
+			plugin:               "future",
+			expectedDisplayName:  "register-future",
+			expectedCapabilities: []string{"accounts", "transactions"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			exportType := export.ExportType("register-" + test.plugin)
+			pluginexporter.Register(t.Context(), &plugin.Plugin{
+				Name: string(exportType),
+				Path: filepath.Join("..", "testdata", plugin.ExecutablePrefix+test.plugin),
+			})
+
+			metadata, registered := export.Lookup(exportType)
+
+			require.True(t, registered)
+			require.Equal(t, test.expectedDisplayName, metadata.DisplayName)
+			require.Equal(t, test.expectedCapabilities, metadata.Capabilities.Names())
+		})
+	}
+}
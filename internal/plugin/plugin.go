@@ -0,0 +1,202 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+)
+
+// maxMessageSize is the longest line a plugin can write, which bounds the size of a single account or transaction.
+const maxMessageSize = 1024 * 1024
+
+type Client interface {
+	Describe(ctx context.Context) (*Description, error)
+	FetchAccounts(ctx context.Context, params Params) ([]*domain.Account, error)
+	// StreamTransactions calls fn with each transaction as the plugin writes it, stopping at the first error.
+	StreamTransactions(ctx context.Context, params Params, fn func(*Transaction) error) error
+}
+
+var _ Client = (*client)(nil)
+
+type client struct {
+	path   string
+	stderr io.Writer
+}
+
+type Option func(*client)
+
+// WithStderr configures where the plugin's stderr is written, which defaults to fingrab's own.
+func WithStderr(w io.Writer) Option {
+	return func(c *client) {
+		c.stderr = w
+	}
+}
+
+// New returns a client of the plugin executable at path. The plugin is run once per request.
+func New(path string, opts ...Option) (*client, error) {
+	if path == "" {
+		return nil, errors.New("plugin path is required")
+	}
+
+	c := &client{
+		path:   path,
+		stderr: os.Stderr,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Describe asks the plugin to describe itself, failing when it speaks a different version of the protocol.
+func (c *client) Describe(ctx context.Context) (*Description, error) {
+	var description *Description
+	err := c.call(ctx, MethodDescribe, Params{}, func(message *Message) error {
+		if message.Type != MessageTypeDescribe || message.Description == nil || description != nil {
+			return fmt.Errorf("unexpected %s message", message.Type)
+		}
+
+		description = message.Description
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if description == nil {
+		return nil, errors.New("plugin did not describe itself")
+	}
+
+	if description.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("unsupported protocol version %d, expected %d", description.ProtocolVersion, ProtocolVersion)
+	}
+
+	return description, nil
+}
+
+func (c *client) FetchAccounts(ctx context.Context, params Params) ([]*domain.Account, error) {
+	accounts := make([]*domain.Account, 0)
+	err := c.call(ctx, MethodAccounts, params, func(message *Message) error {
+		if message.Type != MessageTypeAccount || message.Account == nil {
+			return fmt.Errorf("unexpected %s message", message.Type)
+		}
+
+		accounts = append(accounts, message.Account)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+func (c *client) StreamTransactions(ctx context.Context, params Params, fn func(*Transaction) error) error {
+	return c.call(ctx, MethodTransactions, params, func(message *Message) error {
+		if message.Type != MessageTypeTransaction || message.Transaction == nil {
+			return fmt.Errorf("unexpected %s message", message.Type)
+		}
+
+		return fn(message.Transaction)
+	})
+}
+
+// call runs the plugin with the request, passing each message of its reply to handle until the end message.
+// A reported error is returned as an *Error.
+func (c *client) call(ctx context.Context, method Method, params Params, handle func(*Message) error) error {
+	request, err := json.Marshal(Request{
+		Version: ProtocolVersion,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, c.path)
+	cmd.Stdin = bytes.NewReader(append(request, '\n'))
+	cmd.Stderr = c.stderr
+	cmd.WaitDelay = time.Second
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("stdout: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start plugin: %w", err)
+	}
+
+	ended, err := readReply(stdout, handle)
+	if err != nil {
+		// The rest of the reply isn't wanted, so don't wait for the plugin to finish writing it
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+
+		return err
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("plugin exited: %w", err)
+	}
+
+	if !ended {
+		return errors.New("plugin exited without ending its reply")
+	}
+
+	return nil
+}
+
+// readReply reads messages until the end message, reporting whether it was reached.
+func readReply(r io.Reader, handle func(*Message) error) (bool, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageSize)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		message, err := decodeMessage(line)
+		if err != nil {
+			return false, err
+		}
+
+		switch message.Type {
+		case MessageTypeEnd:
+			return true, nil
+		case MessageTypeError:
+			if message.Error == nil || message.Error.Message == "" {
+				return false, &Error{Message: "plugin reported an error without a message"}
+			}
+
+			return false, message.Error
+		}
+
+		if err := handle(message); err != nil {
+			return false, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("read reply: %w", err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,288 @@
+package plugin_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/plugin"
+	"github.com/stretchr/testify/require"
+)
+
+func setup(t *testing.T, name string) (plugin.Client, *bytes.Buffer) {
+	t.Helper()
+
+	var stderr bytes.Buffer
+	client, err := plugin.New(filepath.Join("testdata", plugin.ExecutablePrefix+name), plugin.WithStderr(&stderr))
+	require.NoError(t, err)
+
+	return client, &stderr
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	client, err := plugin.New("")
+
+	require.Nil(t, client)
+	require.EqualError(t, err, "plugin path is required")
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		plugin              string
+		expectedDescription *plugin.Description
+		expectedErrMsg      string
+	}{
+		"returns description": {
+			plugin: "acme",
+			expectedDescription: &plugin.Description{
+				ProtocolVersion:  plugin.ProtocolVersion,
+				Name:             "Acme Bank",
+				Description:      "Acme Bank current accounts",
+				MaxDateRangeDays: 90,
+				Capabilities:     &plugin.Capabilities{Accounts: true, Transactions: true, Pending: true},
+			},
+		},
+		"returns error when protocol version is unsupported": {
+			plugin:         "future",
+			expectedErrMsg: "unsupported protocol version 2, expected 1",
+		},
+		"returns error when reply isn't a message": {
+			plugin:         "garbled",
+			expectedErrMsg: "decode message: invalid character 'U' looking for beginning of value",
+		},
+		"returns error when reply is of another method": {
+			plugin:         "crash",
+			expectedErrMsg: "unexpected account message",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, _ := setup(t, test.plugin)
+
+			description, err := client.Describe(t.Context())
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, description)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedDescription, description)
+		})
+	}
+}
+
+func TestFetchAccounts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		plugin           string
+		expectedAccounts []*domain.Account
+		expectedErrMsg   string
+		expectedStderr   string
+	}{
+		"returns accounts": {
+			plugin: "acme",
+			expectedAccounts: []*domain.Account{
+				{ID: "acc-1", Name: "Current", Type: "current", Currency: "GBP", CreatedAt: time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)},
+				{ID: "acc-2", Name: "Savings", Type: "savings", Currency: "GBP", Closed: true, CreatedAt: time.Date(2021, time.May, 6, 0, 0, 0, 0, time.UTC)},
+			},
+			expectedStderr: `{"version":1,"method":"accounts","params":{"authToken":"token","timeoutMs":5000}}` + "\n",
+		},
+		"returns reported error": {
+			plugin:         "unauthorized",
+			expectedErrMsg: "token has expired (code=unauthorized)",
+		},
+		"returns error when plugin crashes": {
+			plugin:         "crash",
+			expectedErrMsg: "plugin exited: exit status 2",
+			expectedStderr: "panic: something went wrong\n",
+		},
+		"returns error when reply isn't ended": {
+			plugin:         "truncated",
+			expectedErrMsg: "plugin exited without ending its reply",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, stderr := setup(t, test.plugin)
+
+			accounts, err := client.FetchAccounts(t.Context(), plugin.Params{
+				AuthToken: "token",
+				TimeoutMS: 5000,
+			})
+
+			require.Equal(t, test.expectedStderr, stderr.String())
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, accounts)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedAccounts, accounts)
+		})
+	}
+}
+
+func TestFetchAccountsReturnsTypedError(t *testing.T) {
+	t.Parallel()
+
+	client, _ := setup(t, "unauthorized")
+
+	_, err := client.FetchAccounts(t.Context(), plugin.Params{})
+
+	var pluginErr *plugin.Error
+	require.ErrorAs(t, err, &pluginErr)
+	require.Equal(t, "unauthorized", pluginErr.Code)
+}
+
+func TestStreamTransactions(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	params := plugin.Params{
+		AuthToken: "token",
+		AccountID: "acc-1",
+		StartDate: &start,
+		EndDate:   &end,
+		Audit:     true,
+	}
+
+	t.Run("calls fn with each transaction", func(t *testing.T) {
+		t.Parallel()
+
+		client, stderr := setup(t, "acme")
+
+		var ids []string
+		err := client.StreamTransactions(t.Context(), params, func(txn *plugin.Transaction) error {
+			ids = append(ids, txn.ID)
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []string{"txn-1", "txn-2"}, ids)
+		require.Equal(t, `{"version":1,"method":"transactions","params":{"authToken":"token","accountId":"acc-1","start":"2025-03-01T00:00:00Z","end":"2025-04-01T00:00:00Z","audit":true}}`+"\n", stderr.String())
+	})
+
+	t.Run("stops at fn's first error", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := setup(t, "acme")
+
+		calls := 0
+		err := client.StreamTransactions(t.Context(), params, func(txn *plugin.Transaction) error {
+			calls++
+			return errors.New("stop")
+		})
+
+		require.EqualError(t, err, "stop")
+		require.Equal(t, 1, calls)
+	})
+}
+
+func TestToTransaction(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2025, time.March, 2, 9, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		transaction         *plugin.Transaction
+		expectedTransaction *domain.Transaction
+		expectedErrMsg      string
+	}{
+		"defaults status to settled": {
+			transaction: &plugin.Transaction{
+				ID:        "txn-1",
+				Amount:    domain.Money{MinorUnit: -1250, Currency: "GBP"},
+				Reference: "Coffee Shop",
+				CreatedAt: createdAt,
+			},
+			expectedTransaction: &domain.Transaction{
+				ID:        "txn-1",
+				Amount:    domain.Money{MinorUnit: -1250, Currency: "GBP"},
+				Reference: "Coffee Shop",
+				CreatedAt: createdAt,
+				BankName:  "Acme Bank",
+				Status:    domain.TransactionStatusSettled,
+			},
+		},
+		"converts original amount and splits": {
+			transaction: &plugin.Transaction{
+				ID:             "txn-2",
+				Amount:         domain.Money{MinorUnit: 5000, Currency: "GBP"},
+				OriginalAmount: &domain.Money{MinorUnit: 5800, Currency: "EUR"},
+				CreatedAt:      createdAt,
+				Splits:         []plugin.Split{{Category: "income", Amount: domain.Money{MinorUnit: 5000, Currency: "GBP"}}},
+				Status:         domain.TransactionStatusPending,
+			},
+			expectedTransaction: &domain.Transaction{
+				ID:             "txn-2",
+				Amount:         domain.Money{MinorUnit: 5000, Currency: "GBP"},
+				OriginalAmount: domain.Money{MinorUnit: 5800, Currency: "EUR"},
+				CreatedAt:      createdAt,
+				IsDeposit:      true,
+				BankName:       "Acme Bank",
+				Splits:         []domain.Split{{Category: "income", Amount: domain.Money{MinorUnit: 5000, Currency: "GBP"}}},
+				Status:         domain.TransactionStatusPending,
+			},
+		},
+		"returns error when status is unknown": {
+			transaction: &plugin.Transaction{
+				ID:        "txn-3",
+				Amount:    domain.Money{MinorUnit: 100, Currency: "GBP"},
+				CreatedAt: createdAt,
+				Status:    "bounced",
+			},
+			expectedErrMsg: `transaction "txn-3": unknown status "bounced"`,
+		},
+		"returns error when currency is missing": {
+			transaction: &plugin.Transaction{
+				ID:        "txn-4",
+				Amount:    domain.Money{MinorUnit: 100},
+				CreatedAt: createdAt,
+			},
+			expectedErrMsg: `transaction "txn-4": amount currency is required`,
+		},
+		"returns error when created at is missing": {
+			transaction: &plugin.Transaction{
+				ID:     "txn-5",
+				Amount: domain.Money{MinorUnit: 100, Currency: "GBP"},
+			},
+			expectedErrMsg: `transaction "txn-5": createdAt is required`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			transaction, err := test.transaction.ToTransaction("Acme Bank")
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, transaction)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransaction, transaction)
+		})
+	}
+}
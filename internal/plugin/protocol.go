@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+)
+
+// ProtocolVersion is the version of the protocol spoken to plugins. It's sent with every request, and plugins must
+// describe themselves with the same version.
+//
+// fingrab runs a plugin once per request, writing the request to its stdin as a single JSON object. The plugin
+// replies on stdout with one JSON message per line, ending with an end (or error) message. Anything the plugin
+// writes to stderr is passed through, so it can be used for logging.
+const ProtocolVersion = 1
+
+// Method is the operation requested of a plugin.
+type Method string
+
+const (
+	MethodDescribe     Method = "describe"     // Replied to with a single describe message
+	MethodAccounts     Method = "accounts"     // Replied to with an account message per account
+	MethodTransactions Method = "transactions" // Replied to with a transaction message per transaction
+)
+
+// MessageType is the type of a message written by a plugin.
+type MessageType string
+
+const (
+	MessageTypeDescribe    MessageType = "describe"
+	MessageTypeAccount     MessageType = "account"
+	MessageTypeTransaction MessageType = "transaction"
+	MessageTypeError       MessageType = "error"
+	MessageTypeEnd         MessageType = "end" // Marks a complete reply, so a plugin which crashes part way isn't mistaken for one with no more data
+)
+
+// Request is written to a plugin's stdin.
+type Request struct {
+	Version int    `json:"version"`
+	Method  Method `json:"method"`
+	Params  Params `json:"params"`
+}
+
+// Params are the options of a request. Only the auth token and timeout are sent to describe and accounts requests.
+type Params struct {
+	AuthToken string     `json:"authToken,omitempty"`
+	TimeoutMS int64      `json:"timeoutMs,omitempty"` // Timeout of each request the plugin makes to its bank
+	AccountID string     `json:"accountId,omitempty"` // Account selector, empty for the plugin's default account, or "all"
+	Space     string     `json:"space,omitempty"`
+	StartDate *time.Time `json:"start,omitempty"` // Inclusive
+	EndDate   *time.Time `json:"end,omitempty"`   // Exclusive
+	Audit     bool       `json:"audit,omitempty"` // Include declined and reversed transactions
+}
+
+// Message is a line written to a plugin's stdout. Only the field named by its type is set.
+type Message struct {
+	Type        MessageType     `json:"type"`
+	Description *Description    `json:"description,omitempty"`
+	Account     *domain.Account `json:"account,omitempty"`
+	Transaction *Transaction    `json:"transaction,omitempty"`
+	Error       *Error          `json:"error,omitempty"`
+}
+
+// Description is a plugin's reply to a describe request.
+type Description struct {
+	ProtocolVersion  int           `json:"protocolVersion"`
+	Name             string        `json:"name,omitempty"` // Display name, also given to exported transactions as their bank name
+	Description      string        `json:"description,omitempty"`
+	MaxDateRangeDays int           `json:"maxDateRangeDays,omitempty"` // Zero when there's no limit
+	Capabilities     *Capabilities `json:"capabilities,omitempty"`     // Defaults to accounts and transactions
+}
+
+// Capabilities are what a plugin supports, which decides the commands and options it's offered with.
+type Capabilities struct {
+	Accounts     bool `json:"accounts"`     // Answers accounts requests
+	Transactions bool `json:"transactions"` // Answers transactions requests
+	Spaces       bool `json:"spaces"`       // Exports the transactions of a space (Params.Space)
+	Pending      bool `json:"pending"`      // Exports transactions which haven't settled yet
+	Declines     bool `json:"declines"`     // Exports declined transactions when auditing
+}
+
+// Transaction is a transaction written by a plugin. Amounts are negative when money leaves the account.
+type Transaction struct {
+	ID             string                   `json:"id,omitempty"`
+	Amount         domain.Money             `json:"amount"`
+	OriginalAmount *domain.Money            `json:"originalAmount,omitempty"`
+	Reference      string                   `json:"reference"`
+	Category       string                   `json:"category,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	Account        string                   `json:"account,omitempty"`
+	Notes          string                   `json:"notes,omitempty"`
+	Splits         []Split                  `json:"splits,omitempty"`
+	Status         domain.TransactionStatus `json:"status,omitempty"` // Defaults to settled
+	DeclineReason  string                   `json:"declineReason,omitempty"`
+}
+
+type Split struct {
+	Category string       `json:"category"`
+	Amount   domain.Money `json:"amount"`
+}
+
+// Error is a failure reported by a plugin.
+type Error struct {
+	Code    string `json:"code,omitempty"` // e.g. unauthorized or rate_limited
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+
+	return fmt.Sprintf("%s (code=%s)", e.Message, e.Code)
+}
+
+// ToTransaction converts the plugin's transaction, setting the bank name it was exported from.
+func (t *Transaction) ToTransaction(bankName string) (*domain.Transaction, error) {
+	if t.CreatedAt.IsZero() {
+		return nil, fmt.Errorf("transaction %q: createdAt is required", t.ID)
+	}
+
+	if t.Amount.Currency == "" {
+		return nil, fmt.Errorf("transaction %q: amount currency is required", t.ID)
+	}
+
+	status := t.Status
+	switch status {
+	case "":
+		status = domain.TransactionStatusSettled
+	case domain.TransactionStatusSettled, domain.TransactionStatusPending, domain.TransactionStatusDeclined,
+		domain.TransactionStatusReversed, domain.TransactionStatusCardCheck:
+	default:
+		return nil, fmt.Errorf("transaction %q: unknown status %q", t.ID, status)
+	}
+
+	transaction := &domain.Transaction{
+		ID:            t.ID,
+		Amount:        t.Amount,
+		Reference:     t.Reference,
+		Category:      t.Category,
+		CreatedAt:     t.CreatedAt,
+		IsDeposit:     t.Amount.MinorUnit > 0,
+		BankName:      bankName,
+		Account:       t.Account,
+		Notes:         t.Notes,
+		Status:        status,
+		DeclineReason: t.DeclineReason,
+	}
+
+	if t.OriginalAmount != nil {
+		transaction.OriginalAmount = *t.OriginalAmount
+	}
+
+	for _, split := range t.Splits {
+		transaction.Splits = append(transaction.Splits, domain.Split{
+			Category: split.Category,
+			Amount:   split.Amount,
+		})
+	}
+
+	return transaction, nil
+}
+
+func decodeMessage(line []byte) (*Message, error) {
+	var message Message
+	if err := json.Unmarshal(line, &message); err != nil {
+		return nil, fmt.Errorf("decode message: %w", err)
+	}
+
+	return &message, nil
+}
@@ -0,0 +1,81 @@
+// Package report summarises exported transactions.
+package report
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+)
+
+const unknownDeclineReason = "UNKNOWN"
+
+// DeclineSummary aggregates the declined transactions for a merchant with the same decline reason.
+type DeclineSummary struct {
+	Merchant  string       `json:"merchant"`
+	Reason    string       `json:"reason"`
+	Count     int          `json:"count"`
+	Total     domain.Money `json:"total"`
+	FirstSeen time.Time    `json:"firstSeen"`
+	LastSeen  time.Time    `json:"lastSeen"`
+}
+
+// Declines summarises declined transactions by merchant (the transaction's reference), decline reason and currency.
+// Transactions that weren't declined are ignored. Summaries are ordered by count, most frequent first, then by merchant.
+func Declines(transactions []*domain.Transaction) []*DeclineSummary {
+	type key struct {
+		merchant string
+		reason   string
+		currency string
+	}
+
+	summaries := make([]*DeclineSummary, 0)
+	index := make(map[key]*DeclineSummary)
+
+	for _, txn := range transactions {
+		if txn.Status != domain.TransactionStatusDeclined {
+			continue
+		}
+
+		reason := txn.DeclineReason
+		if reason == "" {
+			reason = unknownDeclineReason
+		}
+
+		k := key{merchant: txn.Reference, reason: reason, currency: txn.Amount.Currency}
+		summary, ok := index[k]
+		if !ok {
+			summary = &DeclineSummary{
+				Merchant:  txn.Reference,
+				Reason:    reason,
+				Total:     domain.Money{Currency: txn.Amount.Currency},
+				FirstSeen: txn.CreatedAt,
+				LastSeen:  txn.CreatedAt,
+			}
+			index[k] = summary
+			summaries = append(summaries, summary)
+		}
+
+		summary.Count++
+		summary.Total.MinorUnit += txn.Amount.MinorUnit
+
+		if txn.CreatedAt.Before(summary.FirstSeen) {
+			summary.FirstSeen = txn.CreatedAt
+		}
+
+		if txn.CreatedAt.After(summary.LastSeen) {
+			summary.LastSeen = txn.CreatedAt
+		}
+	}
+
+	slices.SortStableFunc(summaries, func(a, b *DeclineSummary) int {
+		return cmp.Or(
+			cmp.Compare(b.Count, a.Count),
+			cmp.Compare(a.Merchant, b.Merchant),
+			cmp.Compare(a.Reason, b.Reason),
+		)
+	})
+
+	return summaries
+}
@@ -0,0 +1,50 @@
+package report_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/report"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeclines(t *testing.T) {
+	t.Parallel()
+
+	day1 := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	tests := map[string]struct {
+		transactions []*domain.Transaction
+		expected     []*report.DeclineSummary
+	}{
+		"returns empty when nothing declined": {
+			transactions: []*domain.Transaction{
+				{Reference: "Tesco", Status: domain.TransactionStatusSettled},
+			},
+			expected: []*report.DeclineSummary{},
+		},
+		"groups by merchant and reason, most frequent first": {
+			transactions: []*domain.Transaction{
+				{Reference: "Netflix", Status: domain.TransactionStatusDeclined, DeclineReason: "INSUFFICIENT_FUNDS", CreatedAt: day2, Amount: domain.Money{MinorUnit: -1099, Currency: "GBP"}},
+				{Reference: "Amazon", Status: domain.TransactionStatusDeclined, DeclineReason: "CARD_BLOCKED", CreatedAt: day1, Amount: domain.Money{MinorUnit: -500, Currency: "GBP"}},
+				{Reference: "Netflix", Status: domain.TransactionStatusDeclined, DeclineReason: "INSUFFICIENT_FUNDS", CreatedAt: day1, Amount: domain.Money{MinorUnit: -1099, Currency: "GBP"}},
+				{Reference: "Netflix", Status: domain.TransactionStatusSettled, CreatedAt: day2, Amount: domain.Money{MinorUnit: -1099, Currency: "GBP"}},
+				{Reference: "Spotify", Status: domain.TransactionStatusDeclined, CreatedAt: day1, Amount: domain.Money{MinorUnit: -999, Currency: "GBP"}},
+			},
+			expected: []*report.DeclineSummary{
+				{Merchant: "Netflix", Reason: "INSUFFICIENT_FUNDS", Count: 2, Total: domain.Money{MinorUnit: -2198, Currency: "GBP"}, FirstSeen: day1, LastSeen: day2},
+				{Merchant: "Amazon", Reason: "CARD_BLOCKED", Count: 1, Total: domain.Money{MinorUnit: -500, Currency: "GBP"}, FirstSeen: day1, LastSeen: day1},
+				{Merchant: "Spotify", Reason: "UNKNOWN", Count: 1, Total: domain.Money{MinorUnit: -999, Currency: "GBP"}, FirstSeen: day1, LastSeen: day1},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, test.expected, report.Declines(test.transactions))
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package exporter
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+	"github.com/google/uuid"
+)
+
+const (
+	annotationColumnFeedItemID = "feedItemUid"
+	annotationColumnUserNote   = "userNote"
+	annotationColumnCategory   = "spendingCategory"
+)
+
+// Annotation is the desired user note and spending category of a feed item. Empty values are left unchanged.
+type Annotation struct {
+	FeedItemID starling.FeedItemID
+	UserNote   string
+	Category   string // Starling spending category, e.g. GROCERIES
+}
+
+// AnnotationChange is a single field of a feed item that differs from its annotation.
+type AnnotationChange struct {
+	FeedItemID starling.FeedItemID
+	Field      string // userNote or spendingCategory
+	Old        string
+	New        string
+	Applied    bool // False for a dry run
+}
+
+// ReadAnnotations reads annotations from a CSV with a feedItemUid column and userNote and/or spendingCategory columns.
+func ReadAnnotations(r io.Reader) ([]*Annotation, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	idIndex := slices.Index(header, annotationColumnFeedItemID)
+	noteIndex := slices.Index(header, annotationColumnUserNote)
+	categoryIndex := slices.Index(header, annotationColumnCategory)
+
+	if idIndex == -1 {
+		return nil, fmt.Errorf("missing %s column", annotationColumnFeedItemID)
+	}
+
+	if noteIndex == -1 && categoryIndex == -1 {
+		return nil, fmt.Errorf("missing %s or %s column", annotationColumnUserNote, annotationColumnCategory)
+	}
+
+	annotations := make([]*Annotation, 0)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("read row: %w", err)
+		}
+
+		id, err := uuid.Parse(strings.TrimSpace(record[idIndex]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: parse %s: %w", len(annotations)+2, annotationColumnFeedItemID, err)
+		}
+
+		annotation := &Annotation{
+			FeedItemID: starling.FeedItemID(id),
+		}
+
+		if noteIndex != -1 {
+			annotation.UserNote = strings.TrimSpace(record[noteIndex])
+		}
+
+		if categoryIndex != -1 {
+			annotation.Category = strings.ToUpper(strings.TrimSpace(record[categoryIndex]))
+		}
+
+		annotations = append(annotations, annotation)
+	}
+
+	return annotations, nil
+}
+
+// Annotate compares each annotation with the feed item's current values and, unless dryRun is set, writes the
+// changed fields back to Starling. It returns every change, applied or not.
+func (s *TransactionExporter) Annotate(ctx context.Context, accountID string, annotations []*Annotation, dryRun bool) ([]*AnnotationChange, error) {
+	account, err := s.fetchAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*AnnotationChange, 0)
+	for _, annotation := range annotations {
+		item, err := s.api.FetchFeedItem(ctx, account.ID, account.DefaultCategoryID, annotation.FeedItemID)
+		if err != nil {
+			return changes, fmt.Errorf("fetch feed item %s: %w", annotation.FeedItemID, err)
+		}
+
+		if annotation.UserNote != "" && annotation.UserNote != item.UserNote {
+			change := &AnnotationChange{
+				FeedItemID: annotation.FeedItemID,
+				Field:      annotationColumnUserNote,
+				Old:        item.UserNote,
+				New:        annotation.UserNote,
+			}
+
+			if !dryRun {
+				if err := s.api.UpdateUserNote(ctx, account.ID, account.DefaultCategoryID, annotation.FeedItemID, annotation.UserNote); err != nil {
+					return changes, fmt.Errorf("update user note %s: %w", annotation.FeedItemID, err)
+				}
+
+				change.Applied = true
+			}
+
+			changes = append(changes, change)
+		}
+
+		if annotation.Category != "" && annotation.Category != item.CategoryName {
+			change := &AnnotationChange{
+				FeedItemID: annotation.FeedItemID,
+				Field:      annotationColumnCategory,
+				Old:        item.CategoryName,
+				New:        annotation.Category,
+			}
+
+			if !dryRun {
+				if err := s.api.UpdateSpendingCategory(ctx, account.ID, account.DefaultCategoryID, annotation.FeedItemID, annotation.Category); err != nil {
+					return changes, fmt.Errorf("update spending category %s: %w", annotation.FeedItemID, err)
+				}
+
+				change.Applied = true
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "annotated feed items",
+		slog.String("account.id", account.ID.String()),
+		slog.Int("annotation.total", len(annotations)),
+		slog.Int("change.total", len(changes)),
+		slog.Bool("dry_run", dryRun),
+	)
+
+	return changes, nil
+}
@@ -0,0 +1,121 @@
+package exporter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadAnnotations(t *testing.T) {
+	t.Parallel()
+
+	feedItemID := uuid.MustParse("11221122-1122-1122-1122-112211221122")
+
+	tests := map[string]struct {
+		input               string
+		expectedAnnotations []*starlingexporter.Annotation
+		expectedErrMsg      string
+	}{
+		"reads notes and categories": {
+			input: "feedItemUid,userNote,spendingCategory\n" + feedItemID.String() + ", Dinner with Sam ,eating_out\n",
+			expectedAnnotations: []*starlingexporter.Annotation{
+				{FeedItemID: starling.FeedItemID(feedItemID), UserNote: "Dinner with Sam", Category: "EATING_OUT"},
+			},
+		},
+		"reads notes only": {
+			input: "userNote,feedItemUid\nRent," + feedItemID.String() + "\n",
+			expectedAnnotations: []*starlingexporter.Annotation{
+				{FeedItemID: starling.FeedItemID(feedItemID), UserNote: "Rent"},
+			},
+		},
+		"returns error when id column is missing": {
+			input:          "userNote\nRent\n",
+			expectedErrMsg: "missing feedItemUid column",
+		},
+		"returns error when no annotation column": {
+			input:          "feedItemUid\n" + feedItemID.String() + "\n",
+			expectedErrMsg: "missing userNote or spendingCategory column",
+		},
+		"returns error when id is invalid": {
+			input:          "feedItemUid,userNote\nabc,Rent\n",
+			expectedErrMsg: "row 2: parse feedItemUid: invalid UUID length: 3",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			annotations, err := starlingexporter.ReadAnnotations(strings.NewReader(test.input))
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, annotations)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedAnnotations, annotations)
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+
+	accountID := starling.AccountID(uuid.New())
+	noteChanged := starling.FeedItemID(uuid.New())
+	unchanged := starling.FeedItemID(uuid.New())
+
+	newClient := func() *StubClient {
+		return &StubClient{
+			Accounts: []*starling.Account{{ID: accountID}},
+			Transactions: []*starling.FeedItem{
+				{ID: noteChanged, UserNote: "old note", CategoryName: "GROCERIES"},
+				{ID: unchanged, UserNote: "same", CategoryName: "BILLS_AND_SERVICES"},
+			},
+		}
+	}
+
+	annotations := []*starlingexporter.Annotation{
+		{FeedItemID: noteChanged, UserNote: "new note", Category: "EATING_OUT"},
+		{FeedItemID: unchanged, UserNote: "same", Category: "BILLS_AND_SERVICES"},
+	}
+
+	tests := map[string]struct {
+		dryRun          bool
+		expectedUpdates []string
+	}{
+		"dry run does not write changes": {
+			dryRun: true,
+		},
+		"writes changed fields": {
+			expectedUpdates: []string{
+				noteChanged.String() + " userNote=new note",
+				noteChanged.String() + " spendingCategory=EATING_OUT",
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClient()
+			exporter, err := starlingexporter.New(client)
+			require.NoError(t, err)
+
+			changes, err := exporter.Annotate(t.Context(), accountID.String(), annotations, test.dryRun)
+
+			require.NoError(t, err)
+			require.Equal(t, []*starlingexporter.AnnotationChange{
+				{FeedItemID: noteChanged, Field: "userNote", Old: "old note", New: "new note", Applied: !test.dryRun},
+				{FeedItemID: noteChanged, Field: "spendingCategory", Old: "GROCERIES", New: "EATING_OUT", Applied: !test.dryRun},
+			}, changes)
+			require.Equal(t, test.expectedUpdates, client.Updates)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+	"github.com/samber/lo"
+)
+
+var _ export.ClosingBalanceExporter = (*TransactionExporter)(nil)
+
+// AccountBalance is an account's current balance and the progress of its savings goals.
+type AccountBalance struct {
+	AccountID        string          `json:"accountId"`
+	Name             string          `json:"name"`
+	Cleared          domain.Money    `json:"cleared"`
+	Effective        domain.Money    `json:"effective"`
+	Pending          domain.Money    `json:"pending"`
+	AvailableToSpend domain.Money    `json:"availableToSpend"` // Effective balance plus any accepted overdraft
+	SavingsGoals     []*GoalProgress `json:"savingsGoals"`
+}
+
+// GoalProgress is how much has been saved towards a savings goal.
+type GoalProgress struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	TotalSaved domain.Money `json:"totalSaved"`
+	Target     domain.Money `json:"target"`
+	Progress   float64      `json:"progress"` // Percentage of the target saved, zero when the goal has no target
+}
+
+// ExportBalances returns the balance of every account, or only the account matching accountID (see selectAccount)
+// when it isn't empty or "all".
+func (s *TransactionExporter) ExportBalances(ctx context.Context, accountID string) ([]*AccountBalance, error) {
+	accounts, err := s.api.FetchAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	if accountID != "" && !strings.EqualFold(strings.TrimSpace(accountID), export.AllAccounts) {
+		if len(accounts) == 0 {
+			return nil, fmt.Errorf("no account matches %q", accountID)
+		}
+
+		account, err := selectAccount(accounts, accountID)
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = []*starling.Account{account}
+	}
+
+	balances := make([]*AccountBalance, 0, len(accounts))
+	for _, account := range accounts {
+		balance, err := s.api.FetchBalance(ctx, account.ID)
+		if err != nil {
+			return nil, fmt.Errorf("fetch balance: %w", err)
+		}
+
+		goals, err := s.api.FetchSavingsGoals(ctx, account.ID)
+		if err != nil {
+			return nil, fmt.Errorf("fetch savings goals: %w", err)
+		}
+
+		balances = append(balances, &AccountBalance{
+			AccountID:        account.ID.String(),
+			Name:             account.Name,
+			Cleared:          balance.Cleared,
+			Effective:        balance.Effective,
+			Pending:          balance.PendingTransactions,
+			AvailableToSpend: balance.AvailableToSpend(),
+			SavingsGoals: lo.Map(goals, func(goal *starling.SavingsGoal, _ int) *GoalProgress {
+				return &GoalProgress{
+					ID:         goal.ID.String(),
+					Name:       goal.Name,
+					TotalSaved: goal.TotalSaved,
+					Target:     goal.Target,
+					Progress:   goalProgress(goal),
+				}
+			}),
+		})
+	}
+
+	return balances, nil
+}
+
+func goalProgress(goal *starling.SavingsGoal) float64 {
+	if goal.Target.MinorUnit <= 0 {
+		return 0
+	}
+
+	return float64(goal.TotalSaved.MinorUnit) / float64(goal.Target.MinorUnit) * 100
+}
+
+// ExportClosingBalance returns the account's cleared balance at the end of the export's date range.
+// Starling only reports the current balance, so settled transactions since the end date are unwound from it.
+func (s *TransactionExporter) ExportClosingBalance(ctx context.Context, opts export.TransactionOptions) (domain.Money, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return domain.Money{}, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if opts.Space != "" {
+		return domain.Money{}, fmt.Errorf("%w for a space", export.ErrClosingBalanceUnsupported)
+	}
+
+	account, err := s.fetchAccount(ctx, opts.AccountID)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	balance, err := s.api.FetchBalance(ctx, account.ID)
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("fetch balance: %w", err)
+	}
+
+	closing := balance.Cleared
+
+	now := time.Now()
+	if opts.EndDate.Before(now) {
+		transactions, err := s.api.FetchTransactionsSince(ctx, starling.FetchTransactionOptions{
+			AccountID:  account.ID,
+			CategoryID: account.DefaultCategoryID,
+			Start:      opts.EndDate,
+			End:        now,
+		})
+		if err != nil {
+			return domain.Money{}, fmt.Errorf("fetch transactions: %w", err)
+		}
+
+		for _, txn := range transactions {
+			if txn.Status != starling.StatusSettled {
+				continue
+			}
+
+			if txn.Direction == starling.DirectionIN {
+				closing.MinorUnit -= txn.Amount.MinorUnit
+			} else {
+				closing.MinorUnit += txn.Amount.MinorUnit
+			}
+		}
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "calculated closing balance",
+		slog.String("account.id", account.ID.String()),
+		slog.String("balance.at", opts.EndDate.Format(starlingTimeFormat)),
+		slog.String("balance.closing", closing.String()),
+	)
+
+	return closing, nil
+}
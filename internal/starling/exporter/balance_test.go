@@ -0,0 +1,184 @@
+package exporter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportBalances(t *testing.T) {
+	t.Parallel()
+
+	accountID := starling.AccountID(uuid.New())
+	goalID := uuid.New()
+
+	newClient := func() *StubClient {
+		return &StubClient{
+			Accounts: []*starling.Account{
+				{ID: accountID, Name: "Personal"},
+			},
+			Balance: &starling.Balance{
+				Cleared:             domain.Money{MinorUnit: 10000, Currency: "GBP"},
+				Effective:           domain.Money{MinorUnit: 9000, Currency: "GBP"},
+				PendingTransactions: domain.Money{MinorUnit: 1000, Currency: "GBP"},
+				AcceptedOverdraft:   domain.Money{MinorUnit: 5000, Currency: "GBP"},
+			},
+			SavingsGoals: []*starling.SavingsGoal{
+				{
+					ID:         starling.SavingsGoalID(goalID),
+					Name:       "Holiday",
+					TotalSaved: domain.Money{MinorUnit: 2500, Currency: "GBP"},
+					Target:     domain.Money{MinorUnit: 10000, Currency: "GBP"},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		client           func() *StubClient
+		accountID        string
+		expectedBalances []*starlingexporter.AccountBalance
+		expectedErr      string
+	}{
+		"returns balances and savings goal progress": {
+			client: newClient,
+			expectedBalances: []*starlingexporter.AccountBalance{
+				{
+					AccountID:        accountID.String(),
+					Name:             "Personal",
+					Cleared:          domain.Money{MinorUnit: 10000, Currency: "GBP"},
+					Effective:        domain.Money{MinorUnit: 9000, Currency: "GBP"},
+					Pending:          domain.Money{MinorUnit: 1000, Currency: "GBP"},
+					AvailableToSpend: domain.Money{MinorUnit: 14000, Currency: "GBP"},
+					SavingsGoals: []*starlingexporter.GoalProgress{
+						{
+							ID:         goalID.String(),
+							Name:       "Holiday",
+							TotalSaved: domain.Money{MinorUnit: 2500, Currency: "GBP"},
+							Target:     domain.Money{MinorUnit: 10000, Currency: "GBP"},
+							Progress:   25,
+						},
+					},
+				},
+			},
+		},
+		"returns zero progress when goal has no target": {
+			client: func() *StubClient {
+				client := newClient()
+				client.SavingsGoals[0].Target = domain.Money{Currency: "GBP"}
+
+				return client
+			},
+			expectedBalances: []*starlingexporter.AccountBalance{
+				{
+					AccountID:        accountID.String(),
+					Name:             "Personal",
+					Cleared:          domain.Money{MinorUnit: 10000, Currency: "GBP"},
+					Effective:        domain.Money{MinorUnit: 9000, Currency: "GBP"},
+					Pending:          domain.Money{MinorUnit: 1000, Currency: "GBP"},
+					AvailableToSpend: domain.Money{MinorUnit: 14000, Currency: "GBP"},
+					SavingsGoals: []*starlingexporter.GoalProgress{
+						{
+							ID:         goalID.String(),
+							Name:       "Holiday",
+							TotalSaved: domain.Money{MinorUnit: 2500, Currency: "GBP"},
+							Target:     domain.Money{Currency: "GBP"},
+						},
+					},
+				},
+			},
+		},
+		"returns error when account not found": {
+			client:      newClient,
+			accountID:   uuid.NewString(),
+			expectedErr: "no account matches",
+		},
+		"returns error when balance cannot be fetched": {
+			client: func() *StubClient {
+				client := newClient()
+				client.FetchBalanceErr = errors.New("boom")
+
+				return client
+			},
+			expectedErr: "fetch balance: boom",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter, err := starlingexporter.New(test.client())
+			require.NoError(t, err)
+
+			balances, err := exporter.ExportBalances(t.Context(), test.accountID)
+
+			if test.expectedErr != "" {
+				require.Nil(t, balances)
+				require.ErrorContains(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedBalances, balances)
+		})
+	}
+}
+
+func TestExportClosingBalance(t *testing.T) {
+	t.Parallel()
+
+	accountID := starling.AccountID(uuid.New())
+	opts := export.TransactionOptions{
+		StartDate: time.Now().AddDate(0, 0, -30),
+		EndDate:   time.Now().AddDate(0, 0, -7),
+		AccountID: accountID.String(),
+		Options: export.Options{
+			AuthToken: "test-token",
+		},
+	}
+
+	t.Run("unwinds settled transactions since the end date", func(t *testing.T) {
+		t.Parallel()
+
+		client := &StubClient{
+			Accounts: []*starling.Account{{ID: accountID}},
+			Balance: &starling.Balance{
+				Cleared: domain.Money{MinorUnit: 10000, Currency: "GBP"},
+			},
+			Transactions: []*starling.FeedItem{
+				{Status: starling.StatusSettled, Direction: starling.DirectionIN, Amount: domain.Money{MinorUnit: 2000, Currency: "GBP"}},
+				{Status: starling.StatusSettled, Direction: starling.DirectionOUT, Amount: domain.Money{MinorUnit: 500, Currency: "GBP"}},
+				{Status: starling.StatusPending, Direction: starling.DirectionOUT, Amount: domain.Money{MinorUnit: 700, Currency: "GBP"}},
+			},
+		}
+
+		exporter, err := starlingexporter.New(client)
+		require.NoError(t, err)
+
+		balance, err := exporter.ExportClosingBalance(t.Context(), opts)
+
+		require.NoError(t, err)
+		require.Equal(t, domain.Money{MinorUnit: 8500, Currency: "GBP"}, balance)
+	})
+
+	t.Run("returns error for a space", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := starlingexporter.New(&StubClient{})
+		require.NoError(t, err)
+
+		opts := opts
+		opts.Space = "Holiday"
+		_, err = exporter.ExportClosingBalance(t.Context(), opts)
+
+		require.ErrorIs(t, err, export.ErrClosingBalanceUnsupported)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/HallyG/fingrab/internal/export"
 	"github.com/HallyG/fingrab/internal/log"
 	"github.com/HallyG/fingrab/internal/starling"
+	"github.com/HallyG/fingrab/internal/store"
 	"github.com/google/uuid"
 	"github.com/samber/lo"
 )
@@ -21,21 +22,55 @@ const (
 	ExportTypeStarling   = export.ExportType(Starling)
 	starlingTimeFormat   = "2006-01-02"
 	starlingMaxDateRange = time.Duration(0)
+	spaceTypeSavingsGoal = "SAVINGS_GOAL"
 )
 
 var _ export.Exporter = (*TransactionExporter)(nil)
 
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+	Balances:     true,
+	Spaces:       true,
+	Pending:      true,
+	Declines:     true,
+	Webhooks:     true,
+	WriteBack:    true,
+	Cursors:      true,
+}
+
 type TransactionExporter struct {
-	api starling.Client
+	api   starling.Client
+	store *store.Store
 }
 
-func New(api starling.Client) (*TransactionExporter, error) {
+type Option func(*TransactionExporter)
+
+// WithStore configures the exporter to persist the high-water marks of incremental feed syncs in the given store.
+func WithStore(s *store.Store) Option {
+	return func(e *TransactionExporter) {
+		e.store = s
+	}
+}
+
+func New(api starling.Client, opts ...Option) (*TransactionExporter, error) {
 	if api == nil {
 		return nil, errors.New("starling client is required")
 	}
-	return &TransactionExporter{
+
+	exporter := &TransactionExporter{
 		api: api,
-	}, nil
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(exporter)
+	}
+
+	return exporter, nil
 }
 
 func (s *TransactionExporter) Type() export.ExportType {
@@ -46,34 +81,191 @@ func (s *TransactionExporter) MaxDateRange() time.Duration {
 	return starlingMaxDateRange
 }
 
+func (s *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
 func (s *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
 	accounts, err := s.api.FetchAccounts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("fetch accounts: %w", err)
 	}
 
-	return lo.Map(accounts, func(account *starling.Account, _ int) *domain.Account {
-		return &domain.Account{
-			ID:        account.ID.String(),
-			Type:      account.Type,
-			CreatedAt: account.CreatedAt,
+	// Every account belongs to the account holder the token was issued to
+	holderType, owners, err := s.fetchAccountHolder(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Account, 0, len(accounts))
+	for _, account := range accounts {
+		identifiers, err := s.api.FetchAccountIdentifiers(ctx, account.ID)
+		if err != nil {
+			return nil, fmt.Errorf("fetch account identifiers: %w", err)
 		}
-	}), nil
+
+		spaces, err := s.fetchSpaces(ctx, account.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, &domain.Account{
+			ID:            account.ID.String(),
+			Name:          account.Name,
+			Type:          account.Type,
+			Currency:      account.Currency,
+			SortCode:      identifiers.BankIdentifier,
+			AccountNumber: identifiers.AccountIdentifier,
+			IBAN:          identifiers.IBAN,
+			BIC:           identifiers.BIC,
+			HolderType:    holderType,
+			Owners:        owners,
+			CreatedAt:     account.CreatedAt,
+			Spaces:        spaces,
+		})
+	}
+
+	return result, nil
 }
 
-func (s *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
-	if err := opts.Validate(ctx); err != nil {
-		return nil, fmt.Errorf("invalid options: %w", err)
+// fetchAccountHolder returns the account holder's type, e.g. joint, and the names of the people or business holding
+// the accounts. A joint account lists both people.
+func (s *TransactionExporter) fetchAccountHolder(ctx context.Context) (string, []string, error) {
+	holder, err := s.api.FetchAccountHolder(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch account holder: %w", err)
+	}
+
+	holderType := strings.ToLower(string(holder.Type))
+
+	if holder.Type == starling.AccountHolderTypeJoint {
+		joint, err := s.api.FetchJointAccountHolder(ctx)
+		if err != nil {
+			return "", nil, fmt.Errorf("fetch joint account holder: %w", err)
+		}
+
+		owners := make([]string, 0, 2)
+		for _, person := range []*starling.Person{joint.PersonOne, joint.PersonTwo} {
+			if person != nil {
+				owners = append(owners, person.Name())
+			}
+		}
+
+		return holderType, owners, nil
+	}
+
+	name, err := s.api.FetchAccountHolderName(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("fetch account holder name: %w", err)
 	}
 
-	accountID := starling.AccountID(uuid.Nil)
-	if opts.AccountID != "" {
-		uuid, err := uuid.Parse(opts.AccountID)
+	return holderType, []string{name}, nil
+}
+
+// fetchSpaces returns the account's savings goals and spending spaces.
+// A space's ID is also the category ID of its transaction feed.
+func (s *TransactionExporter) fetchSpaces(ctx context.Context, accountID starling.AccountID) ([]*domain.Space, error) {
+	goals, err := s.api.FetchSavingsGoals(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch savings goals: %w", err)
+	}
+
+	spendingSpaces, err := s.api.FetchSpendingSpaces(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch spending spaces: %w", err)
+	}
+
+	spaces := make([]*domain.Space, 0, len(goals)+len(spendingSpaces))
+	for _, goal := range goals {
+		spaces = append(spaces, &domain.Space{
+			ID:      goal.ID.String(),
+			Name:    goal.Name,
+			Type:    spaceTypeSavingsGoal,
+			State:   goal.State,
+			Balance: goal.TotalSaved,
+		})
+	}
+
+	for _, space := range spendingSpaces {
+		spaces = append(spaces, &domain.Space{
+			ID:      space.ID.String(),
+			Name:    space.Name,
+			Type:    space.Type,
+			State:   space.State,
+			Balance: space.Balance,
+		})
+	}
+
+	return spaces, nil
+}
+
+// category is a transaction feed to export, either an account's main balance or one of its spaces.
+type category struct {
+	ID   starling.CategoryID
+	Name string
+}
+
+// selectCategories returns the account's categories to export. That's the space matching spaceSelector, by ID or
+// name (case-insensitive), when it's set. Otherwise the account's main balance, and its spaces when allSpaces is set.
+func (s *TransactionExporter) selectCategories(ctx context.Context, account *starling.Account, spaceSelector string, allSpaces bool) ([]*category, error) {
+	main := &category{ID: account.DefaultCategoryID, Name: account.Name}
+	if spaceSelector == "" && !allSpaces {
+		return []*category{main}, nil
+	}
+
+	spaces, err := s.fetchSpaces(ctx, account.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	toCategory := func(space *domain.Space) (*category, error) {
+		id, err := uuid.Parse(space.ID)
 		if err != nil {
-			return nil, fmt.Errorf("parse account id: %w", err)
+			return nil, fmt.Errorf("parse space id: %w", err)
 		}
 
-		accountID = starling.AccountID(uuid)
+		return &category{ID: starling.CategoryID(id), Name: account.Name + " / " + space.Name}, nil
+	}
+
+	if spaceSelector == "" {
+		categories := []*category{main}
+		for _, space := range spaces {
+			c, err := toCategory(space)
+			if err != nil {
+				return nil, err
+			}
+
+			categories = append(categories, c)
+		}
+
+		return categories, nil
+	}
+
+	spaceSelector = strings.TrimSpace(spaceSelector)
+	space, ok := lo.Find(spaces, func(space *domain.Space) bool {
+		return space.ID == spaceSelector || strings.EqualFold(space.Name, spaceSelector)
+	})
+	if !ok {
+		names := lo.Map(spaces, func(space *domain.Space, _ int) string { return space.Name })
+		return nil, fmt.Errorf("no space matches %q (spaces: %s)", spaceSelector, strings.Join(names, ", "))
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "selected space",
+		slog.String("space.id", space.ID),
+		slog.String("space.name", space.Name),
+	)
+
+	c, err := toCategory(space)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*category{c}, nil
+}
+
+func (s *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
 	}
 
 	log.FromContext(ctx).InfoContext(ctx, "starting export of transactions",
@@ -81,47 +273,121 @@ func (s *TransactionExporter) ExportTransactions(ctx context.Context, opts expor
 		slog.String("export.end", opts.EndDate.Format(starlingTimeFormat)),
 	)
 
-	account, err := s.fetchAccount(ctx, accountID)
+	accounts, err := s.fetchAccounts(ctx, opts.AccountID)
 	if err != nil {
 		return nil, err
 	}
 
-	categoryID := account.DefaultCategoryID
-	transactions, err := s.fetchTransactionsSince(ctx, account.ID, categoryID, opts.StartDate, opts.EndDate)
-	if err != nil {
-		return nil, err
+	allAccounts := strings.EqualFold(strings.TrimSpace(opts.AccountID), export.AllAccounts)
+	result := make([]*domain.Transaction, 0)
+	for _, account := range accounts {
+		categories, err := s.selectCategories(ctx, account, opts.Space, allAccounts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, category := range categories {
+			transactions, err := s.fetchTransactionsSince(ctx, account.ID, category.ID, opts.StartDate, opts.EndDate, opts.Audit)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, txn := range transactions {
+				result = append(result, toAccountTransaction(txn, account, category))
+			}
+		}
 	}
 
 	log.FromContext(ctx).InfoContext(ctx, "successfully exported transactions",
-		slog.Int("transaction.count", len(transactions)),
+		slog.Int("account.count", len(accounts)),
+		slog.Int("transaction.count", len(result)),
 	)
 
-	return lo.Map(transactions, func(txn *starling.FeedItem, _ int) *domain.Transaction {
-		reference := s.determineReference(txn)
+	return result, nil
+}
 
-		depositSignum := int64(-1)
-		isDeposit := starling.DirectionIN == txn.Direction
+// toAccountTransaction converts the feed item to a transaction labelled with the account (or space) it belongs to.
+// Feed items are in the account's currency, which is used when the item doesn't include one.
+func toAccountTransaction(txn *starling.FeedItem, account *starling.Account, category *category) *domain.Transaction {
+	transaction := toDomainTransaction(txn)
+	transaction.Account = category.Name
+	if transaction.Amount.Currency == "" {
+		transaction.Amount.Currency = account.Currency
+	}
 
-		if isDeposit {
-			depositSignum = 1
-		}
+	return transaction
+}
 
-		return &domain.Transaction{
-			Amount: domain.Money{
-				MinorUnit: txn.Amount.MinorUnit * depositSignum,
-				Currency:  txn.Amount.Currency,
-			},
-			Reference: reference,
-			Category:  txn.CategoryName,
-			CreatedAt: txn.TransactedAt,
-			IsDeposit: isDeposit,
-			BankName:  Starling,
-			Notes:     txn.UserNote,
-		}
-	}), nil
+// toDomainTransaction converts the feed item, signing the amount by its direction.
+func toDomainTransaction(txn *starling.FeedItem) *domain.Transaction {
+	reference := determineReference(txn)
+
+	depositSignum := int64(-1)
+	isDeposit := starling.DirectionIN == txn.Direction
+
+	if isDeposit {
+		depositSignum = 1
+	}
+
+	return &domain.Transaction{
+		ID: txn.ID.String(),
+		Amount: domain.Money{
+			MinorUnit: txn.Amount.MinorUnit * depositSignum,
+			Currency:  txn.Amount.Currency,
+		},
+		Reference: reference,
+		Category:  txn.CategoryName,
+		CreatedAt: txn.TransactedAt,
+		IsDeposit: isDeposit,
+		BankName:  Starling,
+		Notes:     txn.UserNote,
+		Status:    transactionStatus(txn.Status),
+	}
+}
+
+// isAuditOnly reports whether feed items of the status are only exported when auditing, as no money has moved.
+func isAuditOnly(status starling.Status) bool {
+	switch status {
+	case starling.StatusDeclined, starling.StatusReversed, starling.StatusAccountCheck:
+		return true
+	default:
+		return false
+	}
 }
 
-func (s *TransactionExporter) fetchAccount(ctx context.Context, accountID starling.AccountID) (*starling.Account, error) {
+func transactionStatus(status starling.Status) domain.TransactionStatus {
+	switch status {
+	case starling.StatusDeclined:
+		return domain.TransactionStatusDeclined
+	case starling.StatusReversed, starling.StatusRefunded, starling.StatusUpcomingCancelled:
+		return domain.TransactionStatusReversed
+	case starling.StatusAccountCheck:
+		return domain.TransactionStatusCardCheck
+	case starling.StatusPending, starling.StatusUpcoming, starling.StatusRetrying:
+		return domain.TransactionStatusPending
+	case starling.StatusSettled:
+		return domain.TransactionStatusSettled
+	default:
+		return domain.TransactionStatusSettled
+	}
+}
+
+// fetchAccount returns the account matching the selector, see selectAccounts.
+func (s *TransactionExporter) fetchAccount(ctx context.Context, selector string) (*starling.Account, error) {
+	if strings.EqualFold(strings.TrimSpace(selector), export.AllAccounts) {
+		return nil, errors.New("a single account is required")
+	}
+
+	accounts, err := s.fetchAccounts(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts[0], nil
+}
+
+// fetchAccounts returns every account when the selector is "all", otherwise the single account matching it.
+func (s *TransactionExporter) fetchAccounts(ctx context.Context, selector string) ([]*starling.Account, error) {
 	accounts, err := s.api.FetchAccounts(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("fetch accounts: %w", err)
@@ -131,29 +397,61 @@ func (s *TransactionExporter) fetchAccount(ctx context.Context, accountID starli
 		return nil, errors.New("no accounts found, exiting")
 	}
 
-	selectedAccount := accounts[0]
-	accountIDs := make([]starling.AccountID, 0)
-	for _, account := range accounts {
-		if accountID == account.ID {
-			selectedAccount = account
-		}
+	log.FromContext(ctx).InfoContext(ctx, "found accounts",
+		slog.Int("account.total", len(accounts)),
+	)
 
-		accountIDs = append(accountIDs, account.ID)
+	if strings.EqualFold(strings.TrimSpace(selector), export.AllAccounts) {
+		return accounts, nil
 	}
 
-	log.FromContext(ctx).InfoContext(ctx, "found accounts",
-		slog.Int("account.total", len(accountIDs)),
-	)
+	selectedAccount, err := selectAccount(accounts, selector)
+	if err != nil {
+		return nil, err
+	}
 
 	log.FromContext(ctx).InfoContext(ctx, "selected account",
 		slog.String("account.id", selectedAccount.ID.String()),
 		slog.String("account.category.id", selectedAccount.DefaultCategoryID.String()),
+		slog.String("account.currency", selectedAccount.Currency),
 	)
 
-	return selectedAccount, nil
+	return []*starling.Account{selectedAccount}, nil
+}
+
+// selectAccount returns the account whose ID matches the selector or, failing that, the only account whose name,
+// currency or type matches it (case-insensitive), e.g. EUR or ADDITIONAL. An empty selector selects the first
+// (primary) account.
+func selectAccount(accounts []*starling.Account, selector string) (*starling.Account, error) {
+	selector = strings.TrimSpace(selector)
+	if selector == "" {
+		return accounts[0], nil
+	}
+
+	if account, ok := lo.Find(accounts, func(account *starling.Account) bool { return account.ID.String() == selector }); ok {
+		return account, nil
+	}
+
+	matches := lo.Filter(accounts, func(account *starling.Account, _ int) bool {
+		return strings.EqualFold(account.Name, selector) ||
+			strings.EqualFold(account.Currency, selector) ||
+			strings.EqualFold(account.Type, selector)
+	})
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no account matches %q", selector)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := lo.Map(matches, func(account *starling.Account, _ int) string { return account.ID.String() })
+		return nil, fmt.Errorf("%q matches %d accounts (%s), use an account ID instead", selector, len(matches), strings.Join(ids, ", "))
+	}
 }
 
-func (s *TransactionExporter) fetchTransactionsSince(ctx context.Context, accountID starling.AccountID, categoryID starling.CategoryID, start time.Time, end time.Time) ([]*starling.FeedItem, error) {
+// fetchTransactionsSince fetches the category's feed items, and any related round-ups, in the date range.
+// Declined and reversed feed items, and account checks, are only included when audit is true.
+func (s *TransactionExporter) fetchTransactionsSince(ctx context.Context, accountID starling.AccountID, categoryID starling.CategoryID, start time.Time, end time.Time, audit bool) ([]*starling.FeedItem, error) {
 	log.FromContext(ctx).InfoContext(ctx, "fetching transactions",
 		slog.String("account.id", accountID.String()),
 		slog.String("account.category.id", categoryID.String()),
@@ -182,7 +480,7 @@ func (s *TransactionExporter) fetchTransactionsSince(ctx context.Context, accoun
 
 	transactions = append(transactions, roundUpTransactions...)
 	filteredTransactions := lo.Filter(transactions, func(txn *starling.FeedItem, _ int) bool {
-		return txn.Status != starling.StatusDeclined
+		return audit || !isAuditOnly(txn.Status)
 	})
 
 	log.FromContext(ctx).InfoContext(ctx, "fetched transactions",
@@ -237,7 +535,7 @@ func (s *TransactionExporter) fetchRoundUpTransactions(ctx context.Context, acco
 	return roundUpTransactions, nil
 }
 
-func (s *TransactionExporter) determineReference(txn *starling.FeedItem) string {
+func determineReference(txn *starling.FeedItem) string {
 	if txn.CategoryName == "TRANSFERS" && txn.CounterPartyType == "CATEGORY" && txn.Source == "INTERNAL_TRANSFER" && txn.SourceSubType == "" {
 		return "Savings Pot"
 	}
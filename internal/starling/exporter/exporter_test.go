@@ -2,6 +2,8 @@ package exporter_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -10,16 +12,36 @@ import (
 	"github.com/HallyG/fingrab/internal/starling"
 	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
 	"github.com/google/uuid"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 )
 
 type StubClient struct {
 	Accounts         []*starling.Account
 	SavingsGoals     []*starling.SavingsGoal
+	SpendingSpaces   []*starling.SpendingSpace
+	Balance          *starling.Balance
+	StatementPeriods []*starling.StatementPeriod
+	Statements       map[string][]byte
+	Payees           []*starling.Payee
+	StandingOrders   []*starling.StandingOrder
+	Mandates         []*starling.DirectDebitMandate
 	Transactions     []*starling.FeedItem
+	SpendingInsights *starling.SpendingInsights
+	Identifiers      map[starling.AccountID]*starling.AccountIdentifiers
+	AccountHolder    *starling.AccountHolder
+	HolderName       string
+	JointHolder      *starling.JointAccountHolder
 	FetchAccountsErr error
+	FetchHolderErr   error
 	FetchGoalsErr    error
+	FetchSpacesErr   error
+	FetchBalanceErr  error
 	FetchTxnsErr     error
+
+	RequestedCategoryIDs []starling.CategoryID
+	RequestedSince       []time.Time
+	Updates              []string
 }
 
 var _ starling.Client = (*StubClient)(nil)
@@ -29,13 +51,43 @@ func (c *StubClient) FetchTransactionsSince(ctx context.Context, opts starling.F
 		return nil, c.FetchTxnsErr
 	}
 
+	c.RequestedCategoryIDs = append(c.RequestedCategoryIDs, opts.CategoryID)
+
 	return c.Transactions, nil
 }
 
+func (c *StubClient) FetchFeedChangesSince(ctx context.Context, accountID starling.AccountID, categoryID starling.CategoryID, since time.Time) ([]*starling.FeedItem, error) {
+	if c.FetchTxnsErr != nil {
+		return nil, c.FetchTxnsErr
+	}
+
+	c.RequestedSince = append(c.RequestedSince, since)
+
+	return lo.Filter(c.Transactions, func(item *starling.FeedItem, _ int) bool {
+		return !item.UpdatedAt.Before(since)
+	}), nil
+}
+
 func (c *StubClient) FetchFeedItem(ctx context.Context, accountID starling.AccountID, categoryID starling.CategoryID, feedItemID starling.FeedItemID) (*starling.FeedItem, error) {
+	for _, item := range c.Transactions {
+		if item.ID == feedItemID {
+			return item, nil
+		}
+	}
+
 	return &starling.FeedItem{}, nil
 }
 
+func (c *StubClient) UpdateUserNote(ctx context.Context, accountID starling.AccountID, categoryID starling.CategoryID, feedItemID starling.FeedItemID, note string) error {
+	c.Updates = append(c.Updates, fmt.Sprintf("%s userNote=%s", feedItemID, note))
+	return nil
+}
+
+func (c *StubClient) UpdateSpendingCategory(ctx context.Context, accountID starling.AccountID, categoryID starling.CategoryID, feedItemID starling.FeedItemID, category string) error {
+	c.Updates = append(c.Updates, fmt.Sprintf("%s spendingCategory=%s", feedItemID, category))
+	return nil
+}
+
 func (c *StubClient) FetchAccounts(ctx context.Context) ([]*starling.Account, error) {
 	if c.FetchAccountsErr != nil {
 		return nil, c.FetchAccountsErr
@@ -52,6 +104,87 @@ func (c *StubClient) FetchSavingsGoals(ctx context.Context, accountID starling.A
 	return c.SavingsGoals, nil
 }
 
+func (c *StubClient) FetchSpendingSpaces(ctx context.Context, accountID starling.AccountID) ([]*starling.SpendingSpace, error) {
+	if c.FetchSpacesErr != nil {
+		return nil, c.FetchSpacesErr
+	}
+
+	return c.SpendingSpaces, nil
+}
+
+func (c *StubClient) FetchBalance(ctx context.Context, accountID starling.AccountID) (*starling.Balance, error) {
+	if c.FetchBalanceErr != nil {
+		return nil, c.FetchBalanceErr
+	}
+
+	return c.Balance, nil
+}
+
+func (c *StubClient) FetchStatementPeriods(ctx context.Context, accountID starling.AccountID) ([]*starling.StatementPeriod, error) {
+	return c.StatementPeriods, nil
+}
+
+func (c *StubClient) DownloadStatement(ctx context.Context, accountID starling.AccountID, period string, format starling.StatementFormat) ([]byte, error) {
+	data, ok := c.Statements[period]
+	if !ok {
+		return nil, fmt.Errorf("statement %s not found", period)
+	}
+
+	return data, nil
+}
+
+func (c *StubClient) FetchPayees(ctx context.Context) ([]*starling.Payee, error) {
+	return c.Payees, nil
+}
+
+func (c *StubClient) FetchStandingOrders(ctx context.Context, accountID starling.AccountID, categoryID starling.CategoryID) ([]*starling.StandingOrder, error) {
+	return c.StandingOrders, nil
+}
+
+func (c *StubClient) FetchDirectDebitMandates(ctx context.Context) ([]*starling.DirectDebitMandate, error) {
+	return c.Mandates, nil
+}
+
+func (c *StubClient) FetchSpendingInsights(ctx context.Context, accountID starling.AccountID, breakdown starling.InsightsBreakdown, year int, month time.Month) (*starling.SpendingInsights, error) {
+	if c.SpendingInsights == nil {
+		return nil, fmt.Errorf("no insights for %d-%02d", year, month)
+	}
+
+	return c.SpendingInsights, nil
+}
+
+func (c *StubClient) FetchAccountIdentifiers(ctx context.Context, accountID starling.AccountID) (*starling.AccountIdentifiers, error) {
+	if identifiers, ok := c.Identifiers[accountID]; ok {
+		return identifiers, nil
+	}
+
+	return &starling.AccountIdentifiers{}, nil
+}
+
+func (c *StubClient) FetchAccountHolder(ctx context.Context) (*starling.AccountHolder, error) {
+	if c.FetchHolderErr != nil {
+		return nil, c.FetchHolderErr
+	}
+
+	if c.AccountHolder == nil {
+		return &starling.AccountHolder{Type: starling.AccountHolderTypeIndividual}, nil
+	}
+
+	return c.AccountHolder, nil
+}
+
+func (c *StubClient) FetchAccountHolderName(ctx context.Context) (string, error) {
+	return c.HolderName, nil
+}
+
+func (c *StubClient) FetchJointAccountHolder(ctx context.Context) (*starling.JointAccountHolder, error) {
+	if c.JointHolder == nil {
+		return nil, errors.New("not a joint account holder")
+	}
+
+	return c.JointHolder, nil
+}
+
 func TestNewTransactionExport(t *testing.T) {
 	t.Parallel()
 
@@ -73,6 +206,11 @@ func TestNewTransactionExport(t *testing.T) {
 		require.NotNil(t, exporter)
 		require.Equal(t, time.Duration(0), exporter.MaxDateRange())
 		require.Equal(t, starlingexporter.ExportTypeStarling, exporter.Type())
+		require.Equal(t, []string{"accounts", "transactions", "balances", "spaces", "pending", "declines", "webhooks", "write-back", "cursors"}, exporter.Capabilities().Names())
+
+		metadata, registered := export.Lookup(exporter.Type())
+		require.True(t, registered)
+		require.Equal(t, exporter.Capabilities(), metadata.Capabilities)
 	})
 }
 
@@ -81,6 +219,8 @@ func TestExportAccounts(t *testing.T) {
 
 	accountID := starling.AccountID(uuid.New())
 	categoryID := starling.CategoryID(uuid.New())
+	goalID := uuid.New()
+	spaceID := uuid.New()
 	now := time.Now()
 
 	setup := func(t *testing.T) export.Exporter {
@@ -93,11 +233,38 @@ func TestExportAccounts(t *testing.T) {
 				Type:              "PRIMARY",
 				Currency:          "GBP",
 				CreatedAt:         now,
+				Name:              "Personal",
 			},
 		}
 
 		client := &StubClient{
-			Accounts: accounts,
+			Accounts:   accounts,
+			HolderName: "Jo Bloggs",
+			Identifiers: map[starling.AccountID]*starling.AccountIdentifiers{
+				accountID: {
+					AccountIdentifier: "12345678",
+					BankIdentifier:    "608371",
+					IBAN:              "GB33SRLG60837112345678",
+					BIC:               "SRLGGB2L",
+				},
+			},
+			SavingsGoals: []*starling.SavingsGoal{
+				{
+					ID:         starling.SavingsGoalID(goalID),
+					Name:       "Holiday",
+					State:      "ACTIVE",
+					TotalSaved: domain.Money{MinorUnit: 12500, Currency: "GBP"},
+				},
+			},
+			SpendingSpaces: []*starling.SpendingSpace{
+				{
+					ID:      starling.SpaceID(spaceID),
+					Name:    "Bills",
+					Type:    "SPENDING_SPACE",
+					State:   "ACTIVE",
+					Balance: domain.Money{MinorUnit: 4200, Currency: "GBP"},
+				},
+			},
 		}
 
 		exporter, err := starlingexporter.New(client)
@@ -115,7 +282,88 @@ func TestExportAccounts(t *testing.T) {
 		require.Len(t, accounts, 1)
 		require.Equal(t, accountID.String(), accounts[0].ID)
 		require.Equal(t, "PRIMARY", accounts[0].Type)
+		require.Equal(t, "Personal", accounts[0].Name)
+		require.Equal(t, "GBP", accounts[0].Currency)
+		require.Equal(t, "608371", accounts[0].SortCode)
+		require.Equal(t, "12345678", accounts[0].AccountNumber)
+		require.Equal(t, "GB33SRLG60837112345678", accounts[0].IBAN)
+		require.Equal(t, "SRLGGB2L", accounts[0].BIC)
+		require.Equal(t, "individual", accounts[0].HolderType)
+		require.Equal(t, []string{"Jo Bloggs"}, accounts[0].Owners)
 		require.WithinDuration(t, now, accounts[0].CreatedAt, time.Second)
+		require.Equal(t, []*domain.Space{
+			{
+				ID:      goalID.String(),
+				Name:    "Holiday",
+				Type:    "SAVINGS_GOAL",
+				State:   "ACTIVE",
+				Balance: domain.Money{MinorUnit: 12500, Currency: "GBP"},
+			},
+			{
+				ID:      spaceID.String(),
+				Name:    "Bills",
+				Type:    "SPENDING_SPACE",
+				State:   "ACTIVE",
+				Balance: domain.Money{MinorUnit: 4200, Currency: "GBP"},
+			},
+		}, accounts[0].Spaces)
+	})
+
+	t.Run("returns error when spaces cannot be fetched", func(t *testing.T) {
+		t.Parallel()
+
+		client := &StubClient{
+			Accounts:       []*starling.Account{{ID: accountID}},
+			FetchSpacesErr: errors.New("boom"),
+		}
+
+		exporter, err := starlingexporter.New(client)
+		require.NoError(t, err)
+
+		accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+		require.Nil(t, accounts)
+		require.ErrorContains(t, err, "fetch spending spaces: boom")
+	})
+
+	t.Run("lists both people holding a joint account", func(t *testing.T) {
+		t.Parallel()
+
+		client := &StubClient{
+			Accounts:      []*starling.Account{{ID: accountID}},
+			AccountHolder: &starling.AccountHolder{Type: starling.AccountHolderTypeJoint},
+			JointHolder: &starling.JointAccountHolder{
+				PersonOne: &starling.Person{FirstName: "Jo", LastName: "Bloggs"},
+				PersonTwo: &starling.Person{FirstName: "Sam", LastName: "Bloggs"},
+			},
+		}
+
+		exporter, err := starlingexporter.New(client)
+		require.NoError(t, err)
+
+		accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, accounts, 1)
+		require.Equal(t, "joint", accounts[0].HolderType)
+		require.Equal(t, []string{"Jo Bloggs", "Sam Bloggs"}, accounts[0].Owners)
+	})
+
+	t.Run("returns error when account holder cannot be fetched", func(t *testing.T) {
+		t.Parallel()
+
+		client := &StubClient{
+			Accounts:       []*starling.Account{{ID: accountID}},
+			FetchHolderErr: errors.New("boom"),
+		}
+
+		exporter, err := starlingexporter.New(client)
+		require.NoError(t, err)
+
+		accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+		require.Nil(t, accounts)
+		require.ErrorContains(t, err, "fetch account holder: boom")
 	})
 }
 
@@ -146,6 +394,25 @@ func TestExportTransactions(t *testing.T) {
 					Currency:  "GBP",
 				},
 			},
+			{
+				CategoryID:  categoryID,
+				Status:      starling.StatusReversed,
+				Direction:   starling.DirectionOUT,
+				Description: "reversed",
+				Amount: domain.Money{
+					MinorUnit: 450,
+					Currency:  "GBP",
+				},
+			},
+			{
+				CategoryID:  categoryID,
+				Status:      starling.StatusAccountCheck,
+				Direction:   starling.DirectionOUT,
+				Description: "account check",
+				Amount: domain.Money{
+					Currency: "GBP",
+				},
+			},
 			{
 				CategoryID:  categoryID,
 				Status:      starling.StatusSettled,
@@ -180,7 +447,7 @@ func TestExportTransactions(t *testing.T) {
 		return exporter
 	}
 
-	t.Run("excludes declined transactions", func(t *testing.T) {
+	t.Run("excludes declined and reversed transactions and account checks", func(t *testing.T) {
 		t.Parallel()
 
 		res, err := setup(t).ExportTransactions(
@@ -208,5 +475,218 @@ func TestExportTransactions(t *testing.T) {
 			MinorUnit: 123,
 			Currency:  "GBP",
 		}, res[0].Amount)
+		require.Equal(t, domain.TransactionStatusSettled, res[0].Status)
 	})
+
+	t.Run("includes declined and reversed transactions and account checks in audit mode", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := setup(t).ExportTransactions(
+			t.Context(),
+			export.TransactionOptions{
+				StartDate: time.Now().Add(-24 * time.Hour),
+				EndDate:   time.Now(),
+				AccountID: accountID.String(),
+				Audit:     true,
+				Options: export.Options{
+					AuthToken: "test-token",
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		require.Len(t, res, 5)
+		require.Equal(t, "declined", res[0].Reference)
+		require.Equal(t, domain.TransactionStatusDeclined, res[0].Status)
+		require.Equal(t, "reversed", res[1].Reference)
+		require.Equal(t, domain.TransactionStatusReversed, res[1].Status)
+		require.Equal(t, "account check", res[2].Reference)
+		require.Equal(t, domain.TransactionStatusCardCheck, res[2].Status)
+	})
+
+	t.Run("exports the transactions of a space", func(t *testing.T) {
+		t.Parallel()
+
+		goalID := uuid.New()
+		spaceID := uuid.New()
+		opts := export.TransactionOptions{
+			StartDate: time.Now().Add(-24 * time.Hour),
+			EndDate:   time.Now(),
+			AccountID: accountID.String(),
+			Options: export.Options{
+				AuthToken: "test-token",
+			},
+		}
+
+		tests := map[string]struct {
+			space              string
+			expectedCategoryID starling.CategoryID
+			expectedErr        string
+		}{
+			"by savings goal name": {
+				space:              "holiday",
+				expectedCategoryID: starling.CategoryID(goalID),
+			},
+			"by spending space ID": {
+				space:              spaceID.String(),
+				expectedCategoryID: starling.CategoryID(spaceID),
+			},
+			"unknown space": {
+				space:       "rainy day",
+				expectedErr: `no space matches "rainy day" (spaces: Holiday, Bills)`,
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				client := &StubClient{
+					Accounts: []*starling.Account{{ID: accountID, DefaultCategoryID: categoryID}},
+					SavingsGoals: []*starling.SavingsGoal{
+						{ID: starling.SavingsGoalID(goalID), Name: "Holiday"},
+					},
+					SpendingSpaces: []*starling.SpendingSpace{
+						{ID: starling.SpaceID(spaceID), Name: "Bills"},
+					},
+				}
+
+				exporter, err := starlingexporter.New(client)
+				require.NoError(t, err)
+
+				opts := opts
+				opts.Space = test.space
+				_, err = exporter.ExportTransactions(t.Context(), opts)
+
+				if test.expectedErr != "" {
+					require.EqualError(t, err, test.expectedErr)
+					require.Empty(t, client.RequestedCategoryIDs)
+
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, []starling.CategoryID{test.expectedCategoryID}, client.RequestedCategoryIDs)
+			})
+		}
+	})
+}
+
+func TestExportTransactionsSelectsAccounts(t *testing.T) {
+	t.Parallel()
+
+	gbpAccountID := starling.AccountID(uuid.New())
+	gbpCategoryID := starling.CategoryID(uuid.New())
+	eurAccountID := starling.AccountID(uuid.New())
+	eurCategoryID := starling.CategoryID(uuid.New())
+	spaceID := uuid.New()
+
+	accounts := []*starling.Account{
+		{ID: gbpAccountID, DefaultCategoryID: gbpCategoryID, Type: "PRIMARY", Currency: "GBP", Name: "Personal"},
+		{ID: eurAccountID, DefaultCategoryID: eurCategoryID, Type: "ADDITIONAL", Currency: "EUR", Name: "Euro"},
+	}
+
+	newClient := func(accounts []*starling.Account) *StubClient {
+		return &StubClient{
+			Accounts: accounts,
+			SpendingSpaces: []*starling.SpendingSpace{
+				{ID: starling.SpaceID(spaceID), Name: "Bills"},
+			},
+			Transactions: []*starling.FeedItem{
+				{
+					Status:      starling.StatusSettled,
+					Direction:   starling.DirectionOUT,
+					Description: "coffee",
+					Amount:      domain.Money{MinorUnit: 250},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		accounts            []*starling.Account
+		accountID           string
+		expectedCategoryIDs []starling.CategoryID
+		expectedAccounts    []string
+		expectedCurrencies  []string
+		expectedErr         string
+	}{
+		"selects the primary account by default": {
+			expectedCategoryIDs: []starling.CategoryID{gbpCategoryID},
+			expectedAccounts:    []string{"Personal"},
+			expectedCurrencies:  []string{"GBP"},
+		},
+		"selects an account by ID": {
+			accountID:           eurAccountID.String(),
+			expectedCategoryIDs: []starling.CategoryID{eurCategoryID},
+			expectedAccounts:    []string{"Euro"},
+			expectedCurrencies:  []string{"EUR"},
+		},
+		"selects an account by currency": {
+			accountID:           "eur",
+			expectedCategoryIDs: []starling.CategoryID{eurCategoryID},
+			expectedAccounts:    []string{"Euro"},
+			expectedCurrencies:  []string{"EUR"},
+		},
+		"selects an account by name": {
+			accountID:           "Personal",
+			expectedCategoryIDs: []starling.CategoryID{gbpCategoryID},
+			expectedAccounts:    []string{"Personal"},
+			expectedCurrencies:  []string{"GBP"},
+		},
+		"selects every account and space": {
+			accountID: export.AllAccounts,
+			expectedCategoryIDs: []starling.CategoryID{
+				gbpCategoryID, starling.CategoryID(spaceID),
+				eurCategoryID, starling.CategoryID(spaceID),
+			},
+			expectedAccounts:   []string{"Personal", "Personal / Bills", "Euro", "Euro / Bills"},
+			expectedCurrencies: []string{"GBP", "GBP", "EUR", "EUR"},
+		},
+		"returns error when no account matches": {
+			accountID:   "USD",
+			expectedErr: `no account matches "USD"`,
+		},
+		"returns error when several accounts match": {
+			accounts: []*starling.Account{
+				{ID: gbpAccountID, DefaultCategoryID: gbpCategoryID, Currency: "GBP", Name: "Personal"},
+				{ID: eurAccountID, DefaultCategoryID: eurCategoryID, Currency: "GBP", Name: "Joint"},
+			},
+			accountID:   "gbp",
+			expectedErr: "use an account ID instead",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newClient(accounts)
+			if test.accounts != nil {
+				client = newClient(test.accounts)
+			}
+
+			exporter, err := starlingexporter.New(client)
+			require.NoError(t, err)
+
+			res, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				StartDate: time.Now().Add(-24 * time.Hour),
+				EndDate:   time.Now(),
+				AccountID: test.accountID,
+				Options: export.Options{
+					AuthToken: "test-token",
+				},
+			})
+
+			if test.expectedErr != "" {
+				require.ErrorContains(t, err, test.expectedErr)
+				require.Empty(t, client.RequestedCategoryIDs)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedCategoryIDs, client.RequestedCategoryIDs)
+			require.Equal(t, test.expectedAccounts, lo.Map(res, func(txn *domain.Transaction, _ int) string { return txn.Account }))
+			require.Equal(t, test.expectedCurrencies, lo.Map(res, func(txn *domain.Transaction, _ int) string { return txn.Amount.Currency }))
+		})
+	}
 }
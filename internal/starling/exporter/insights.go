@@ -0,0 +1,141 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/samber/lo"
+)
+
+type InsightsOptions struct {
+	AccountID string                     // Defaults to the first account
+	Month     string                     // Month to report on (YYYY-MM)
+	By        starling.InsightsBreakdown // category, counterparty or country
+}
+
+func (o InsightsOptions) Validate(ctx context.Context) error {
+	return validation.ValidateStructWithContext(ctx, &o,
+		validation.Field(&o.Month, validation.Required.Error("is required"), validation.By(func(value any) error {
+			month, ok := value.(string)
+			if !ok {
+				return validation.NewError("validation_period_type", "must be a string")
+			}
+
+			if _, err := time.Parse(statementPeriodFormat, month); err != nil {
+				return validation.NewError("validation_period", "must be a month (YYYY-MM)")
+			}
+
+			return nil
+		})),
+		validation.Field(&o.By, validation.Required.Error("is required"), validation.In(
+			starling.InsightsByCategory,
+			starling.InsightsByCounterparty,
+			starling.InsightsByCountry,
+		).Error("must be category, counterparty or country")),
+	)
+}
+
+// SpendingInsights is a month's spending as aggregated by Starling, a cross-check for totals calculated from the
+// exported transactions.
+type SpendingInsights struct {
+	AccountID string                     `json:"accountId"`
+	Period    string                     `json:"period"`
+	By        starling.InsightsBreakdown `json:"by"`
+	Spent     domain.Money               `json:"spent"`
+	Received  domain.Money               `json:"received"`
+	Net       domain.Money               `json:"net"` // Received less spent, negative when more was spent
+	Breakdown []*SpendingInsight         `json:"breakdown"`
+}
+
+// SpendingInsight is the spending for a single category, counterparty or country.
+type SpendingInsight struct {
+	Name             string       `json:"name"`
+	Spent            domain.Money `json:"spent"`
+	Received         domain.Money `json:"received"`
+	Net              domain.Money `json:"net"`        // Received less spent, negative when more was spent
+	Percentage       float64      `json:"percentage"` // Share of the month's spending
+	TransactionCount int          `json:"transactionCount"`
+}
+
+// ExportSpendingInsights returns the account's spending for the month, aggregated by category, counterparty or
+// country.
+func (s *TransactionExporter) ExportSpendingInsights(ctx context.Context, opts InsightsOptions) (*SpendingInsights, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	month, _ := time.Parse(statementPeriodFormat, opts.Month)
+
+	account, err := s.fetchAccount(ctx, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	insights, err := s.api.FetchSpendingInsights(ctx, account.ID, opts.By, month.Year(), month.Month())
+	if err != nil {
+		return nil, fmt.Errorf("fetch spending insights: %w", err)
+	}
+
+	currency := insights.Currency
+	if currency == "" {
+		currency = account.Currency
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "fetched spending insights",
+		slog.String("account.id", account.ID.String()),
+		slog.String("insights.period", opts.Month),
+		slog.String("insights.by", string(opts.By)),
+		slog.Int("insights.count", len(insights.Breakdown)),
+	)
+
+	return &SpendingInsights{
+		AccountID: account.ID.String(),
+		Period:    opts.Month,
+		By:        opts.By,
+		Spent:     domain.MoneyFromMajorUnit(insights.TotalSpent, currency),
+		Received:  domain.MoneyFromMajorUnit(insights.TotalReceived, currency),
+		Net:       netAmount(insights.NetSpend, insights.NetDirection, currency),
+		Breakdown: lo.Map(insights.Breakdown, func(insight *starling.SpendingInsight, _ int) *SpendingInsight {
+			itemCurrency := insight.Currency
+			if itemCurrency == "" {
+				itemCurrency = currency
+			}
+
+			return &SpendingInsight{
+				Name:             insightName(insight, opts.By),
+				Spent:            domain.MoneyFromMajorUnit(insight.TotalSpent, itemCurrency),
+				Received:         domain.MoneyFromMajorUnit(insight.TotalReceived, itemCurrency),
+				Net:              netAmount(insight.NetSpend, insight.NetDirection, itemCurrency),
+				Percentage:       insight.Percentage,
+				TransactionCount: insight.TransactionCount,
+			}
+		}),
+	}, nil
+}
+
+// netAmount signs Starling's unsigned net spend, negative when money went out.
+func netAmount(amount float64, direction starling.Direction, currency string) domain.Money {
+	net := domain.MoneyFromMajorUnit(amount, currency)
+	if direction == starling.DirectionOUT {
+		net.MinorUnit = -net.MinorUnit
+	}
+
+	return net
+}
+
+func insightName(insight *starling.SpendingInsight, by starling.InsightsBreakdown) string {
+	switch by {
+	case starling.InsightsByCounterparty:
+		return insight.CounterPartyName
+	case starling.InsightsByCountry:
+		return insight.CountryCode
+	default:
+		return insight.SpendingCategory
+	}
+}
@@ -0,0 +1,115 @@
+package exporter_test
+
+import (
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportSpendingInsights(t *testing.T) {
+	t.Parallel()
+
+	accountID := starling.AccountID(uuid.New())
+
+	newClient := func() *StubClient {
+		return &StubClient{
+			Accounts: []*starling.Account{{ID: accountID, Currency: "GBP"}},
+			SpendingInsights: &starling.SpendingInsights{
+				Period:        "2025-03",
+				TotalSpent:    412.5,
+				TotalReceived: 2500,
+				NetSpend:      2087.5,
+				NetDirection:  starling.DirectionIN,
+				Breakdown: []*starling.SpendingInsight{
+					{
+						SpendingCategory: "GROCERIES",
+						CounterPartyName: "Corner Shop",
+						CountryCode:      "GB",
+						TotalSpent:       162.25,
+						TotalReceived:    12.5,
+						NetSpend:         149.75,
+						NetDirection:     starling.DirectionOUT,
+						Currency:         "GBP",
+						Percentage:       39.33,
+						TransactionCount: 6,
+					},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		opts             starlingexporter.InsightsOptions
+		expectedInsights *starlingexporter.SpendingInsights
+		expectedErr      string
+	}{
+		"returns insights by category": {
+			opts: starlingexporter.InsightsOptions{Month: "2025-03", By: starling.InsightsByCategory},
+			expectedInsights: &starlingexporter.SpendingInsights{
+				AccountID: accountID.String(),
+				Period:    "2025-03",
+				By:        starling.InsightsByCategory,
+				Spent:     domain.Money{MinorUnit: 41250, Currency: "GBP"},
+				Received:  domain.Money{MinorUnit: 250000, Currency: "GBP"},
+				Net:       domain.Money{MinorUnit: 208750, Currency: "GBP"},
+				Breakdown: []*starlingexporter.SpendingInsight{
+					{
+						Name:             "GROCERIES",
+						Spent:            domain.Money{MinorUnit: 16225, Currency: "GBP"},
+						Received:         domain.Money{MinorUnit: 1250, Currency: "GBP"},
+						Net:              domain.Money{MinorUnit: -14975, Currency: "GBP"},
+						Percentage:       39.33,
+						TransactionCount: 6,
+					},
+				},
+			},
+		},
+		"names breakdown by counterparty": {
+			opts: starlingexporter.InsightsOptions{Month: "2025-03", By: starling.InsightsByCounterparty},
+		},
+		"names breakdown by country": {
+			opts: starlingexporter.InsightsOptions{Month: "2025-03", By: starling.InsightsByCountry},
+		},
+		"returns error when month is invalid": {
+			opts:        starlingexporter.InsightsOptions{Month: "March", By: starling.InsightsByCategory},
+			expectedErr: "invalid options: Month: must be a month (YYYY-MM).",
+		},
+		"returns error when breakdown is unsupported": {
+			opts:        starlingexporter.InsightsOptions{Month: "2025-03", By: "merchant"},
+			expectedErr: "invalid options: By: must be category, counterparty or country.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			exporter, err := starlingexporter.New(newClient())
+			require.NoError(t, err)
+
+			insights, err := exporter.ExportSpendingInsights(t.Context(), test.opts)
+
+			if test.expectedErr != "" {
+				require.Nil(t, insights)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			if test.expectedInsights != nil {
+				require.Equal(t, test.expectedInsights, insights)
+			}
+
+			expectedNames := map[starling.InsightsBreakdown]string{
+				starling.InsightsByCategory:     "GROCERIES",
+				starling.InsightsByCounterparty: "Corner Shop",
+				starling.InsightsByCountry:      "GB",
+			}
+			require.Equal(t, expectedNames[test.opts.By], insights.Breakdown[0].Name)
+		})
+	}
+}
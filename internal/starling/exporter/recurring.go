@@ -0,0 +1,122 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+	"github.com/samber/lo"
+)
+
+var recurrenceUnits = map[string]string{
+	"DAILY":   "days",
+	"WEEKLY":  "weeks",
+	"MONTHLY": "months",
+	"YEARLY":  "years",
+}
+
+// ExportRecurringPayments returns the account's active standing orders and live direct debit mandates.
+func (s *TransactionExporter) ExportRecurringPayments(ctx context.Context, accountID string) ([]*domain.RecurringPayment, error) {
+	account, err := s.fetchAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	payees, err := s.api.FetchPayees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch payees: %w", err)
+	}
+
+	payeeNames := lo.SliceToMap(payees, func(payee *starling.Payee) (starling.PayeeID, string) {
+		return payee.ID, payee.Name
+	})
+
+	orders, err := s.api.FetchStandingOrders(ctx, account.ID, account.DefaultCategoryID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch standing orders: %w", err)
+	}
+
+	mandates, err := s.api.FetchDirectDebitMandates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch direct debit mandates: %w", err)
+	}
+
+	payments := make([]*domain.RecurringPayment, 0, len(orders)+len(mandates))
+	for _, order := range orders {
+		if order.CancelledAt != nil {
+			continue
+		}
+
+		payments = append(payments, &domain.RecurringPayment{
+			ID:              order.ID.String(),
+			Type:            domain.RecurringPaymentTypeStandingOrder,
+			Payee:           payeeNames[order.PayeeID],
+			Reference:       order.Reference,
+			Amount:          order.Amount,
+			Frequency:       recurrenceFrequency(order.Recurrence),
+			NextPaymentDate: dateOrNil(order.NextDate),
+			Category:        order.Category,
+			BankName:        Starling,
+		})
+	}
+
+	for _, mandate := range mandates {
+		if mandate.AccountID != account.ID || mandate.Status != starling.MandateStatusLive {
+			continue
+		}
+
+		payment := &domain.RecurringPayment{
+			ID:        mandate.ID.String(),
+			Type:      domain.RecurringPaymentTypeDirectDebit,
+			Payee:     mandate.OriginatorName,
+			Reference: mandate.Reference,
+			BankName:  Starling,
+		}
+
+		// Direct debits are collected by the payee, so Starling only knows about the last collection
+		if mandate.LastPayment != nil {
+			payment.Amount = mandate.LastPayment.Amount
+			payment.LastPaymentDate = dateOrNil(&mandate.LastPayment.Date)
+		}
+
+		payments = append(payments, payment)
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "exported recurring payments",
+		slog.String("account.id", account.ID.String()),
+		slog.Int("standing_order.total", len(orders)),
+		slog.Int("mandate.total", len(mandates)),
+		slog.Int("recurring_payment.total", len(payments)),
+	)
+
+	return payments, nil
+}
+
+// recurrenceFrequency describes the recurrence, e.g. "monthly" or "every 2 weeks".
+func recurrenceFrequency(recurrence starling.StandingOrderRecurrence) string {
+	if recurrence.Interval <= 1 {
+		return strings.ToLower(recurrence.Frequency)
+	}
+
+	unit, ok := recurrenceUnits[recurrence.Frequency]
+	if !ok {
+		unit = strings.ToLower(recurrence.Frequency)
+	}
+
+	return fmt.Sprintf("every %d %s", recurrence.Interval, unit)
+}
+
+func dateOrNil(date *starling.Date) *time.Time {
+	if date == nil || date.IsZero() {
+		return nil
+	}
+
+	t := date.Time
+
+	return &t
+}
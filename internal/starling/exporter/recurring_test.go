@@ -0,0 +1,118 @@
+package exporter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRecurringPayments(t *testing.T) {
+	t.Parallel()
+
+	accountID := starling.AccountID(uuid.New())
+	otherAccountID := starling.AccountID(uuid.New())
+	payeeID := starling.PayeeID(uuid.New())
+	orderID := starling.PaymentOrderID(uuid.New())
+	fortnightlyID := starling.PaymentOrderID(uuid.New())
+	mandateID := starling.MandateID(uuid.New())
+	nextDate := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	lastDate := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	cancelledAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	client := &StubClient{
+		Accounts: []*starling.Account{{ID: accountID}},
+		Payees: []*starling.Payee{
+			{ID: payeeID, Name: "Landlord Ltd"},
+		},
+		StandingOrders: []*starling.StandingOrder{
+			{
+				ID:         orderID,
+				Amount:     domain.Money{MinorUnit: 95000, Currency: "GBP"},
+				Reference:  "RENT",
+				PayeeID:    payeeID,
+				Recurrence: starling.StandingOrderRecurrence{Frequency: "MONTHLY", Interval: 1},
+				NextDate:   &starling.Date{Time: nextDate},
+				Category:   "BILLS_AND_SERVICES",
+			},
+			{
+				ID:         fortnightlyID,
+				Amount:     domain.Money{MinorUnit: 2000, Currency: "GBP"},
+				Reference:  "POCKET MONEY",
+				PayeeID:    starling.PayeeID(uuid.New()),
+				Recurrence: starling.StandingOrderRecurrence{Frequency: "WEEKLY", Interval: 2},
+			},
+			{
+				ID:          starling.PaymentOrderID(uuid.New()),
+				Reference:   "CANCELLED",
+				CancelledAt: &cancelledAt,
+			},
+		},
+		Mandates: []*starling.DirectDebitMandate{
+			{
+				ID:             mandateID,
+				Reference:      "ENERGY-0001",
+				Status:         starling.MandateStatusLive,
+				OriginatorName: "Energy Co",
+				AccountID:      accountID,
+				LastPayment: &starling.DirectDebitPayment{
+					Date:   starling.Date{Time: lastDate},
+					Amount: domain.Money{MinorUnit: 8500, Currency: "GBP"},
+				},
+			},
+			{
+				ID:        starling.MandateID(uuid.New()),
+				Reference: "CANCELLED",
+				Status:    "CANCELLED",
+				AccountID: accountID,
+			},
+			{
+				ID:        starling.MandateID(uuid.New()),
+				Reference: "OTHER ACCOUNT",
+				Status:    starling.MandateStatusLive,
+				AccountID: otherAccountID,
+			},
+		},
+	}
+
+	exporter, err := starlingexporter.New(client)
+	require.NoError(t, err)
+
+	payments, err := exporter.ExportRecurringPayments(t.Context(), accountID.String())
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.RecurringPayment{
+		{
+			ID:              orderID.String(),
+			Type:            domain.RecurringPaymentTypeStandingOrder,
+			Payee:           "Landlord Ltd",
+			Reference:       "RENT",
+			Amount:          domain.Money{MinorUnit: 95000, Currency: "GBP"},
+			Frequency:       "monthly",
+			NextPaymentDate: &nextDate,
+			Category:        "BILLS_AND_SERVICES",
+			BankName:        starlingexporter.Starling,
+		},
+		{
+			ID:        fortnightlyID.String(),
+			Type:      domain.RecurringPaymentTypeStandingOrder,
+			Reference: "POCKET MONEY",
+			Amount:    domain.Money{MinorUnit: 2000, Currency: "GBP"},
+			Frequency: "every 2 weeks",
+			BankName:  starlingexporter.Starling,
+		},
+		{
+			ID:              mandateID.String(),
+			Type:            domain.RecurringPaymentTypeDirectDebit,
+			Payee:           "Energy Co",
+			Reference:       "ENERGY-0001",
+			Amount:          domain.Money{MinorUnit: 8500, Currency: "GBP"},
+			LastPaymentDate: &lastDate,
+			BankName:        starlingexporter.Starling,
+		},
+	}, payments)
+}
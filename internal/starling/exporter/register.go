@@ -0,0 +1,51 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/oauth"
+	"github.com/HallyG/fingrab/internal/starling"
+	"github.com/HallyG/fingrab/internal/store"
+)
+
+const description = "Commands for interacting with the Starling API"
+
+func init() {
+	export.Register(ExportTypeStarling, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName(Starling),
+		export.WithDescription(description),
+		export.WithOAuth(func() oauth.Config {
+			return oauth.Config{
+				AuthURL:  "https://oauth.starlingbank.com/oauth/authorize",
+				TokenURL: "https://api.starlingbank.com/oauth2/token",
+			}
+		}),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+// NewFromOptions returns an exporter of the Starling API, which keeps its sync state in the data directory when
+// there is one.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+
+	var exporterOpts []Option
+	if opts.DataDir != "" {
+		s, err := store.New(opts.DataDir)
+		if err != nil {
+			return nil, fmt.Errorf("store: %w", err)
+		}
+
+		exporterOpts = append(exporterOpts, WithStore(s))
+	}
+
+	api := starling.New(client, api.WithAuthToken(opts.BearerAuthToken()))
+	return New(api, exporterOpts...)
+}
@@ -0,0 +1,158 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const statementPeriodFormat = "2006-01"
+
+type StatementOptions struct {
+	AccountID string                   // Defaults to the first account
+	From      string                   // First period to download (YYYY-MM), inclusive
+	To        string                   // Last period to download (YYYY-MM), inclusive. Defaults to the latest available period.
+	Format    starling.StatementFormat // pdf or csv
+	Dir       string                   // Directory the statements are written to
+}
+
+func (o StatementOptions) Validate(ctx context.Context) error {
+	period := validation.By(func(value any) error {
+		str, _ := value.(string)
+		if str == "" {
+			return nil
+		}
+
+		if _, err := time.Parse(statementPeriodFormat, str); err != nil {
+			return validation.NewError("validation_period", "must be a month (YYYY-MM)")
+		}
+
+		return nil
+	})
+
+	return validation.ValidateStructWithContext(ctx, &o,
+		validation.Field(&o.From, validation.Required.Error("is required"), period),
+		validation.Field(&o.To, period, validation.By(func(value any) error {
+			if to, _ := value.(string); to != "" && to < o.From {
+				return validation.NewError("validation_period_order", "must not be before From")
+			}
+
+			return nil
+		})),
+		validation.Field(&o.Format, validation.Required.Error("is required"), validation.In(starling.StatementFormatPDF, starling.StatementFormatCSV).Error("must be pdf or csv")),
+		validation.Field(&o.Dir, validation.Required.Error("is required")),
+	)
+}
+
+// Statement is a statement file for a single period.
+type Statement struct {
+	Period  string
+	Path    string
+	Skipped bool // The file already existed, so wasn't downloaded again
+}
+
+// DownloadStatements downloads the account's statements for each complete period in the range to the directory,
+// skipping files which are already present. Partial periods (the current month) are never downloaded as the
+// statement would be incomplete.
+func (s *TransactionExporter) DownloadStatements(ctx context.Context, opts StatementOptions) ([]*Statement, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	account, err := s.fetchAccount(ctx, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	periods, err := s.api.FetchStatementPeriods(ctx, account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch statement periods: %w", err)
+	}
+
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create statement directory: %w", err)
+	}
+
+	statements := make([]*Statement, 0, len(periods))
+	for _, period := range periods {
+		if period.Period < opts.From || (opts.To != "" && period.Period > opts.To) {
+			continue
+		}
+
+		if period.Partial {
+			log.FromContext(ctx).InfoContext(ctx, "skipping partial statement period",
+				slog.String("statement.period", period.Period),
+			)
+
+			continue
+		}
+
+		statement, err := s.downloadStatement(ctx, account.ID, period.Period, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, statement)
+	}
+
+	return statements, nil
+}
+
+func (s *TransactionExporter) downloadStatement(ctx context.Context, accountID starling.AccountID, period string, opts StatementOptions) (*Statement, error) {
+	statement := &Statement{
+		Period: period,
+		Path:   filepath.Join(opts.Dir, fmt.Sprintf("starling-%s-%s.%s", accountID, period, opts.Format)),
+	}
+
+	_, err := os.Stat(statement.Path)
+	if err == nil {
+		statement.Skipped = true
+		return statement, nil
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("stat statement %s: %w", period, err)
+	}
+
+	data, err := s.api.DownloadStatement(ctx, accountID, period, opts.Format)
+	if err != nil {
+		return nil, fmt.Errorf("download statement %s: %w", period, err)
+	}
+
+	// Write to a temporary file first so an interrupted download isn't mistaken for an existing statement
+	tmp, err := os.CreateTemp(opts.Dir, ".statement-*")
+	if err != nil {
+		return nil, fmt.Errorf("create statement file: %w", err)
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return nil, fmt.Errorf("write statement %s: %w", period, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("write statement %s: %w", period, err)
+	}
+
+	if err := os.Rename(tmp.Name(), statement.Path); err != nil {
+		return nil, fmt.Errorf("save statement %s: %w", period, err)
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "downloaded statement",
+		slog.String("statement.period", period),
+		slog.String("statement.path", statement.Path),
+		slog.Int("statement.bytes", len(data)),
+	)
+
+	return statement, nil
+}
@@ -0,0 +1,114 @@
+package exporter_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadStatements(t *testing.T) {
+	t.Parallel()
+
+	accountID := starling.AccountID(uuid.New())
+
+	setup := func(t *testing.T) *starlingexporter.TransactionExporter {
+		t.Helper()
+
+		client := &StubClient{
+			Accounts: []*starling.Account{{ID: accountID}},
+			StatementPeriods: []*starling.StatementPeriod{
+				{Period: "2024-12"},
+				{Period: "2025-01"},
+				{Period: "2025-02"},
+				{Period: "2025-03", Partial: true},
+			},
+			Statements: map[string][]byte{
+				"2024-12": []byte("december"),
+				"2025-01": []byte("january"),
+				"2025-02": []byte("february"),
+			},
+		}
+
+		exporter, err := starlingexporter.New(client)
+		require.NoError(t, err)
+
+		return exporter
+	}
+
+	statementPath := func(dir string, period string) string {
+		return filepath.Join(dir, "starling-"+accountID.String()+"-"+period+".csv")
+	}
+
+	t.Run("downloads complete periods in range and skips existing files", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(statementPath(dir, "2025-01"), []byte("existing"), 0o600))
+
+		statements, err := setup(t).DownloadStatements(t.Context(), starlingexporter.StatementOptions{
+			From:   "2025-01",
+			To:     "2025-03",
+			Format: starling.StatementFormatCSV,
+			Dir:    dir,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []*starlingexporter.Statement{
+			{Period: "2025-01", Path: statementPath(dir, "2025-01"), Skipped: true},
+			{Period: "2025-02", Path: statementPath(dir, "2025-02")},
+		}, statements)
+
+		existing, err := os.ReadFile(statementPath(dir, "2025-01"))
+		require.NoError(t, err)
+		require.Equal(t, "existing", string(existing))
+
+		downloaded, err := os.ReadFile(statementPath(dir, "2025-02"))
+		require.NoError(t, err)
+		require.Equal(t, "february", string(downloaded))
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+	})
+
+	t.Run("returns error when options are invalid", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]struct {
+			opts           starlingexporter.StatementOptions
+			expectedErrMsg string
+		}{
+			"missing from": {
+				opts:           starlingexporter.StatementOptions{Format: starling.StatementFormatPDF, Dir: "statements"},
+				expectedErrMsg: "invalid options: From: is required.",
+			},
+			"invalid period": {
+				opts:           starlingexporter.StatementOptions{From: "2025-1-1", Format: starling.StatementFormatPDF, Dir: "statements"},
+				expectedErrMsg: "invalid options: From: must be a month (YYYY-MM).",
+			},
+			"to before from": {
+				opts:           starlingexporter.StatementOptions{From: "2025-02", To: "2025-01", Format: starling.StatementFormatPDF, Dir: "statements"},
+				expectedErrMsg: "invalid options: To: must not be before From.",
+			},
+			"unsupported format": {
+				opts:           starlingexporter.StatementOptions{From: "2025-01", Format: "xlsx", Dir: "statements"},
+				expectedErrMsg: "invalid options: Format: must be pdf or csv.",
+			},
+		}
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				statements, err := setup(t).DownloadStatements(t.Context(), test.opts)
+
+				require.Nil(t, statements)
+				require.EqualError(t, err, test.expectedErrMsg)
+			})
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+)
+
+const (
+	syncKeyPrefix = "starling-sync-"
+	// Feed items are settled, reversed or refunded within weeks, so ones transacted longer before the high-water mark
+	// than this aren't remembered, and a later change to one is returned as new
+	syncAmendmentWindow = 90 * 24 * time.Hour
+)
+
+type SyncOptions struct {
+	AccountID string    // Account selector, see selectAccount. "all" syncs every account and space.
+	Since     time.Time // Where to start a feed that hasn't been synced before
+	Audit     bool      // Include declined and reversed transactions, and account checks, in the new transactions
+}
+
+// SyncResult is the feed items created or changed since the previous sync. Amended transactions (e.g. settled,
+// reversed or with an edited note) were returned by an earlier sync, so are kept apart from new ones for downstream
+// tools to update rather than import twice.
+type SyncResult struct {
+	New     []*domain.Transaction
+	Amended []*domain.Transaction
+
+	cursors []*syncCursor
+}
+
+// syncCursor is the persisted high-water mark of a single account category's feed.
+type syncCursor struct {
+	AccountID     string               `json:"accountId"`
+	CategoryID    string               `json:"categoryId"`
+	HighWaterMark time.Time            `json:"highWaterMark"` // The latest change returned by the previous sync
+	Seen          map[string]time.Time `json:"seen"`          // When the feed items returned by previous syncs were transacted, by UID
+}
+
+// Sync fetches the feed items created or changed since each feed's stored high-water mark, or opts.Since for a feed
+// that hasn't been synced before. The marks aren't advanced until CommitSync is called with the result, so a sync
+// whose transactions failed to be written is fetched again next time.
+func (s *TransactionExporter) Sync(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	if s.store == nil {
+		return nil, errors.New("sync store is required")
+	}
+
+	accounts, err := s.fetchAccounts(ctx, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	allAccounts := strings.EqualFold(strings.TrimSpace(opts.AccountID), export.AllAccounts)
+	result := &SyncResult{
+		New:     make([]*domain.Transaction, 0),
+		Amended: make([]*domain.Transaction, 0),
+	}
+
+	for _, account := range accounts {
+		categories, err := s.selectCategories(ctx, account, "", allAccounts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, category := range categories {
+			cursor, err := s.syncCategory(ctx, account, category, opts, result)
+			if err != nil {
+				return nil, err
+			}
+
+			result.cursors = append(result.cursors, cursor)
+		}
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "synced transactions",
+		slog.Int("transaction.new", len(result.New)),
+		slog.Int("transaction.amended", len(result.Amended)),
+	)
+
+	return result, nil
+}
+
+// syncCategory appends the category's changes to the result and returns the category's new high-water mark.
+func (s *TransactionExporter) syncCategory(ctx context.Context, account *starling.Account, category *category, opts SyncOptions, result *SyncResult) (*syncCursor, error) {
+	cursor := &syncCursor{
+		AccountID:  account.ID.String(),
+		CategoryID: category.ID.String(),
+	}
+	found, err := s.store.Load(cursor.key(), cursor)
+	if err != nil {
+		return nil, fmt.Errorf("load sync state: %w", err)
+	}
+
+	if !found {
+		if opts.Since.IsZero() {
+			return nil, fmt.Errorf("%s hasn't been synced before, a start time is required", category.Name)
+		}
+
+		cursor.HighWaterMark = opts.Since
+	}
+
+	since := cursor.HighWaterMark
+	if cursor.Seen == nil {
+		cursor.Seen = make(map[string]time.Time)
+	}
+
+	log.FromContext(ctx).InfoContext(ctx, "fetching feed changes",
+		slog.String("account.id", account.ID.String()),
+		slog.String("account.category.id", category.ID.String()),
+		slog.Time("since", since),
+	)
+
+	items, err := s.api.FetchFeedChangesSince(ctx, account.ID, category.ID, since)
+	if err != nil {
+		return nil, fmt.Errorf("fetch feed changes: %w", err)
+	}
+
+	for _, item := range items {
+		changedAt := item.UpdatedAt
+		if changedAt.IsZero() {
+			changedAt = item.TransactedAt
+		}
+
+		// Changes at the mark itself were returned by the previous sync
+		if found && !changedAt.After(since) {
+			continue
+		}
+
+		if changedAt.After(cursor.HighWaterMark) {
+			cursor.HighWaterMark = changedAt
+		}
+
+		transaction := toAccountTransaction(item, account, category)
+
+		// An item returned by a previous sync has since been amended, however long ago it was transacted
+		if _, ok := cursor.Seen[item.ID.String()]; ok {
+			result.Amended = append(result.Amended, transaction)
+			continue
+		}
+
+		if opts.Audit || !isAuditOnly(item.Status) {
+			result.New = append(result.New, transaction)
+			cursor.Seen[item.ID.String()] = item.TransactedAt
+		}
+	}
+
+	for id, transactedAt := range cursor.Seen {
+		if cursor.HighWaterMark.Sub(transactedAt) > syncAmendmentWindow {
+			delete(cursor.Seen, id)
+		}
+	}
+
+	return cursor, nil
+}
+
+// CommitSync persists the result's high-water marks, so the next sync only returns later changes.
+// It should be called once the result's transactions have been written.
+func (s *TransactionExporter) CommitSync(result *SyncResult) error {
+	if s.store == nil {
+		return errors.New("sync store is required")
+	}
+
+	for _, cursor := range result.cursors {
+		if err := s.store.Save(cursor.key(), cursor); err != nil {
+			return fmt.Errorf("save sync state: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *syncCursor) key() string {
+	return syncKeyPrefix + c.AccountID + "-" + c.CategoryID
+}
@@ -0,0 +1,182 @@
+package exporter_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/google/uuid"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	accountID := starling.AccountID(uuid.New())
+	categoryID := starling.CategoryID(uuid.New())
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	firstSync := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	item := func(reference string, status starling.Status, transactedAt time.Time, updatedAt time.Time) *starling.FeedItem {
+		return &starling.FeedItem{
+			ID:           starling.FeedItemID(uuid.New()),
+			Description:  reference,
+			Status:       status,
+			Direction:    starling.DirectionOUT,
+			Amount:       domain.Money{MinorUnit: 100, Currency: "GBP"},
+			TransactedAt: transactedAt,
+			UpdatedAt:    updatedAt,
+		}
+	}
+
+	references := func(transactions []*domain.Transaction) []string {
+		return lo.Map(transactions, func(txn *domain.Transaction, _ int) string { return txn.Reference })
+	}
+
+	setup := func(t *testing.T, client *StubClient) *starlingexporter.TransactionExporter {
+		t.Helper()
+
+		s, err := store.New(t.TempDir())
+		require.NoError(t, err)
+
+		client.Accounts = []*starling.Account{{ID: accountID, DefaultCategoryID: categoryID, Name: "Personal"}}
+		exporter, err := starlingexporter.New(client, starlingexporter.WithStore(s))
+		require.NoError(t, err)
+
+		return exporter
+	}
+
+	t.Run("separates amended transactions from new ones", func(t *testing.T) {
+		t.Parallel()
+
+		pending := item("coffee", starling.StatusPending, start.Add(24*time.Hour), firstSync)
+		client := &StubClient{
+			Transactions: []*starling.FeedItem{
+				pending,
+				item("rent", starling.StatusSettled, start.Add(48*time.Hour), start.Add(48*time.Hour)),
+				item("declined", starling.StatusDeclined, start.Add(72*time.Hour), start.Add(72*time.Hour)),
+			},
+		}
+		exporter := setup(t, client)
+
+		result, err := exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+		require.NoError(t, err)
+		require.Equal(t, []string{"coffee", "rent"}, references(result.New))
+		require.Empty(t, result.Amended)
+		require.NoError(t, exporter.CommitSync(result))
+
+		// The pending item settles, a new item arrives and one dated before the first sync is only now posted
+		settled := *pending
+		settled.Status = starling.StatusSettled
+		settled.UpdatedAt = firstSync.Add(time.Hour)
+		client.Transactions = []*starling.FeedItem{
+			&settled,
+			item("lunch", starling.StatusSettled, firstSync.Add(2*time.Hour), firstSync.Add(2*time.Hour)),
+			item("refund", starling.StatusSettled, start.Add(time.Hour), firstSync.Add(3*time.Hour)),
+		}
+
+		result, err = exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{start, firstSync}, client.RequestedSince)
+		require.Equal(t, []string{"lunch", "refund"}, references(result.New))
+		require.Equal(t, []string{"coffee"}, references(result.Amended))
+		require.Equal(t, domain.TransactionStatusSettled, result.Amended[0].Status)
+		require.Equal(t, "Personal", result.Amended[0].Account)
+	})
+
+	t.Run("forgets transactions from before the amendment window", func(t *testing.T) {
+		t.Parallel()
+
+		old := item("coffee", starling.StatusSettled, start.Add(time.Hour), start.Add(time.Hour))
+		client := &StubClient{Transactions: []*starling.FeedItem{old}}
+		exporter := setup(t, client)
+
+		result, err := exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+		require.NoError(t, err)
+		require.NoError(t, exporter.CommitSync(result))
+
+		// Months later, a new item moves the high-water mark past the window of the first
+		later := start.Add(100 * 24 * time.Hour)
+		client.Transactions = []*starling.FeedItem{item("rent", starling.StatusSettled, later, later)}
+		result, err = exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+		require.NoError(t, err)
+		require.NoError(t, exporter.CommitSync(result))
+
+		amended := *old
+		amended.UpdatedAt = later.Add(time.Hour)
+		client.Transactions = []*starling.FeedItem{&amended}
+
+		result, err = exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+		require.NoError(t, err)
+		require.Equal(t, []string{"coffee"}, references(result.New))
+		require.Empty(t, result.Amended)
+	})
+
+	t.Run("includes declined transactions in audit mode", func(t *testing.T) {
+		t.Parallel()
+
+		client := &StubClient{
+			Transactions: []*starling.FeedItem{
+				item("declined", starling.StatusDeclined, start.Add(time.Hour), start.Add(time.Hour)),
+			},
+		}
+
+		result, err := setup(t, client).Sync(t.Context(), starlingexporter.SyncOptions{Since: start, Audit: true})
+		require.NoError(t, err)
+		require.Equal(t, []string{"declined"}, references(result.New))
+	})
+
+	t.Run("does not advance the high-water mark until committed", func(t *testing.T) {
+		t.Parallel()
+
+		client := &StubClient{
+			Transactions: []*starling.FeedItem{
+				item("rent", starling.StatusSettled, start.Add(time.Hour), start.Add(time.Hour)),
+			},
+		}
+		exporter := setup(t, client)
+
+		_, err := exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+		require.NoError(t, err)
+
+		result, err := exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{start, start}, client.RequestedSince)
+		require.Equal(t, []string{"rent"}, references(result.New))
+	})
+
+	t.Run("returns error when a feed hasn't been synced and there's no start time", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := setup(t, &StubClient{}).Sync(t.Context(), starlingexporter.SyncOptions{})
+
+		require.Nil(t, result)
+		require.EqualError(t, err, "Personal hasn't been synced before, a start time is required")
+	})
+
+	t.Run("returns error when changes cannot be fetched", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := setup(t, &StubClient{FetchTxnsErr: errors.New("boom")}).Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+
+		require.Nil(t, result)
+		require.EqualError(t, err, "fetch feed changes: boom")
+	})
+
+	t.Run("returns error without a store", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := starlingexporter.New(&StubClient{})
+		require.NoError(t, err)
+
+		result, err := exporter.Sync(t.Context(), starlingexporter.SyncOptions{Since: start})
+
+		require.Nil(t, result)
+		require.EqualError(t, err, "sync store is required")
+	})
+}
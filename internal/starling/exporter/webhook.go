@@ -0,0 +1,211 @@
+package exporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/starling"
+	"github.com/HallyG/fingrab/internal/store"
+)
+
+const (
+	defaultWebhookTolerance = 5 * time.Minute
+	maxWebhookBodySize      = 1 << 20
+	// Feed items are remembered for long enough to cover later events for them, e.g. an edited note
+	webhookRetention  = 30 * 24 * time.Hour
+	webhookWrittenKey = "starling-webhook-written"
+)
+
+type WebhookOption func(*WebhookHandler)
+
+// WithTolerance sets how far an event's timestamp may be from now before the delivery is rejected as a replay.
+func WithTolerance(tolerance time.Duration) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.tolerance = tolerance
+	}
+}
+
+// WithWebhookStore keeps the feed items written in the store, so they aren't written again after a restart.
+func WithWebhookStore(s *store.Store) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.store = s
+	}
+}
+
+// WithClock sets the function used to get the current time.
+func WithClock(now func() time.Time) WebhookOption {
+	return func(h *WebhookHandler) {
+		h.now = now
+	}
+}
+
+// WebhookHandler receives Starling feed item webhook deliveries and writes each settled feed item, as a
+// transaction, to the formatter. Deliveries are rejected unless their signature is valid and their event is recent,
+// and an event or feed item is never written twice, so replayed and retried deliveries are acknowledged but ignored.
+// Feed items are remembered for 30 days after they're written, and only until the handler stops unless they're kept
+// in a store, after which a later event for one writes it again.
+type WebhookHandler struct {
+	verifier  starling.SignatureVerifier
+	formatter format.Formatter
+	tolerance time.Duration
+	now       func() time.Time
+	store     *store.Store
+
+	mu      sync.Mutex
+	events  map[string]time.Time // Event IDs seen within the tolerance, and when they happened
+	written map[string]time.Time // UIDs of the feed items written within the retention, and when they were written
+}
+
+func NewWebhookHandler(verifier starling.SignatureVerifier, formatter format.Formatter, opts ...WebhookOption) (*WebhookHandler, error) {
+	if verifier == nil {
+		return nil, errors.New("signature verifier is required")
+	}
+
+	if formatter == nil {
+		return nil, errors.New("formatter is required")
+	}
+
+	h := &WebhookHandler{
+		verifier:  verifier,
+		formatter: formatter,
+		tolerance: defaultWebhookTolerance,
+		now:       time.Now,
+		events:    make(map[string]time.Time),
+		written:   make(map[string]time.Time),
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(h)
+	}
+
+	if h.store != nil {
+		if _, err := h.store.Load(webhookWrittenKey, &h.written); err != nil {
+			return nil, fmt.Errorf("load written feed items: %w", err)
+		}
+	}
+
+	return h, nil
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.FromContext(ctx)
+
+	if r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize))
+	if err != nil {
+		http.Error(w, "read body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verifier.Verify(body, r.Header.Get(starling.SignatureHeader)); err != nil {
+		logger.WarnContext(ctx, "rejected webhook delivery", slog.Any("error", err))
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var event starling.WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.ID == "" || event.Content == nil {
+		http.Error(w, "invalid feed item event", http.StatusBadRequest)
+		return
+	}
+
+	logger = logger.With(
+		slog.String("event.id", event.ID),
+		slog.String("feed_item.id", event.Content.ID.String()),
+	)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	h.pruneEvents(now)
+	h.pruneWritten(now)
+
+	age := now.Sub(event.Timestamp)
+	if age > h.tolerance || age < -h.tolerance {
+		logger.WarnContext(ctx, "rejected stale webhook delivery", slog.Duration("event.age", age))
+		http.Error(w, "event timestamp outside tolerance", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := h.events[event.ID]; ok {
+		logger.InfoContext(ctx, "ignored duplicate webhook delivery")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if err := h.write(event.Content, now); err != nil {
+		logger.ErrorContext(ctx, "failed to write transaction", slog.Any("error", err))
+		http.Error(w, "write transaction", http.StatusInternalServerError)
+		return
+	}
+
+	// Only record the event once handled, so a delivery that failed is written when Starling retries it
+	h.events[event.ID] = event.Timestamp
+
+	logger.InfoContext(ctx, "handled webhook delivery", slog.String("feed_item.status", string(event.Content.Status)))
+	w.WriteHeader(http.StatusOK)
+}
+
+// write appends the feed item to the formatter, unless it hasn't settled or has already been written.
+func (h *WebhookHandler) write(item *starling.FeedItem, now time.Time) error {
+	if item.Status != starling.StatusSettled {
+		return nil
+	}
+
+	if _, ok := h.written[item.ID.String()]; !ok {
+		if err := h.formatter.WriteTransaction(toDomainTransaction(item)); err != nil {
+			return err
+		}
+
+		if err := h.formatter.Flush(); err != nil {
+			return err
+		}
+
+		h.written[item.ID.String()] = now
+	}
+
+	// The item is remembered even if saving fails, so a retried delivery saves it without writing it again
+	if h.store != nil {
+		if err := h.store.Save(webhookWrittenKey, h.written); err != nil {
+			return fmt.Errorf("save written feed items: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// pruneEvents forgets events outside the tolerance, as they would be rejected as stale anyway.
+func (h *WebhookHandler) pruneEvents(now time.Time) {
+	for id, timestamp := range h.events {
+		if now.Sub(timestamp) > h.tolerance {
+			delete(h.events, id)
+		}
+	}
+}
+
+// pruneWritten forgets feed items written before the retention.
+func (h *WebhookHandler) pruneWritten(now time.Time) {
+	for id, writtenAt := range h.written {
+		if now.Sub(writtenAt) > webhookRetention {
+			delete(h.written, id)
+		}
+	}
+}
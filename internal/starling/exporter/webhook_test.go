@@ -0,0 +1,255 @@
+package exporter_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/format"
+	"github.com/HallyG/fingrab/internal/starling"
+	starlingexporter "github.com/HallyG/fingrab/internal/starling/exporter"
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/HallyG/fingrab/internal/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	webhookSecret = "webhook-secret"
+	settledRow    = "11221122-1122-1122-1122-112211221122,2025-03-10T11:59:30Z,Starling,,Corner Cafe,EATING_OUT,-12.50,GBP,settled,,Lunch,,\n"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	t.Parallel()
+
+	eventTime := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	settled := testhelper.LoadTestDataFile(t, "webhook-feed-item.json")
+	pending := testhelper.LoadTestDataFile(t, "webhook-feed-item-pending.json")
+
+	secretSignature := func(body []byte) string {
+		hash := sha512.Sum512(append([]byte(webhookSecret), body...))
+		return base64.StdEncoding.EncodeToString(hash[:])
+	}
+
+	type delivery struct {
+		method         string
+		body           []byte
+		signature      string
+		expectedStatus int
+	}
+
+	tests := map[string]struct {
+		now        time.Time
+		deliveries []delivery
+		expected   string
+	}{
+		"writes settled feed item": {
+			now: eventTime.Add(time.Minute),
+			deliveries: []delivery{
+				{body: settled, signature: secretSignature(settled), expectedStatus: http.StatusOK},
+			},
+			expected: settledRow,
+		},
+		"acknowledges but ignores pending feed item": {
+			now: eventTime,
+			deliveries: []delivery{
+				{body: pending, signature: secretSignature(pending), expectedStatus: http.StatusOK},
+			},
+		},
+		"ignores replayed delivery": {
+			now: eventTime,
+			deliveries: []delivery{
+				{body: settled, signature: secretSignature(settled), expectedStatus: http.StatusOK},
+				{body: settled, signature: secretSignature(settled), expectedStatus: http.StatusOK},
+			},
+			expected: settledRow,
+		},
+		"rejects stale delivery": {
+			now: eventTime.Add(10 * time.Minute),
+			deliveries: []delivery{
+				{body: settled, signature: secretSignature(settled), expectedStatus: http.StatusBadRequest},
+			},
+		},
+		"rejects delivery from the future": {
+			now: eventTime.Add(-10 * time.Minute),
+			deliveries: []delivery{
+				{body: settled, signature: secretSignature(settled), expectedStatus: http.StatusBadRequest},
+			},
+		},
+		"rejects invalid signature": {
+			now: eventTime,
+			deliveries: []delivery{
+				{body: settled, signature: secretSignature(pending), expectedStatus: http.StatusUnauthorized},
+			},
+		},
+		"rejects missing signature": {
+			now: eventTime,
+			deliveries: []delivery{
+				{body: settled, expectedStatus: http.StatusUnauthorized},
+			},
+		},
+		"rejects signed body that isn't a feed item event": {
+			now: eventTime,
+			deliveries: []delivery{
+				{body: []byte(`{}`), signature: secretSignature([]byte(`{}`)), expectedStatus: http.StatusBadRequest},
+			},
+		},
+		"rejects non POST requests": {
+			now: eventTime,
+			deliveries: []delivery{
+				{method: http.MethodGet, expectedStatus: http.StatusMethodNotAllowed},
+			},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			output := &bytes.Buffer{}
+			formatter, err := format.NewFormatter(format.FormatTypeDetailed, output)
+			require.NoError(t, err)
+
+			handler, err := starlingexporter.NewWebhookHandler(
+				starling.NewSharedSecretVerifier(webhookSecret),
+				formatter,
+				starlingexporter.WithClock(func() time.Time { return test.now }),
+			)
+			require.NoError(t, err)
+
+			for i, d := range test.deliveries {
+				method := d.method
+				if method == "" {
+					method = http.MethodPost
+				}
+
+				req := httptest.NewRequestWithContext(t.Context(), method, "/", bytes.NewReader(d.body))
+				if d.signature != "" {
+					req.Header.Set(starling.SignatureHeader, d.signature)
+				}
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				require.Equal(t, d.expectedStatus, rec.Code, "delivery %d: %s", i+1, rec.Body.String())
+			}
+
+			require.Equal(t, test.expected, output.String())
+		})
+	}
+
+	t.Run("verifies public key signatures", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		require.NoError(t, err)
+
+		verifier, err := starling.NewPublicKeyVerifier(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+		require.NoError(t, err)
+
+		digest := sha512.Sum512(settled)
+		sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA512, digest[:])
+		require.NoError(t, err)
+
+		output := &bytes.Buffer{}
+		formatter, err := format.NewFormatter(format.FormatTypeDetailed, output)
+		require.NoError(t, err)
+
+		handler, err := starlingexporter.NewWebhookHandler(verifier, formatter,
+			starlingexporter.WithClock(func() time.Time { return eventTime }),
+		)
+		require.NoError(t, err)
+
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/", bytes.NewReader(settled))
+		req.Header.Set(starling.SignatureHeader, base64.StdEncoding.EncodeToString(sig))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, settledRow, output.String())
+	})
+
+	deliver := func(t *testing.T, handler http.Handler, body []byte) {
+		t.Helper()
+
+		req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/", bytes.NewReader(body))
+		req.Header.Set(starling.SignatureHeader, secretSignature(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	newHandler := func(t *testing.T, output *bytes.Buffer, s *store.Store, now *time.Time) *starlingexporter.WebhookHandler {
+		t.Helper()
+
+		formatter, err := format.NewFormatter(format.FormatTypeDetailed, output)
+		require.NoError(t, err)
+
+		handler, err := starlingexporter.NewWebhookHandler(
+			starling.NewSharedSecretVerifier(webhookSecret),
+			formatter,
+			starlingexporter.WithWebhookStore(s),
+			starlingexporter.WithClock(func() time.Time { return *now }),
+		)
+		require.NoError(t, err)
+
+		return handler
+	}
+
+	t.Run("remembers written feed items after a restart", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := store.New(t.TempDir())
+		require.NoError(t, err)
+
+		now := eventTime
+		before := &bytes.Buffer{}
+		deliver(t, newHandler(t, before, s, &now), settled)
+
+		after := &bytes.Buffer{}
+		deliver(t, newHandler(t, after, s, &now), settled)
+
+		require.Equal(t, settledRow, before.String())
+		require.Empty(t, after.String())
+	})
+
+	t.Run("writes a feed item again after the retention", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := store.New(t.TempDir())
+		require.NoError(t, err)
+
+		now := eventTime
+		output := &bytes.Buffer{}
+		handler := newHandler(t, output, s, &now)
+		deliver(t, handler, settled)
+
+		// A later event for the same feed item, e.g. its note being edited
+		now = eventTime.Add(31 * 24 * time.Hour)
+		later := bytes.ReplaceAll(settled, []byte("2025-03-10T12:00:00.000Z"), []byte(now.Format(time.RFC3339)))
+		later = bytes.ReplaceAll(later, []byte("a6b7c8d9-0000-4000-0000-000000000001"), []byte("a6b7c8d9-0000-4000-0000-000000000002"))
+		deliver(t, handler, later)
+
+		require.Equal(t, settledRow+settledRow, output.String())
+	})
+
+	t.Run("returns error when verifier is missing", func(t *testing.T) {
+		t.Parallel()
+
+		handler, err := starlingexporter.NewWebhookHandler(nil, nil)
+
+		require.Nil(t, handler)
+		require.EqualError(t, err, "signature verifier is required")
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/HallyG/fingrab/internal/api"
@@ -14,11 +16,26 @@ import (
 )
 
 const (
-	prodAPI              = "https://api.starlingbank.com"
-	getAccountsRoute     = "/api/v2/accounts"
-	getTransactionsRoute = "/api/v2/feed/account/%s/category/%s/transactions-between"
-	getFeedItemRoute     = "/api/v2/feed/account/%s/category/%s/%s"
-	getSavingsRoute      = "/api/v2/account/%s/savings-goals"
+	prodAPI                  = "https://api.starlingbank.com"
+	getAccountsRoute         = "/api/v2/accounts"
+	getTransactionsRoute     = "/api/v2/feed/account/%s/category/%s/transactions-between"
+	getFeedItemRoute         = "/api/v2/feed/account/%s/category/%s/%s"
+	getFeedChangesRoute      = "/api/v2/feed/account/%s/category/%s"
+	putUserNoteRoute         = "/api/v2/feed/account/%s/category/%s/%s/user-note"
+	putSpendingCategoryRoute = "/api/v2/feed/account/%s/category/%s/%s/spending-category"
+	getSavingsRoute          = "/api/v2/account/%s/savings-goals"
+	getSpacesRoute           = "/api/v2/account/%s/spaces"
+	getBalanceRoute          = "/api/v2/accounts/%s/balance"
+	getStatementsRoute       = "/api/v2/accounts/%s/statement/available-periods"
+	getStatementRoute        = "/api/v2/accounts/%s/statement/download"
+	getPayeesRoute           = "/api/v2/payees"
+	getStandingOrdersRoute   = "/api/v2/payments/local/account/%s/category/%s/standing-orders"
+	getMandatesRoute         = "/api/v2/direct-debit/mandates"
+	getSpendingInsightsRoute = "/api/v2/accounts/%s/spending-insights/%s"
+	getIdentifiersRoute      = "/api/v2/accounts/%s/identifiers"
+	getAccountHolderRoute    = "/api/v2/account-holder"
+	getHolderNameRoute       = "/api/v2/account-holder/name"
+	getJointHolderRoute      = "/api/v2/account-holder/joint"
 )
 
 var _ Client = (*client)(nil)
@@ -27,8 +44,23 @@ type (
 	Client interface {
 		FetchTransactionsSince(ctx context.Context, opts FetchTransactionOptions) ([]*FeedItem, error)
 		FetchFeedItem(ctx context.Context, accountID AccountID, categoryID CategoryID, feedItemID FeedItemID) (*FeedItem, error)
+		FetchFeedChangesSince(ctx context.Context, accountID AccountID, categoryID CategoryID, since time.Time) ([]*FeedItem, error)
 		FetchAccounts(ctx context.Context) ([]*Account, error)
 		FetchSavingsGoals(ctx context.Context, accountID AccountID) ([]*SavingsGoal, error)
+		FetchSpendingSpaces(ctx context.Context, accountID AccountID) ([]*SpendingSpace, error)
+		FetchBalance(ctx context.Context, accountID AccountID) (*Balance, error)
+		FetchStatementPeriods(ctx context.Context, accountID AccountID) ([]*StatementPeriod, error)
+		DownloadStatement(ctx context.Context, accountID AccountID, period string, format StatementFormat) ([]byte, error)
+		UpdateUserNote(ctx context.Context, accountID AccountID, categoryID CategoryID, feedItemID FeedItemID, note string) error
+		UpdateSpendingCategory(ctx context.Context, accountID AccountID, categoryID CategoryID, feedItemID FeedItemID, category string) error
+		FetchPayees(ctx context.Context) ([]*Payee, error)
+		FetchStandingOrders(ctx context.Context, accountID AccountID, categoryID CategoryID) ([]*StandingOrder, error)
+		FetchDirectDebitMandates(ctx context.Context) ([]*DirectDebitMandate, error)
+		FetchSpendingInsights(ctx context.Context, accountID AccountID, breakdown InsightsBreakdown, year int, month time.Month) (*SpendingInsights, error)
+		FetchAccountIdentifiers(ctx context.Context, accountID AccountID) (*AccountIdentifiers, error)
+		FetchAccountHolder(ctx context.Context) (*AccountHolder, error)
+		FetchAccountHolderName(ctx context.Context) (string, error)
+		FetchJointAccountHolder(ctx context.Context) (*JointAccountHolder, error)
 	}
 	client struct {
 		api *resty.Client
@@ -68,6 +100,34 @@ func (c *client) FetchFeedItem(ctx context.Context, accountID AccountID, categor
 	return result, nil
 }
 
+// UpdateUserNote replaces the feed item's user note.
+func (c *client) UpdateUserNote(ctx context.Context, accountID AccountID, categoryID CategoryID, feedItemID FeedItemID, note string) error {
+	_, err := api.ExecuteRequestWithBody[struct{}](ctx, c.api,
+		http.MethodPut,
+		fmt.Sprintf(putUserNoteRoute, accountID, categoryID, feedItemID),
+		map[string]string{
+			"userNote": note,
+		},
+	)
+
+	return err
+}
+
+// UpdateSpendingCategory changes the feed item's spending category, e.g. GROCERIES, for this feed item only.
+func (c *client) UpdateSpendingCategory(ctx context.Context, accountID AccountID, categoryID CategoryID, feedItemID FeedItemID, category string) error {
+	_, err := api.ExecuteRequestWithBody[struct{}](ctx, c.api,
+		http.MethodPut,
+		fmt.Sprintf(putSpendingCategoryRoute, accountID, categoryID, feedItemID),
+		map[string]any{
+			"spendingCategory":                         category,
+			"permanentSpendingCategoryUpdate":          false,
+			"previousSpendingCategoryReferencesUpdate": false,
+		},
+	)
+
+	return err
+}
+
 func (c *client) FetchAccounts(ctx context.Context) ([]*Account, error) {
 	result, err := api.ExecuteRequest[struct {
 		Accounts []*Account `json:"accounts"`
@@ -100,6 +160,22 @@ func (c *client) FetchSavingsGoals(ctx context.Context, accountID AccountID) ([]
 	return result.SavingsGoals, nil
 }
 
+func (c *client) FetchSpendingSpaces(ctx context.Context, accountID AccountID) ([]*SpendingSpace, error) {
+	result, err := api.ExecuteRequest[struct {
+		SpendingSpaces []*SpendingSpace `json:"spendingSpaces"`
+	}](
+		ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getSpacesRoute, accountID.String()),
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.SpendingSpaces, nil
+}
+
 func (c *client) FetchTransactionsSince(ctx context.Context, opts FetchTransactionOptions) ([]*FeedItem, error) {
 	if err := opts.Validate(ctx); err != nil {
 		return nil, fmt.Errorf("invalid options: %w", err)
@@ -125,6 +201,112 @@ func (c *client) FetchTransactionsSince(ctx context.Context, opts FetchTransacti
 	return result.FeedItems, nil
 }
 
+// FetchFeedChangesSince fetches the category's feed items created or changed since the given time, in any status.
+func (c *client) FetchFeedChangesSince(ctx context.Context, accountID AccountID, categoryID CategoryID, since time.Time) ([]*FeedItem, error) {
+	result, err := api.ExecuteRequest[struct {
+		FeedItems []*FeedItem `json:"feedItems"`
+	}](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getFeedChangesRoute, accountID, categoryID),
+		url.Values{
+			"changesSince": []string{since.UTC().Format(time.RFC3339Nano)},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.FeedItems, nil
+}
+
+func (c *client) FetchStatementPeriods(ctx context.Context, accountID AccountID) ([]*StatementPeriod, error) {
+	result, err := api.ExecuteRequest[struct {
+		Periods []*StatementPeriod `json:"periods"`
+	}](
+		ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getStatementsRoute, accountID.String()),
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Periods, nil
+}
+
+// DownloadStatement downloads the account's statement for the period (YYYY-MM) in the given format.
+func (c *client) DownloadStatement(ctx context.Context, accountID AccountID, period string, format StatementFormat) ([]byte, error) {
+	accept, ok := statementContentTypes[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported statement format: %s", format)
+	}
+
+	params := url.Values{}
+	params.Add("yearMonth", period)
+
+	result, err := api.ExecuteDownload(ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getStatementRoute, accountID.String()),
+		params,
+		accept,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (c *client) FetchPayees(ctx context.Context) ([]*Payee, error) {
+	result, err := api.ExecuteRequest[struct {
+		Payees []*Payee `json:"payees"`
+	}](
+		ctx, c.api,
+		http.MethodGet,
+		getPayeesRoute,
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Payees, nil
+}
+
+func (c *client) FetchStandingOrders(ctx context.Context, accountID AccountID, categoryID CategoryID) ([]*StandingOrder, error) {
+	result, err := api.ExecuteRequest[struct {
+		StandingOrders []*StandingOrder `json:"standingOrders"`
+	}](
+		ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getStandingOrdersRoute, accountID.String(), categoryID.String()),
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.StandingOrders, nil
+}
+
+// FetchDirectDebitMandates returns the direct debit mandates of every account.
+func (c *client) FetchDirectDebitMandates(ctx context.Context) ([]*DirectDebitMandate, error) {
+	result, err := api.ExecuteRequest[struct {
+		Mandates []*DirectDebitMandate `json:"mandates"`
+	}](
+		ctx, c.api,
+		http.MethodGet,
+		getMandatesRoute,
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Mandates, nil
+}
+
 type FetchTransactionOptions struct {
 	AccountID  AccountID
 	CategoryID CategoryID
@@ -161,3 +343,97 @@ func (fto FetchTransactionOptions) Validate(ctx context.Context) error {
 		}))),
 	)
 }
+
+func (c *client) FetchBalance(ctx context.Context, accountID AccountID) (*Balance, error) {
+	result, err := api.ExecuteRequest[Balance](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getBalanceRoute, accountID.String()),
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FetchSpendingInsights fetches the account's spending for the month, aggregated by the breakdown.
+func (c *client) FetchSpendingInsights(ctx context.Context, accountID AccountID, breakdown InsightsBreakdown, year int, month time.Month) (*SpendingInsights, error) {
+	path, ok := insightsPaths[breakdown]
+	if !ok {
+		return nil, fmt.Errorf("unsupported spending insights breakdown: %s", breakdown)
+	}
+
+	result, err := api.ExecuteRequest[SpendingInsights](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getSpendingInsightsRoute, accountID.String(), path),
+		url.Values{
+			"year":  []string{strconv.Itoa(year)},
+			"month": []string{strings.ToUpper(month.String())},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FetchAccountIdentifiers fetches the account's sort code, account number, IBAN and BIC.
+func (c *client) FetchAccountIdentifiers(ctx context.Context, accountID AccountID) (*AccountIdentifiers, error) {
+	result, err := api.ExecuteRequest[AccountIdentifiers](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getIdentifiersRoute, accountID.String()),
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FetchAccountHolder fetches the type of the account holder the token belongs to, e.g. INDIVIDUAL or JOINT.
+func (c *client) FetchAccountHolder(ctx context.Context) (*AccountHolder, error) {
+	result, err := api.ExecuteRequest[AccountHolder](ctx, c.api,
+		http.MethodGet,
+		getAccountHolderRoute,
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FetchAccountHolderName fetches the account holder's name, the business name for business accounts.
+func (c *client) FetchAccountHolderName(ctx context.Context) (string, error) {
+	result, err := api.ExecuteRequest[struct {
+		Name string `json:"accountHolderName"`
+	}](
+		ctx, c.api,
+		http.MethodGet,
+		getHolderNameRoute,
+		url.Values{},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Name, nil
+}
+
+// FetchJointAccountHolder fetches both people holding a joint account.
+func (c *client) FetchJointAccountHolder(ctx context.Context) (*JointAccountHolder, error) {
+	result, err := api.ExecuteRequest[JointAccountHolder](ctx, c.api,
+		http.MethodGet,
+		getJointHolderRoute,
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
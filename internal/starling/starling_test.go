@@ -3,6 +3,7 @@ package starling_test
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"testing"
@@ -196,6 +197,705 @@ func TestFetchSavingsGoals(t *testing.T) {
 	}
 }
 
+func TestFetchSpendingSpaces(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+
+	tests := map[string]struct {
+		route               testhelper.HTTPTestRoute
+		expectedSpaces      []*starling.SpendingSpace
+		expectedStarlingErr *starling.Error
+		expectedErrMsg      string
+	}{
+		"successful fetch": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/account/%s/spaces", accountId.String()),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					query := url.Values{}
+					header.Add("Authorization", token)
+
+					testhelper.AssertRequest(t, r, http.MethodGet, header, query)
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "spaces.json")(w, r)
+				},
+			},
+			expectedSpaces: []*starling.SpendingSpace{
+				{
+					ID:      starling.SpaceID(uuid.MustParse("55665566-5566-5566-5566-556655665566")),
+					Name:    "Bills",
+					Type:    "SPENDING_SPACE",
+					State:   "ACTIVE",
+					Balance: domain.Money{MinorUnit: 45000, Currency: "GBP"},
+				},
+			},
+		},
+		"returns API error": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/account/%s/spaces", accountId.String()),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+				},
+			},
+			expectedStarlingErr: &starling.Error{
+				Code:    "invalid_token",
+				Message: "No access token provided in request. `Header: Authorization` must be set",
+			},
+			expectedErrMsg: "No access token provided in request. `Header: Authorization` must be set",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, test.route)
+			items, err := client.FetchSpendingSpaces(t.Context(), accountId)
+
+			if test.expectedStarlingErr != nil {
+				require.Empty(t, items)
+				requireStarlingErrorEqual(t, *test.expectedStarlingErr, test.expectedErrMsg, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.expectedSpaces, items)
+				require.Equal(t, "55665566-5566-5566-5566-556655665566", items[0].ID.String())
+			}
+		})
+	}
+}
+
+func TestFetchBalance(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+
+	tests := map[string]struct {
+		route               testhelper.HTTPTestRoute
+		expectedBalance     *starling.Balance
+		expectedStarlingErr *starling.Error
+		expectedErrMsg      string
+	}{
+		"successful fetch": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/accounts/%s/balance", accountId.String()),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					query := url.Values{}
+					header.Add("Authorization", token)
+
+					testhelper.AssertRequest(t, r, http.MethodGet, header, query)
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "balance.json")(w, r)
+				},
+			},
+			expectedBalance: &starling.Balance{
+				Cleared:             domain.Money{MinorUnit: 123456, Currency: "GBP"},
+				Effective:           domain.Money{MinorUnit: 120456, Currency: "GBP"},
+				PendingTransactions: domain.Money{MinorUnit: 3000, Currency: "GBP"},
+				AcceptedOverdraft:   domain.Money{MinorUnit: 50000, Currency: "GBP"},
+				TotalCleared:        domain.Money{MinorUnit: 246912, Currency: "GBP"},
+				TotalEffective:      domain.Money{MinorUnit: 243912, Currency: "GBP"},
+			},
+		},
+		"returns API error": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/accounts/%s/balance", accountId.String()),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+				},
+			},
+			expectedStarlingErr: &starling.Error{
+				Code:    "invalid_token",
+				Message: "No access token provided in request. `Header: Authorization` must be set",
+			},
+			expectedErrMsg: "No access token provided in request. `Header: Authorization` must be set",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, test.route)
+			balance, err := client.FetchBalance(t.Context(), accountId)
+
+			if test.expectedStarlingErr != nil {
+				require.Nil(t, balance)
+				requireStarlingErrorEqual(t, *test.expectedStarlingErr, test.expectedErrMsg, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.expectedBalance, balance)
+				require.Equal(t, domain.Money{MinorUnit: 170456, Currency: "GBP"}, balance.AvailableToSpend())
+			}
+		})
+	}
+}
+
+func TestFetchStatementPeriods(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+
+	t.Run("successful fetch", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, testhelper.HTTPTestRoute{
+			Method: http.MethodGet,
+			URL:    fmt.Sprintf("/api/v2/accounts/%s/statement/available-periods", accountId.String()),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				header := http.Header{}
+				header.Add("Authorization", token)
+
+				testhelper.AssertRequest(t, r, http.MethodGet, header, url.Values{})
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "statement-periods.json")(w, r)
+			},
+		})
+
+		periods, err := client.FetchStatementPeriods(t.Context(), accountId)
+
+		require.NoError(t, err)
+		require.Equal(t, []*starling.StatementPeriod{
+			{Period: "2025-01"},
+			{Period: "2025-02", Partial: true},
+		}, periods)
+	})
+}
+
+func TestDownloadStatement(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+	route := fmt.Sprintf("/api/v2/accounts/%s/statement/download", accountId.String())
+
+	tests := map[string]struct {
+		format              starling.StatementFormat
+		route               testhelper.HTTPTestRoute
+		expectedBody        []byte
+		expectedStarlingErr *starling.Error
+		expectedErrMsg      string
+	}{
+		"downloads csv statement": {
+			format: starling.StatementFormatCSV,
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    route,
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					header.Add("Authorization", token)
+					header.Add("Accept", "text/csv")
+					query := url.Values{}
+					query.Add("yearMonth", "2025-01")
+
+					testhelper.AssertRequest(t, r, http.MethodGet, header, query)
+					w.Header().Set("Content-Type", "text/csv")
+					_, _ = w.Write([]byte("Date,Counter Party,Amount\n"))
+				},
+			},
+			expectedBody: []byte("Date,Counter Party,Amount\n"),
+		},
+		"downloads pdf statement": {
+			format: starling.StatementFormatPDF,
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    route,
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					header.Add("Accept", "application/pdf")
+
+					testhelper.AssertRequest(t, r, http.MethodGet, header, url.Values{})
+					w.Header().Set("Content-Type", "application/pdf")
+					_, _ = w.Write([]byte("%PDF-1.4"))
+				},
+			},
+			expectedBody: []byte("%PDF-1.4"),
+		},
+		"returns error when format unsupported": {
+			format: starling.StatementFormat("xlsx"),
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    route,
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					t.Error("unexpected request")
+				},
+			},
+			expectedErrMsg: "unsupported statement format: xlsx",
+		},
+		"returns API error": {
+			format: starling.StatementFormatPDF,
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    route,
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+				},
+			},
+			expectedStarlingErr: &starling.Error{
+				Code:    "invalid_token",
+				Message: "No access token provided in request. `Header: Authorization` must be set",
+			},
+			expectedErrMsg: "No access token provided in request. `Header: Authorization` must be set",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, test.route)
+			body, err := client.DownloadStatement(t.Context(), accountId, "2025-01", test.format)
+
+			switch {
+			case test.expectedStarlingErr != nil:
+				require.Nil(t, body)
+				requireStarlingErrorEqual(t, *test.expectedStarlingErr, test.expectedErrMsg, err)
+			case test.expectedErrMsg != "":
+				require.Nil(t, body)
+				require.EqualError(t, err, test.expectedErrMsg)
+			default:
+				require.NoError(t, err)
+				require.Equal(t, test.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestUpdateFeedItem(t *testing.T) {
+	t.Parallel()
+
+	feedItemId := starling.FeedItemID(uuid.MustParse("11221122-1122-1122-1122-112211221122"))
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+	categoryId := starling.CategoryID(uuid.MustParse("ccddccdd-ccdd-ccdd-ccdd-ccddccddccdd"))
+	feedItemURL := fmt.Sprintf("/api/v2/feed/account/%s/category/%s/%s", accountId.String(), categoryId.String(), feedItemId.String())
+
+	assertBody := func(t *testing.T, r *http.Request, expected string) {
+		t.Helper()
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.JSONEq(t, expected, string(body))
+	}
+
+	tests := map[string]struct {
+		route          testhelper.HTTPTestRoute
+		update         func(client starling.Client) error
+		expectedErrMsg string
+	}{
+		"updates user note": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodPut,
+				URL:    feedItemURL + "/user-note",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					header.Add("Authorization", token)
+
+					testhelper.AssertRequest(t, r, http.MethodPut, header, url.Values{})
+					assertBody(t, r, `{"userNote":"Dinner with Sam"}`)
+					w.WriteHeader(http.StatusOK)
+				},
+			},
+			update: func(client starling.Client) error {
+				return client.UpdateUserNote(t.Context(), accountId, categoryId, feedItemId, "Dinner with Sam")
+			},
+		},
+		"updates spending category": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodPut,
+				URL:    feedItemURL + "/spending-category",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					header.Add("Authorization", token)
+
+					testhelper.AssertRequest(t, r, http.MethodPut, header, url.Values{})
+					assertBody(t, r, `{
+						"spendingCategory": "EATING_OUT",
+						"permanentSpendingCategoryUpdate": false,
+						"previousSpendingCategoryReferencesUpdate": false
+					}`)
+					w.WriteHeader(http.StatusOK)
+				},
+			},
+			update: func(client starling.Client) error {
+				return client.UpdateSpendingCategory(t.Context(), accountId, categoryId, feedItemId, "EATING_OUT")
+			},
+		},
+		"returns API error": {
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodPut,
+				URL:    feedItemURL + "/user-note",
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+				},
+			},
+			update: func(client starling.Client) error {
+				return client.UpdateUserNote(t.Context(), accountId, categoryId, feedItemId, "note")
+			},
+			expectedErrMsg: "No access token provided in request. `Header: Authorization` must be set",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, test.route)
+			err := test.update(client)
+
+			if test.expectedErrMsg != "" {
+				require.EqualError(t, err, test.expectedErrMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFetchRecurringPayments(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+	categoryId := starling.CategoryID(uuid.MustParse("ccddccdd-ccdd-ccdd-ccdd-ccddccddccdd"))
+	payeeId := starling.PayeeID(uuid.MustParse("33443344-3344-3344-3344-334433443344"))
+
+	serve := func(url string, filename string) testhelper.HTTPTestRoute {
+		return testhelper.HTTPTestRoute{
+			Method: http.MethodGet,
+			URL:    url,
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				header := http.Header{}
+				header.Add("Authorization", token)
+
+				testhelper.AssertRequest(t, r, http.MethodGet, header, nil)
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, filename)(w, r)
+			},
+		}
+	}
+
+	t.Run("fetches payees", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, serve("/api/v2/payees", "payees.json"))
+		payees, err := client.FetchPayees(t.Context())
+
+		require.NoError(t, err)
+		require.Len(t, payees, 1)
+		require.Equal(t, payeeId, payees[0].ID)
+		require.Equal(t, "Landlord Ltd", payees[0].Name)
+		require.Equal(t, "608371", payees[0].Accounts[0].BankIdentifier)
+	})
+
+	t.Run("fetches standing orders", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, serve(fmt.Sprintf("/api/v2/payments/local/account/%s/category/%s/standing-orders", accountId, categoryId), "standing-orders.json"))
+		orders, err := client.FetchStandingOrders(t.Context(), accountId, categoryId)
+
+		require.NoError(t, err)
+		require.Len(t, orders, 1)
+		require.Equal(t, "22332233-2233-2233-2233-223322332233", orders[0].ID.String())
+		require.Equal(t, payeeId, orders[0].PayeeID)
+		require.Equal(t, domain.Money{MinorUnit: 95000, Currency: "GBP"}, orders[0].Amount)
+		require.Equal(t, "MONTHLY", orders[0].Recurrence.Frequency)
+		require.Equal(t, 1, orders[0].Recurrence.Interval)
+		require.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), orders[0].NextDate.Time)
+		require.Nil(t, orders[0].CancelledAt)
+	})
+
+	t.Run("fetches direct debit mandates", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, serve("/api/v2/direct-debit/mandates", "mandates.json"))
+		mandates, err := client.FetchDirectDebitMandates(t.Context())
+
+		require.NoError(t, err)
+		require.Len(t, mandates, 1)
+		require.Equal(t, "Energy Co", mandates[0].OriginatorName)
+		require.Equal(t, starling.MandateStatusLive, mandates[0].Status)
+		require.Equal(t, accountId, mandates[0].AccountID)
+		require.Equal(t, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), mandates[0].LastPayment.Date.Time)
+		require.Equal(t, domain.Money{MinorUnit: 8500, Currency: "GBP"}, mandates[0].LastPayment.Amount)
+	})
+
+	t.Run("returns API error", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, testhelper.HTTPTestRoute{
+			Method: http.MethodGet,
+			URL:    "/api/v2/payees",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+			},
+		})
+		payees, err := client.FetchPayees(t.Context())
+
+		require.Nil(t, payees)
+		require.EqualError(t, err, "No access token provided in request. `Header: Authorization` must be set")
+	})
+}
+
+func TestFetchSpendingInsights(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+
+	tests := map[string]struct {
+		breakdown        starling.InsightsBreakdown
+		route            testhelper.HTTPTestRoute
+		expectedInsights *starling.SpendingInsights
+		expectedErrMsg   string
+	}{
+		"fetches insights by category": {
+			breakdown: starling.InsightsByCategory,
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/accounts/%s/spending-insights/spending-category", accountId),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					header := http.Header{}
+					header.Add("Authorization", token)
+					query := url.Values{}
+					query.Add("year", "2025")
+					query.Add("month", "MARCH")
+
+					testhelper.AssertRequest(t, r, http.MethodGet, header, query)
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "spending-insights-category.json")(w, r)
+				},
+			},
+			expectedInsights: &starling.SpendingInsights{
+				Period:        "2025-03",
+				TotalSpent:    412.5,
+				TotalReceived: 2500,
+				NetSpend:      2087.5,
+				NetDirection:  starling.DirectionIN,
+				Currency:      "GBP",
+				Breakdown: []*starling.SpendingInsight{
+					{
+						SpendingCategory: "GROCERIES",
+						TotalSpent:       250.25,
+						NetSpend:         250.25,
+						NetDirection:     starling.DirectionOUT,
+						Currency:         "GBP",
+						Percentage:       60.67,
+						TransactionCount: 9,
+					},
+					{
+						SpendingCategory: "EATING_OUT",
+						TotalSpent:       162.25,
+						TotalReceived:    12.5,
+						NetSpend:         149.75,
+						NetDirection:     starling.DirectionOUT,
+						Currency:         "GBP",
+						Percentage:       39.33,
+						TransactionCount: 6,
+					},
+				},
+			},
+		},
+		"fetches insights by counterparty": {
+			breakdown: starling.InsightsByCounterparty,
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/accounts/%s/spending-insights/counter-party", accountId),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "spending-insights-counterparty.json")(w, r)
+				},
+			},
+			expectedInsights: &starling.SpendingInsights{
+				Period:       "2025-03",
+				TotalSpent:   250.25,
+				NetSpend:     250.25,
+				NetDirection: starling.DirectionOUT,
+				Currency:     "GBP",
+				Breakdown: []*starling.SpendingInsight{
+					{
+						CounterPartyID:   starling.CounterPartyID(uuid.MustParse("00000000-0000-4000-0000-000000000077")),
+						CounterPartyType: "MERCHANT",
+						CounterPartyName: "Corner Shop",
+						TotalSpent:       250.25,
+						NetSpend:         250.25,
+						NetDirection:     starling.DirectionOUT,
+						Currency:         "GBP",
+						Percentage:       100,
+						TransactionCount: 9,
+					},
+				},
+			},
+		},
+		"returns error for unsupported breakdown": {
+			breakdown: starling.InsightsBreakdown("merchant"),
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/accounts/%s/spending-insights/merchant", accountId),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					t.Error("unexpected request for unsupported breakdown")
+				},
+			},
+			expectedErrMsg: "unsupported spending insights breakdown: merchant",
+		},
+		"returns API error": {
+			breakdown: starling.InsightsByCountry,
+			route: testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    fmt.Sprintf("/api/v2/accounts/%s/spending-insights/country", accountId),
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+				},
+			},
+			expectedErrMsg: "No access token provided in request. `Header: Authorization` must be set",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, test.route)
+			insights, err := client.FetchSpendingInsights(t.Context(), accountId, test.breakdown, 2025, time.March)
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, insights)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedInsights, insights)
+		})
+	}
+}
+
+func TestFetchAccountDetails(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+
+	serve := func(url string, filename string) testhelper.HTTPTestRoute {
+		return testhelper.HTTPTestRoute{
+			Method: http.MethodGet,
+			URL:    url,
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				header := http.Header{}
+				header.Add("Authorization", token)
+
+				testhelper.AssertRequest(t, r, http.MethodGet, header, nil)
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, filename)(w, r)
+			},
+		}
+	}
+
+	t.Run("fetches account identifiers", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, serve(fmt.Sprintf("/api/v2/accounts/%s/identifiers", accountId), "identifiers.json"))
+		identifiers, err := client.FetchAccountIdentifiers(t.Context(), accountId)
+
+		require.NoError(t, err)
+		require.Equal(t, &starling.AccountIdentifiers{
+			AccountIdentifier: "12345678",
+			BankIdentifier:    "608371",
+			IBAN:              "GB33SRLG60837112345678",
+			BIC:               "SRLGGB2L",
+		}, identifiers)
+	})
+
+	t.Run("fetches account holder", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, serve("/api/v2/account-holder", "account-holder.json"))
+		holder, err := client.FetchAccountHolder(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, &starling.AccountHolder{
+			ID:   "00000000-0000-4000-0000-000000000055",
+			Type: starling.AccountHolderTypeJoint,
+		}, holder)
+	})
+
+	t.Run("fetches account holder name", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, serve("/api/v2/account-holder/name", "account-holder-name.json"))
+		name, err := client.FetchAccountHolderName(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, "Jo Bloggs and Sam Bloggs", name)
+	})
+
+	t.Run("fetches joint account holder", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, serve("/api/v2/account-holder/joint", "account-holder-joint.json"))
+		holder, err := client.FetchJointAccountHolder(t.Context())
+
+		require.NoError(t, err)
+		require.Equal(t, "Jo Bloggs", holder.PersonOne.Name())
+		require.Equal(t, "Sam Bloggs", holder.PersonTwo.Name())
+	})
+
+	t.Run("returns API error", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, testhelper.HTTPTestRoute{
+			Method: http.MethodGet,
+			URL:    fmt.Sprintf("/api/v2/accounts/%s/identifiers", accountId),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+			},
+		})
+		identifiers, err := client.FetchAccountIdentifiers(t.Context(), accountId)
+
+		require.Nil(t, identifiers)
+		require.EqualError(t, err, "No access token provided in request. `Header: Authorization` must be set")
+	})
+}
+
+func TestFetchFeedChangesSince(t *testing.T) {
+	t.Parallel()
+
+	accountId := starling.AccountID(uuid.MustParse("00000000-0000-4000-0000-000000000033"))
+	categoryId := starling.CategoryID(uuid.MustParse("ccddccdd-ccdd-ccdd-ccdd-ccddccddccdd"))
+	since := time.Date(2025, 2, 19, 12, 0, 0, 500, time.UTC)
+
+	t.Run("successful fetch", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, testhelper.HTTPTestRoute{
+			Method: http.MethodGet,
+			URL:    fmt.Sprintf("/api/v2/feed/account/%s/category/%s", accountId, categoryId),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				header := http.Header{}
+				header.Add("Authorization", token)
+				query := url.Values{}
+				query.Add("changesSince", "2025-02-19T12:00:00.0000005Z")
+
+				testhelper.AssertRequest(t, r, http.MethodGet, header, query)
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "feed-items.json")(w, r)
+			},
+		})
+
+		items, err := client.FetchFeedChangesSince(t.Context(), accountId, categoryId, since)
+
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, "11221122-1122-1122-1122-112211221122", items[0].ID.String())
+		require.Equal(t, "2025-02-19T16:38:59.564Z", items[0].UpdatedAt.Format(time.RFC3339Nano))
+	})
+
+	t.Run("returns API error", func(t *testing.T) {
+		t.Parallel()
+
+		client := setup(t, testhelper.HTTPTestRoute{
+			Method: http.MethodGet,
+			URL:    fmt.Sprintf("/api/v2/feed/account/%s/category/%s", accountId, categoryId),
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json")(w, r)
+			},
+		})
+
+		items, err := client.FetchFeedChangesSince(t.Context(), accountId, categoryId, since)
+
+		require.Nil(t, items)
+		require.EqualError(t, err, "No access token provided in request. `Header: Authorization` must be set")
+	})
+}
+
 func TestFetchFeedItem(t *testing.T) {
 	t.Parallel()
 
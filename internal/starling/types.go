@@ -15,6 +15,10 @@ type (
 	CategoryID     uuid.UUID
 	CounterPartyID uuid.UUID
 	SavingsGoalID  uuid.UUID
+	SpaceID        uuid.UUID
+	PayeeID        uuid.UUID
+	PaymentOrderID uuid.UUID
+	MandateID      uuid.UUID
 )
 
 func (a *AccountID) UnmarshalJSON(data []byte) error {
@@ -64,6 +68,96 @@ func (s SavingsGoalID) String() string {
 	return uuid.UUID(s).String()
 }
 
+func (s *SpaceID) UnmarshalJSON(data []byte) error {
+	var id uuid.UUID
+	err := json.Unmarshal(data, &id)
+	if err != nil {
+		return err
+	}
+
+	*s = SpaceID(id)
+
+	return nil
+}
+
+func (s SpaceID) String() string {
+	return uuid.UUID(s).String()
+}
+
+func (p *PayeeID) UnmarshalJSON(data []byte) error {
+	var id uuid.UUID
+	err := json.Unmarshal(data, &id)
+	if err != nil {
+		return err
+	}
+
+	*p = PayeeID(id)
+
+	return nil
+}
+
+func (p PayeeID) String() string {
+	return uuid.UUID(p).String()
+}
+
+func (p *PaymentOrderID) UnmarshalJSON(data []byte) error {
+	var id uuid.UUID
+	err := json.Unmarshal(data, &id)
+	if err != nil {
+		return err
+	}
+
+	*p = PaymentOrderID(id)
+
+	return nil
+}
+
+func (p PaymentOrderID) String() string {
+	return uuid.UUID(p).String()
+}
+
+func (m *MandateID) UnmarshalJSON(data []byte) error {
+	var id uuid.UUID
+	err := json.Unmarshal(data, &id)
+	if err != nil {
+		return err
+	}
+
+	*m = MandateID(id)
+
+	return nil
+}
+
+func (m MandateID) String() string {
+	return uuid.UUID(m).String()
+}
+
+// Date is a calendar date, sent by Starling as YYYY-MM-DD.
+type Date struct {
+	time.Time
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	if str == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+
+	t, err := time.Parse(time.DateOnly, str)
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+
+	return nil
+}
+
 func (c *CategoryID) UnmarshalJSON(data []byte) error {
 	var id uuid.UUID
 	err := json.Unmarshal(data, &id)
@@ -105,6 +199,125 @@ type Account struct {
 	Name              string     `json:"name"`
 }
 
+// AccountIdentifiers are the details used to pay into an account.
+type AccountIdentifiers struct {
+	AccountIdentifier string `json:"accountIdentifier"` // Account number
+	BankIdentifier    string `json:"bankIdentifier"`    // Sort code
+	IBAN              string `json:"iban"`
+	BIC               string `json:"bic"`
+}
+
+// AccountHolderType is who holds the accounts, which determines the endpoint for the holder's details.
+type AccountHolderType string
+
+const (
+	AccountHolderTypeIndividual AccountHolderType = "INDIVIDUAL"
+	AccountHolderTypeJoint      AccountHolderType = "JOINT"
+	AccountHolderTypeBusiness   AccountHolderType = "BUSINESS"
+	AccountHolderTypeSoleTrader AccountHolderType = "SOLE_TRADER"
+)
+
+type AccountHolder struct {
+	ID   string            `json:"accountHolderUid"`
+	Type AccountHolderType `json:"accountHolderType"`
+}
+
+type JointAccountHolder struct {
+	ID        string  `json:"accountHolderUid"`
+	PersonOne *Person `json:"personOne"`
+	PersonTwo *Person `json:"personTwo"`
+}
+
+type Person struct {
+	Title     string `json:"title"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+}
+
+// Name returns the person's first and last name.
+func (p Person) Name() string {
+	return strings.TrimSpace(p.FirstName + " " + p.LastName)
+}
+
+// Balance is an account's balance. The total balances include the account's savings goals and spending spaces.
+type Balance struct {
+	Cleared             domain.Money `json:"clearedBalance"`      // Settled transactions only
+	Effective           domain.Money `json:"effectiveBalance"`    // Cleared balance plus pending transactions
+	PendingTransactions domain.Money `json:"pendingTransactions"` // Total of pending transactions
+	AcceptedOverdraft   domain.Money `json:"acceptedOverdraft"`
+	TotalCleared        domain.Money `json:"totalClearedBalance"`
+	TotalEffective      domain.Money `json:"totalEffectiveBalance"`
+}
+
+// AvailableToSpend is the effective balance plus any accepted overdraft.
+func (b Balance) AvailableToSpend() domain.Money {
+	return domain.Money{
+		MinorUnit: b.Effective.MinorUnit + b.AcceptedOverdraft.MinorUnit,
+		Currency:  b.Effective.Currency,
+	}
+}
+
+// StatementFormat is the file format of a downloaded statement.
+type StatementFormat string
+
+const (
+	StatementFormatPDF StatementFormat = "pdf"
+	StatementFormatCSV StatementFormat = "csv"
+)
+
+var statementContentTypes = map[StatementFormat]string{
+	StatementFormatPDF: "application/pdf",
+	StatementFormatCSV: "text/csv",
+}
+
+// StatementPeriod is a month for which a statement can be downloaded.
+type StatementPeriod struct {
+	Period  string `json:"period"`  // YYYY-MM
+	Partial bool   `json:"partial"` // The month hasn't ended yet, so the statement is incomplete
+}
+
+// InsightsBreakdown is what spending insights are aggregated by.
+type InsightsBreakdown string
+
+const (
+	InsightsByCategory     InsightsBreakdown = "category"
+	InsightsByCounterparty InsightsBreakdown = "counterparty"
+	InsightsByCountry      InsightsBreakdown = "country"
+)
+
+var insightsPaths = map[InsightsBreakdown]string{
+	InsightsByCategory:     "spending-category",
+	InsightsByCounterparty: "counter-party",
+	InsightsByCountry:      "country",
+}
+
+// SpendingInsights is a month's spending, aggregated server-side. Amounts are in major units.
+type SpendingInsights struct {
+	Period        string             `json:"period"`
+	TotalSpent    float64            `json:"totalSpent"`
+	TotalReceived float64            `json:"totalReceived"`
+	NetSpend      float64            `json:"netSpend"`
+	NetDirection  Direction          `json:"direction"`
+	Currency      string             `json:"currency"`
+	Breakdown     []*SpendingInsight `json:"breakdown"`
+}
+
+// SpendingInsight is the spending for a single category, counterparty or country, depending on the breakdown.
+type SpendingInsight struct {
+	SpendingCategory string         `json:"spendingCategory"`
+	CounterPartyID   CounterPartyID `json:"counterPartyUid"`
+	CounterPartyType string         `json:"counterPartyType"`
+	CounterPartyName string         `json:"counterPartyName"`
+	CountryCode      string         `json:"countryCode"`
+	TotalSpent       float64        `json:"totalSpent"`
+	TotalReceived    float64        `json:"totalReceived"`
+	NetSpend         float64        `json:"netSpend"`
+	NetDirection     Direction      `json:"netDirection"`
+	Currency         string         `json:"currency"`
+	Percentage       float64        `json:"percentage"`
+	TransactionCount int            `json:"transactionCount"`
+}
+
 type SavingsGoal struct {
 	ID         SavingsGoalID `json:"savingsGoalUid"`
 	Name       string        `json:"name"`
@@ -113,6 +326,71 @@ type SavingsGoal struct {
 	TotalSaved domain.Money  `json:"totalSaved"`
 }
 
+// SpendingSpace is a space with its own balance (and optionally card) used for day-to-day spending, as opposed
+// to a savings goal. Its ID is also the category ID of its transaction feed.
+type SpendingSpace struct {
+	ID      SpaceID      `json:"spaceUid"`
+	Name    string       `json:"name"`
+	Type    string       `json:"spendingSpaceType"` // e.g. SPENDING_SPACE
+	State   string       `json:"state"`
+	Balance domain.Money `json:"balance"`
+}
+
+type Payee struct {
+	ID           PayeeID         `json:"payeeUid"`
+	Name         string          `json:"payeeName"`
+	Type         string          `json:"payeeType"` // INDIVIDUAL or BUSINESS
+	BusinessName string          `json:"businessName"`
+	Accounts     []*PayeeAccount `json:"accounts"`
+}
+
+type PayeeAccount struct {
+	ID                uuid.UUID `json:"payeeAccountUid"`
+	Description       string    `json:"description"`
+	AccountIdentifier string    `json:"accountIdentifier"` // e.g. account number
+	BankIdentifier    string    `json:"bankIdentifier"`    // e.g. sort code
+}
+
+// StandingOrderRecurrence is how often a standing order is paid, e.g. every 2 (interval) MONTHLY (frequency).
+type StandingOrderRecurrence struct {
+	StartDate Date   `json:"startDate"`
+	Frequency string `json:"frequency"` // DAILY, WEEKLY, MONTHLY or YEARLY
+	Interval  int    `json:"interval"`
+	Count     int    `json:"count"`     // Number of payments, zero when unlimited
+	UntilDate *Date  `json:"untilDate"` // Date of the last payment, if any
+}
+
+type StandingOrder struct {
+	ID          PaymentOrderID          `json:"paymentOrderUid"`
+	Amount      domain.Money            `json:"amount"`
+	Reference   string                  `json:"reference"`
+	PayeeID     PayeeID                 `json:"payeeUid"`
+	Recurrence  StandingOrderRecurrence `json:"standingOrderRecurrence"`
+	NextDate    *Date                   `json:"nextDate"`
+	CancelledAt *time.Time              `json:"cancelledAt"`
+	Category    string                  `json:"spendingCategory"`
+}
+
+const MandateStatusLive = "LIVE"
+
+type DirectDebitMandate struct {
+	ID             MandateID           `json:"uid"`
+	Reference      string              `json:"reference"`
+	Status         string              `json:"status"` // LIVE or CANCELLED
+	Source         string              `json:"source"` // ELECTRONIC or PAPER
+	CreatedAt      time.Time           `json:"created"`
+	CancelledAt    *time.Time          `json:"cancelled"`
+	OriginatorName string              `json:"originatorName"`
+	LastPayment    *DirectDebitPayment `json:"lastPayment"`
+	AccountID      AccountID           `json:"accountUid"`
+	CategoryID     CategoryID          `json:"categoryUid"`
+}
+
+type DirectDebitPayment struct {
+	Date   Date         `json:"lastDate"`
+	Amount domain.Money `json:"lastAmount"`
+}
+
 type (
 	Direction string
 	Status    string
@@ -142,6 +420,7 @@ type FeedItem struct {
 	Amount                  domain.Money   `json:"amount"` // Amount in the account's currency
 	TransactedAt            time.Time      `json:"transactionTime"`
 	SettledAt               *time.Time     `json:"settlementTime"`
+	UpdatedAt               time.Time      `json:"updatedAt"` // When the feed item was last changed, e.g. settled or its note edited
 	CategoryID              CategoryID     `json:"categoryUid"`
 	CategoryName            string         `json:"spendingCategory"`
 	Description             string         `json:"reference"`
@@ -0,0 +1,103 @@
+package starling
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the header Starling signs webhook deliveries with.
+const SignatureHeader = "X-Hook-Signature"
+
+// ErrInvalidSignature is returned when a webhook delivery's signature doesn't match its body.
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// WebhookEvent is a feed item webhook delivery. Content is the feed item as it is after the event.
+type WebhookEvent struct {
+	ID              string    `json:"webhookEventUid"`
+	Timestamp       time.Time `json:"eventTimestamp"`
+	AccountHolderID string    `json:"accountHolderUid"`
+	Content         *FeedItem `json:"content"`
+}
+
+// SignatureVerifier checks the X-Hook-Signature of a webhook delivery against its raw body.
+type SignatureVerifier interface {
+	Verify(body []byte, signature string) error
+}
+
+type publicKeyVerifier struct {
+	key *rsa.PublicKey
+}
+
+// NewPublicKeyVerifier verifies signatures made with SHA512withRSA, using the webhook public key from the
+// Starling developer portal. The key may be PEM encoded or the bare base64 encoded DER shown in the portal.
+func NewPublicKeyVerifier(publicKey []byte) (SignatureVerifier, error) {
+	der := publicKey
+	if block, _ := pem.Decode(publicKey); block != nil {
+		der = block.Bytes
+	} else {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(publicKey)))
+		if err != nil {
+			return nil, fmt.Errorf("decode public key: %w", err)
+		}
+
+		der = decoded
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, expected RSA", key)
+	}
+
+	return &publicKeyVerifier{key: rsaKey}, nil
+}
+
+func (v *publicKeyVerifier) Verify(body []byte, signature string) error {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	digest := sha512.Sum512(body)
+	if err := rsa.VerifyPKCS1v15(v.key, crypto.SHA512, digest[:], sig); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
+
+type sharedSecretVerifier struct {
+	secret []byte
+}
+
+// NewSharedSecretVerifier verifies legacy signatures, the base64 encoded SHA-512 hash of the shared secret
+// followed by the body.
+func NewSharedSecretVerifier(secret string) SignatureVerifier {
+	return &sharedSecretVerifier{secret: []byte(secret)}
+}
+
+func (v *sharedSecretVerifier) Verify(body []byte, signature string) error {
+	hash := sha512.New()
+	hash.Write(v.secret)
+	hash.Write(body)
+	expected := base64.StdEncoding.EncodeToString(hash.Sum(nil))
+
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) != 1 {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package starling_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/starling"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicKeyVerifier(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"webhookEventUid":"a6b7c8d9-0000-4000-0000-000000000001"}`)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	digest := sha512.Sum512(body)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA512, digest[:])
+	require.NoError(t, err)
+
+	signature := base64.StdEncoding.EncodeToString(sig)
+
+	tests := map[string]struct {
+		publicKey      []byte
+		body           []byte
+		signature      string
+		expectedErr    error
+		expectedErrMsg string
+	}{
+		"verifies with PEM key": {
+			publicKey: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+			body:      body,
+			signature: signature,
+		},
+		"verifies with base64 DER key": {
+			publicKey: []byte(base64.StdEncoding.EncodeToString(der) + "\n"),
+			body:      body,
+			signature: signature,
+		},
+		"rejects tampered body": {
+			publicKey:   pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+			body:        []byte(`{"webhookEventUid":"tampered"}`),
+			signature:   signature,
+			expectedErr: starling.ErrInvalidSignature,
+		},
+		"rejects signature that isn't base64": {
+			publicKey:   pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+			body:        body,
+			signature:   "not base64!",
+			expectedErr: starling.ErrInvalidSignature,
+		},
+		"returns error for invalid key": {
+			publicKey:      []byte("bm90IGEga2V5"),
+			expectedErrMsg: "parse public key",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			verifier, err := starling.NewPublicKeyVerifier(test.publicKey)
+			if test.expectedErrMsg != "" {
+				require.Nil(t, verifier)
+				require.ErrorContains(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			err = verifier.Verify(test.body, test.signature)
+			if test.expectedErr != nil {
+				require.ErrorIs(t, err, test.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSharedSecretVerifier(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"webhookEventUid":"a6b7c8d9-0000-4000-0000-000000000001"}`)
+	hash := sha512.Sum512(append([]byte("secret"), body...))
+	signature := base64.StdEncoding.EncodeToString(hash[:])
+
+	verifier := starling.NewSharedSecretVerifier("secret")
+
+	t.Run("verifies signature", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, verifier.Verify(body, signature))
+	})
+
+	t.Run("rejects signature made with another secret", func(t *testing.T) {
+		t.Parallel()
+
+		err := starling.NewSharedSecretVerifier("other").Verify(body, signature)
+
+		require.ErrorIs(t, err, starling.ErrInvalidSignature)
+	})
+
+	t.Run("rejects missing signature", func(t *testing.T) {
+		t.Parallel()
+
+		require.ErrorIs(t, verifier.Verify(body, ""), starling.ErrInvalidSignature)
+	})
+}
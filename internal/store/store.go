@@ -0,0 +1,109 @@
+// Package store persists small pieces of local state (e.g. backfilled history, sync cursors)
+// as JSON documents in a directory on disk.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const appName = "fingrab"
+
+var invalidKeyChars = regexp.MustCompile(`[^a-zA-Z0-9._-]`)
+
+// Store reads and writes JSON documents, one file per key, within a directory.
+type Store struct {
+	dir string
+}
+
+// New creates a Store rooted at dir, creating the directory if it does not exist.
+func New(dir string) (*Store, error) {
+	if dir == "" {
+		return nil, errors.New("directory is required")
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+
+	return &Store{
+		dir: dir,
+	}, nil
+}
+
+// DefaultDir returns the per-user directory used to store fingrab's local state (e.g. ~/.cache/fingrab).
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, appName), nil
+}
+
+// Load decodes the document stored under key into v.
+// It reports false, without error, when no document exists for the key.
+func (s *Store) Load(key string, v any) (bool, error) {
+	b, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", key, err)
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		return false, fmt.Errorf("decode %s: %w", key, err)
+	}
+
+	return true, nil
+}
+
+// Save encodes v as JSON and stores it under key, replacing any existing document.
+// The write is atomic: readers observe either the previous or the new document.
+func (s *Store) Save(key string, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", key, err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		return fmt.Errorf("rename %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Delete removes the document stored under key. Deleting a missing key is not an error.
+func (s *Store) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("delete %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.dir, invalidKeyChars.ReplaceAllString(key, "_")+".json")
+}
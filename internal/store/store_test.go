@@ -0,0 +1,102 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/HallyG/fingrab/internal/store"
+	"github.com/stretchr/testify/require"
+)
+
+type document struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when directory is empty", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := store.New("")
+
+		require.Nil(t, s)
+		require.ErrorContains(t, err, "directory is required")
+	})
+
+	t.Run("creates missing directory", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := store.New(filepath.Join(t.TempDir(), "nested", "dir"))
+
+		require.NoError(t, err)
+		require.NotNil(t, s)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) *store.Store {
+		t.Helper()
+
+		s, err := store.New(t.TempDir())
+		require.NoError(t, err)
+
+		return s
+	}
+
+	t.Run("load returns false when key is missing", func(t *testing.T) {
+		t.Parallel()
+
+		var doc document
+		found, err := setup(t).Load("missing", &doc)
+
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("save then load round trips document", func(t *testing.T) {
+		t.Parallel()
+
+		s := setup(t)
+		require.NoError(t, s.Save("monzo/acc_123", document{Name: "history", Count: 3}))
+
+		var doc document
+		found, err := s.Load("monzo/acc_123", &doc)
+
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, document{Name: "history", Count: 3}, doc)
+	})
+
+	t.Run("save overwrites existing document", func(t *testing.T) {
+		t.Parallel()
+
+		s := setup(t)
+		require.NoError(t, s.Save("key", document{Count: 1}))
+		require.NoError(t, s.Save("key", document{Count: 2}))
+
+		var doc document
+		_, err := s.Load("key", &doc)
+
+		require.NoError(t, err)
+		require.Equal(t, 2, doc.Count)
+	})
+
+	t.Run("delete removes document", func(t *testing.T) {
+		t.Parallel()
+
+		s := setup(t)
+		require.NoError(t, s.Save("key", document{Count: 1}))
+		require.NoError(t, s.Delete("key"))
+		require.NoError(t, s.Delete("key"))
+
+		var doc document
+		found, err := s.Load("key", &doc)
+
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+}
@@ -0,0 +1,329 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/truelayer"
+	"github.com/samber/lo"
+)
+
+const (
+	ExportTypeTrueLayer   = export.ExportType("truelayer")
+	trueLayerMaxDateRange = time.Duration(0)
+	defaultBankName       = "TrueLayer"
+	businessPrefix        = "BUSINESS_"
+	cardTypeSuffix        = "_card"
+)
+
+var _ export.Exporter = (*TransactionExporter)(nil)
+
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+	Pending:      true,
+}
+
+type TransactionExporter struct {
+	api      truelayer.Client
+	bankName string
+}
+
+type Option func(*TransactionExporter)
+
+// WithBankName configures the bank name given to exported transactions, rather than the name of the provider
+// they're from, e.g. American Express.
+func WithBankName(name string) Option {
+	return func(e *TransactionExporter) {
+		e.bankName = name
+	}
+}
+
+func New(api truelayer.Client, opts ...Option) (*TransactionExporter, error) {
+	if api == nil {
+		return nil, errors.New("truelayer client is required")
+	}
+
+	exporter := &TransactionExporter{
+		api: api,
+	}
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(exporter)
+	}
+
+	return exporter, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return ExportTypeTrueLayer
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return trueLayerMaxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
+// ExportAccounts returns the connected accounts, followed by the connected cards.
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	sources, err := e.fetchSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(sources, func(source *source, _ int) *domain.Account {
+		return source.account
+	}), nil
+}
+
+// ExportTransactions returns the settled and pending transactions of the selected account or card, or of every one
+// when the account is "all". They're selected by ID or display name.
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if opts.Space != "" {
+		return nil, errors.New("spaces are not supported by truelayer")
+	}
+
+	sources, err := e.fetchSources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectSources(sources, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.FromContext(ctx)
+
+	transactions := make([]*domain.Transaction, 0)
+	for _, source := range selected {
+		// to is inclusive and only precise to the second
+		settled, err := e.api.FetchTransactions(ctx, truelayer.FetchTransactionOptions{
+			Resource:  source.resource,
+			AccountID: source.account.ID,
+			Start:     opts.StartDate,
+			End:       opts.EndDate.Add(-time.Second),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch transactions: %w", err)
+		}
+
+		pending, err := e.api.FetchPendingTransactions(ctx, source.resource, source.account.ID)
+		if err != nil {
+			return nil, fmt.Errorf("fetch pending transactions: %w", err)
+		}
+
+		count := 0
+		for i, txn := range slices.Concat(settled, pending) {
+			if txn.Timestamp.Before(opts.StartDate) || !txn.Timestamp.Before(opts.EndDate) {
+				continue
+			}
+
+			status := domain.TransactionStatusSettled
+			if i >= len(settled) {
+				status = domain.TransactionStatusPending
+			}
+
+			transactions = append(transactions, e.toTransaction(txn, source, status))
+			count++
+		}
+
+		logger.InfoContext(ctx, "fetched transactions",
+			slog.String("account.id", source.account.ID),
+			slog.String("account.resource", string(source.resource)),
+			slog.Int("transaction.count", count),
+		)
+	}
+
+	// Pending transactions are fetched separately, so are mixed back in by date
+	slices.SortStableFunc(transactions, func(a, b *domain.Transaction) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return transactions, nil
+}
+
+// source is an account or card, which transactions are fetched from alike.
+type source struct {
+	resource truelayer.Resource
+	account  *domain.Account
+	provider string
+}
+
+func (e *TransactionExporter) fetchSources(ctx context.Context) ([]*source, error) {
+	accounts, err := e.api.FetchAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch accounts: %w", err)
+	}
+
+	cards, err := e.api.FetchCards(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch cards: %w", err)
+	}
+
+	sources := make([]*source, 0, len(accounts)+len(cards))
+	for _, account := range accounts {
+		sources = append(sources, &source{
+			resource: truelayer.ResourceAccounts,
+			account:  toAccount(account),
+			provider: account.Provider.DisplayName,
+		})
+	}
+
+	for _, card := range cards {
+		sources = append(sources, &source{
+			resource: truelayer.ResourceCards,
+			account:  cardToAccount(card),
+			provider: card.Provider.DisplayName,
+		})
+	}
+
+	return sources, nil
+}
+
+// selectSources returns the account or card matching the selector, or every one when the selector is "all".
+// An empty selector selects the first account, or the first card when there are no accounts.
+func selectSources(sources []*source, selector string) ([]*source, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("no accounts or cards found")
+	}
+
+	if strings.EqualFold(selector, export.AllAccounts) {
+		return sources, nil
+	}
+
+	if selector == "" {
+		return sources[:1], nil
+	}
+
+	if match, ok := lo.Find(sources, func(source *source) bool {
+		return source.account.ID == selector
+	}); ok {
+		return []*source{match}, nil
+	}
+
+	matches := lo.Filter(sources, func(source *source, _ int) bool {
+		return strings.EqualFold(source.account.Name, selector)
+	})
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no account or card matches %q", selector)
+	case 1:
+		return matches, nil
+	default:
+		ids := lo.Map(matches, func(source *source, _ int) string {
+			return source.account.ID
+		})
+
+		return nil, fmt.Errorf("%q matches %d accounts (%s), use an account ID instead", selector, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+func toAccount(account *truelayer.Account) *domain.Account {
+	result := &domain.Account{
+		ID:            account.AccountID,
+		Name:          account.DisplayName,
+		Type:          strings.ToLower(strings.TrimPrefix(account.AccountType, businessPrefix)),
+		Currency:      account.Currency,
+		SortCode:      strings.ReplaceAll(account.AccountNumber.SortCode, "-", ""),
+		AccountNumber: account.AccountNumber.Number,
+		IBAN:          account.AccountNumber.IBAN,
+		BIC:           account.AccountNumber.SwiftBIC,
+	}
+
+	if strings.HasPrefix(account.AccountType, businessPrefix) {
+		result.HolderType = "business"
+	}
+
+	return result
+}
+
+func cardToAccount(card *truelayer.Card) *domain.Account {
+	result := &domain.Account{
+		ID:        card.AccountID,
+		Name:      card.DisplayName,
+		Type:      strings.ToLower(card.CardType) + cardTypeSuffix,
+		Currency:  card.Currency,
+		CreatedAt: card.ValidFrom.Time,
+	}
+
+	if card.NameOnCard != "" {
+		result.Owners = []string{card.NameOnCard}
+	}
+
+	return result
+}
+
+func (e *TransactionExporter) toTransaction(txn *truelayer.Transaction, source *source, status domain.TransactionStatus) *domain.Transaction {
+	// Providers differ on the sign of card transactions, so it's taken from the transaction type
+	value := math.Abs(txn.Amount)
+	if txn.TransactionType == truelayer.Debit {
+		value = -value
+	}
+
+	// The merchant's name is cleaner than the provider's description, which is kept as a note
+	reference := txn.Description
+	notes := ""
+	if txn.MerchantName != "" {
+		reference = txn.MerchantName
+		notes = txn.Description
+	}
+
+	// The normalised ID is stable between requests, which the transaction ID of some providers isn't
+	id := txn.NormalisedProviderTransactionID
+	if id == "" {
+		id = txn.TransactionID
+	}
+
+	category := ""
+	if len(txn.TransactionClassification) > 0 {
+		category = txn.TransactionClassification[0]
+	}
+
+	return &domain.Transaction{
+		ID:        id,
+		Amount:    domain.MoneyFromMajorUnit(value, txn.Currency),
+		Reference: reference,
+		Category:  category,
+		CreatedAt: txn.Timestamp.Time,
+		IsDeposit: txn.TransactionType == truelayer.Credit,
+		BankName:  e.bankNameOf(source),
+		Account:   source.account.Name,
+		Notes:     notes,
+		Status:    status,
+	}
+}
+
+// bankNameOf returns the configured bank name, or else the name of the source's provider.
+func (e *TransactionExporter) bankNameOf(source *source) string {
+	if e.bankName != "" {
+		return e.bankName
+	}
+
+	if source.provider != "" {
+		return source.provider
+	}
+
+	return defaultBankName
+}
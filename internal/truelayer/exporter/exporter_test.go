@@ -0,0 +1,342 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/truelayer"
+	truelayerexporter "github.com/HallyG/fingrab/internal/truelayer/exporter"
+	"github.com/stretchr/testify/require"
+)
+
+type StubClient struct {
+	Accounts              []*truelayer.Account
+	Cards                 []*truelayer.Card
+	Transactions          map[string][]*truelayer.Transaction
+	Pending               map[string][]*truelayer.Transaction
+	FetchCardsErr         error
+	FetchTransactionsErr  error
+	RequestedTransactions []truelayer.FetchTransactionOptions
+}
+
+var _ truelayer.Client = (*StubClient)(nil)
+
+func (c *StubClient) FetchAccounts(ctx context.Context) ([]*truelayer.Account, error) {
+	return c.Accounts, nil
+}
+
+func (c *StubClient) FetchCards(ctx context.Context) ([]*truelayer.Card, error) {
+	return c.Cards, c.FetchCardsErr
+}
+
+func (c *StubClient) FetchTransactions(ctx context.Context, opts truelayer.FetchTransactionOptions) ([]*truelayer.Transaction, error) {
+	if c.FetchTransactionsErr != nil {
+		return nil, c.FetchTransactionsErr
+	}
+
+	c.RequestedTransactions = append(c.RequestedTransactions, opts)
+	return c.Transactions[opts.AccountID], nil
+}
+
+func (c *StubClient) FetchPendingTransactions(ctx context.Context, resource truelayer.Resource, accountID string) ([]*truelayer.Transaction, error) {
+	return c.Pending[accountID], nil
+}
+
+func date(day int, hour int) time.Time {
+	return time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+func newStubClient() *StubClient {
+	return &StubClient{
+		Accounts: []*truelayer.Account{
+			{
+				AccountID:   "acc-1",
+				AccountType: "TRANSACTION",
+				DisplayName: "Club Lloyds",
+				Currency:    "GBP",
+				AccountNumber: truelayer.AccountNumber{
+					IBAN:     "GB35LOYD30963512345678",
+					Number:   "12345678",
+					SortCode: "30-96-35",
+					SwiftBIC: "LOYDGB21",
+				},
+				Provider: truelayer.Provider{ID: "ob-lloyds", DisplayName: "Lloyds"},
+			},
+		},
+		Cards: []*truelayer.Card{
+			{
+				AccountID:   "card-1",
+				CardNetwork: "AMEX",
+				CardType:    "CREDIT",
+				Currency:    "GBP",
+				DisplayName: "Preferred Rewards Gold",
+				NameOnCard:  "J Smith",
+				ValidFrom:   truelayer.DateTime{Time: date(1, 0)},
+				Provider:    truelayer.Provider{ID: "ob-amex", DisplayName: "American Express"},
+			},
+		},
+		Transactions: map[string][]*truelayer.Transaction{
+			"acc-1": {
+				{
+					TransactionID:   "txn-1",
+					Timestamp:       truelayer.DateTime{Time: date(2, 9)},
+					Description:     "SALARY ACME LTD",
+					Amount:          2500,
+					Currency:        "GBP",
+					TransactionType: truelayer.Credit,
+				},
+			},
+			"card-1": {
+				{
+					TransactionID:                   "txn-2",
+					NormalisedProviderTransactionID: "norm-2",
+					Timestamp:                       truelayer.DateTime{Time: date(4, 0)},
+					Description:                     "TESCO STORES 3297 LONDON",
+					Amount:                          24.35,
+					Currency:                        "GBP",
+					TransactionType:                 truelayer.Debit,
+					TransactionClassification:       []string{"Shopping", "Groceries"},
+					MerchantName:                    "Tesco",
+				},
+				{
+					TransactionID:   "txn-3",
+					Timestamp:       truelayer.DateTime{Time: date(10, 0)},
+					Description:     "PAYMENT RECEIVED - THANK YOU",
+					Amount:          -300,
+					Currency:        "GBP",
+					TransactionType: truelayer.Credit,
+				},
+				{
+					TransactionID:   "txn-4",
+					Timestamp:       truelayer.DateTime{Time: date(31, 0)},
+					Description:     "AFTER THE END DATE",
+					Amount:          10,
+					Currency:        "GBP",
+					TransactionType: truelayer.Debit,
+				},
+			},
+		},
+		Pending: map[string][]*truelayer.Transaction{
+			"card-1": {
+				{
+					TransactionID:   "txn-5",
+					Timestamp:       truelayer.DateTime{Time: date(8, 18)},
+					Description:     "PRET A MANGER",
+					Amount:          4.6,
+					Currency:        "GBP",
+					TransactionType: truelayer.Debit,
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when client is nil", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := truelayerexporter.New(nil)
+
+		require.Nil(t, exporter)
+		require.EqualError(t, err, "truelayer client is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := truelayerexporter.New(&StubClient{})
+
+		require.NoError(t, err)
+		require.Equal(t, truelayerexporter.ExportTypeTrueLayer, exporter.Type())
+		require.Equal(t, time.Duration(0), exporter.MaxDateRange())
+		require.Equal(t, []string{"accounts", "transactions", "pending"}, exporter.Capabilities().Names())
+
+		metadata, registered := export.Lookup(exporter.Type())
+		require.True(t, registered)
+		require.Equal(t, exporter.Capabilities(), metadata.Capabilities)
+	})
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	salary := &domain.Transaction{
+		ID:        "txn-1",
+		Amount:    domain.Money{MinorUnit: 250000, Currency: "GBP"},
+		Reference: "SALARY ACME LTD",
+		CreatedAt: date(2, 9),
+		IsDeposit: true,
+		BankName:  "Lloyds",
+		Account:   "Club Lloyds",
+		Status:    domain.TransactionStatusSettled,
+	}
+	groceries := &domain.Transaction{
+		ID:        "norm-2",
+		Amount:    domain.Money{MinorUnit: -2435, Currency: "GBP"},
+		Reference: "Tesco",
+		Category:  "Shopping",
+		CreatedAt: date(4, 0),
+		BankName:  "American Express",
+		Account:   "Preferred Rewards Gold",
+		Notes:     "TESCO STORES 3297 LONDON",
+		Status:    domain.TransactionStatusSettled,
+	}
+	coffee := &domain.Transaction{
+		ID:        "txn-5",
+		Amount:    domain.Money{MinorUnit: -460, Currency: "GBP"},
+		Reference: "PRET A MANGER",
+		CreatedAt: date(8, 18),
+		BankName:  "American Express",
+		Account:   "Preferred Rewards Gold",
+		Status:    domain.TransactionStatusPending,
+	}
+	payment := &domain.Transaction{
+		ID:        "txn-3",
+		Amount:    domain.Money{MinorUnit: 30000, Currency: "GBP"},
+		Reference: "PAYMENT RECEIVED - THANK YOU",
+		CreatedAt: date(10, 0),
+		IsDeposit: true,
+		BankName:  "American Express",
+		Account:   "Preferred Rewards Gold",
+		Status:    domain.TransactionStatusSettled,
+	}
+
+	tests := map[string]struct {
+		accountID            string
+		opts                 []truelayerexporter.Option
+		fetchCardsErr        error
+		fetchTransactionsErr error
+		expectedTransactions []*domain.Transaction
+		expectedErr          string
+	}{
+		"exports first account": {
+			expectedTransactions: []*domain.Transaction{salary},
+		},
+		"exports card's settled and pending transactions by date, excluding those after the end date": {
+			accountID:            "card-1",
+			expectedTransactions: []*domain.Transaction{groceries, coffee, payment},
+		},
+		"selects card by display name": {
+			accountID:            "preferred rewards gold",
+			expectedTransactions: []*domain.Transaction{groceries, coffee, payment},
+		},
+		"exports every account and card": {
+			accountID:            export.AllAccounts,
+			expectedTransactions: []*domain.Transaction{salary, groceries, coffee, payment},
+		},
+		"names transactions with the configured bank name": {
+			opts: []truelayerexporter.Option{truelayerexporter.WithBankName("Bank")},
+			expectedTransactions: []*domain.Transaction{
+				{
+					ID:        "txn-1",
+					Amount:    domain.Money{MinorUnit: 250000, Currency: "GBP"},
+					Reference: "SALARY ACME LTD",
+					CreatedAt: date(2, 9),
+					IsDeposit: true,
+					BankName:  "Bank",
+					Account:   "Club Lloyds",
+					Status:    domain.TransactionStatusSettled,
+				},
+			},
+		},
+		"returns error when no account or card matches": {
+			accountID:   "savings",
+			expectedErr: `no account or card matches "savings"`,
+		},
+		"returns error when fetching cards fails": {
+			fetchCardsErr: errors.New("boom"),
+			expectedErr:   "fetch cards: boom",
+		},
+		"returns error when fetching transactions fails": {
+			fetchTransactionsErr: errors.New("boom"),
+			expectedErr:          "fetch transactions: boom",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newStubClient()
+			client.FetchCardsErr = test.fetchCardsErr
+			client.FetchTransactionsErr = test.fetchTransactionsErr
+
+			exporter, err := truelayerexporter.New(client, test.opts...)
+			require.NoError(t, err)
+
+			transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				AccountID: test.accountID,
+				StartDate: date(1, 0),
+				EndDate:   date(31, 0),
+				Options:   export.Options{AuthToken: "test-token"},
+			})
+
+			if test.expectedErr != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransactions, transactions)
+		})
+	}
+
+	t.Run("requests the card's transactions up to the second before the end date", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStubClient()
+
+		exporter, err := truelayerexporter.New(client)
+		require.NoError(t, err)
+
+		_, err = exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+			AccountID: "card-1",
+			StartDate: date(1, 0),
+			EndDate:   date(31, 0),
+			Options:   export.Options{AuthToken: "test-token"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []truelayer.FetchTransactionOptions{
+			{Resource: truelayer.ResourceCards, AccountID: "card-1", Start: date(1, 0), End: date(31, 0).Add(-time.Second)},
+		}, client.RequestedTransactions)
+	})
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	exporter, err := truelayerexporter.New(newStubClient())
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Account{
+		{
+			ID:            "acc-1",
+			Name:          "Club Lloyds",
+			Type:          "transaction",
+			Currency:      "GBP",
+			SortCode:      "309635",
+			AccountNumber: "12345678",
+			IBAN:          "GB35LOYD30963512345678",
+			BIC:           "LOYDGB21",
+		},
+		{
+			ID:        "card-1",
+			Name:      "Preferred Rewards Gold",
+			Type:      "credit_card",
+			Currency:  "GBP",
+			Owners:    []string{"J Smith"},
+			CreatedAt: date(1, 0),
+		},
+	}, accounts)
+}
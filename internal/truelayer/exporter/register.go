@@ -0,0 +1,130 @@
+package exporter
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/oauth"
+	"github.com/HallyG/fingrab/internal/truelayer"
+)
+
+const (
+	// TrueLayer's auth dialog lists the providers it's given, which differ between environments
+	envProvidersSuffix    = "_PROVIDERS"
+	sandboxEnvironment    = "sandbox"
+	productionEnvironment = "production"
+)
+
+type environment struct {
+	name      string
+	apiURL    string
+	authURL   string
+	providers string
+}
+
+var environments = map[string]environment{
+	sandboxEnvironment: {
+		name:      sandboxEnvironment,
+		apiURL:    truelayer.SandboxAPI,
+		authURL:   truelayer.SandboxAuth,
+		providers: "uk-cs-mock",
+	},
+	productionEnvironment: {
+		name:      productionEnvironment,
+		apiURL:    truelayer.ProductionAPI,
+		authURL:   truelayer.ProductionAuth,
+		providers: "uk-ob-all uk-oauth-all",
+	},
+}
+
+func init() {
+	description := fmt.Sprintf(`Commands for interacting with the accounts and cards connected through TrueLayer's Data API, including credit
+cards without an API of their own (e.g. Amex and Barclaycard). Cards are exported as accounts. Without a token, the
+OAuth2 flow connects them, and its refresh token is kept in the data directory so it isn't repeated. TrueLayer is
+configured with environment variables:
+
+  %s     Client ID of the TrueLayer application
+  %s Client secret of the TrueLayer application
+  %s           Environment, %s or %s (default)
+  %s     Providers offered when connecting, defaults to "%s" in the sandbox
+                          and "%s" in production
+  %s     Bank name given to exported transactions, defaults to the provider's name`,
+		export.EnvVarName(ExportTypeTrueLayer, export.EnvClientIDSuffix),
+		export.EnvVarName(ExportTypeTrueLayer, export.EnvClientSecretSuffix),
+		export.EnvVarName(ExportTypeTrueLayer, export.EnvEnvironmentSuffix),
+		sandboxEnvironment,
+		productionEnvironment,
+		export.EnvVarName(ExportTypeTrueLayer, envProvidersSuffix),
+		environments[sandboxEnvironment].providers,
+		environments[productionEnvironment].providers,
+		export.EnvVarName(ExportTypeTrueLayer, export.EnvBankNameSuffix),
+	)
+
+	export.Register(ExportTypeTrueLayer, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName("TrueLayer"),
+		export.WithDescription(description),
+		// The environment is chosen when the flow starts, so the endpoints are read then
+		export.WithOAuth(oauthConfig),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+func getEnvironment() (environment, error) {
+	name := strings.ToLower(export.Getenv(ExportTypeTrueLayer, export.EnvEnvironmentSuffix))
+	if name == "" {
+		name = productionEnvironment
+	}
+
+	env, ok := environments[name]
+	if !ok {
+		return environment{}, fmt.Errorf("unknown %s %q (options: %s, %s)", export.EnvVarName(ExportTypeTrueLayer, export.EnvEnvironmentSuffix), name, sandboxEnvironment, productionEnvironment)
+	}
+
+	return env, nil
+}
+
+// NewFromOptions returns an exporter of the Data API in the environment configured by the environment variables.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	env, err := getEnvironment()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+
+	api := truelayer.New(client, api.WithBaseURL(env.apiURL), api.WithAuthToken(opts.BearerAuthToken()))
+	return New(api, WithBankName(export.Getenv(ExportTypeTrueLayer, export.EnvBankNameSuffix)))
+}
+
+// oauthConfig returns the OAuth2 flow of the environment. An unknown environment has no endpoints, which fails
+// validation before the browser is opened.
+func oauthConfig() oauth.Config {
+	env, _ := getEnvironment()
+
+	providers := export.Getenv(ExportTypeTrueLayer, envProvidersSuffix)
+	if providers == "" {
+		providers = env.providers
+	}
+
+	config := oauth.Config{
+		Scopes:       truelayer.Scopes,
+		AuthParams:   map[string]string{"providers": providers},
+		RefreshToken: true,
+		// Tokens are only accepted by the environment that issued them
+		TokenKey: env.name,
+	}
+
+	if env.authURL != "" {
+		config.AuthURL = env.authURL
+		config.TokenURL = env.authURL + truelayer.TokenRoute
+	}
+
+	return config
+}
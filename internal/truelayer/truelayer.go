@@ -0,0 +1,157 @@
+// Package truelayer is a client for TrueLayer's Data API, which reads the accounts and cards a user has connected at
+// UK and European providers, including credit cards without an API of their own (e.g. Amex and Barclaycard).
+package truelayer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	resty "resty.dev/v3"
+)
+
+const (
+	SandboxAPI       = "https://api.truelayer-sandbox.com"
+	ProductionAPI    = "https://api.truelayer.com"
+	SandboxAuth      = "https://auth.truelayer-sandbox.com"
+	ProductionAuth   = "https://auth.truelayer.com"
+	TokenRoute       = "/connect/token"
+	getAccountsRoute = "/data/v1/accounts"
+	getCardsRoute    = "/data/v1/cards"
+	getTransactions  = "/data/v1/%s/%s/transactions"
+	getPendingRoute  = "/data/v1/%s/%s/transactions/pending"
+	dateTimeFormat   = "2006-01-02T15:04:05"
+)
+
+// Scopes are the permissions the Data API needs. offline_access issues a refresh token, so access isn't granted
+// again every hour.
+var Scopes = []string{"info", "accounts", "cards", "transactions", "offline_access"}
+
+var _ Client = (*client)(nil)
+
+type (
+	Client interface {
+		FetchAccounts(ctx context.Context) ([]*Account, error)
+		FetchCards(ctx context.Context) ([]*Card, error)
+		FetchTransactions(ctx context.Context, opts FetchTransactionOptions) ([]*Transaction, error)
+		FetchPendingTransactions(ctx context.Context, resource Resource, accountID string) ([]*Transaction, error)
+	}
+	client struct {
+		api *resty.Client
+	}
+)
+
+type FetchTransactionOptions struct {
+	Resource  Resource
+	AccountID string
+	Start     time.Time // Inclusive
+	End       time.Time // Inclusive
+}
+
+// response wraps the results of every endpoint.
+type response[T any] struct {
+	Results []*T `json:"results"`
+}
+
+// New returns a client of the Data API in production, unless a base URL option is given, e.g. the SandboxAPI.
+func New(httpClient *http.Client, opts ...api.Option) *client {
+	c := api.New(
+		ProductionAPI,
+		httpClient,
+		api.WithError[Error](),
+	)
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(c)
+	}
+
+	return &client{
+		api: c,
+	}
+}
+
+func (c *client) FetchAccounts(ctx context.Context) ([]*Account, error) {
+	return fetch[Account](ctx, c, getAccountsRoute, url.Values{})
+}
+
+// FetchCards fetches the user's cards. Providers without cards don't support the endpoint, which is no cards rather
+// than an error.
+func (c *client) FetchCards(ctx context.Context) ([]*Card, error) {
+	cards, err := fetch[Card](ctx, c, getCardsRoute, url.Values{})
+	if isNotSupported(err) {
+		return []*Card{}, nil
+	}
+
+	return cards, err
+}
+
+// FetchTransactions fetches the settled transactions of the account or card within the time range.
+func (c *client) FetchTransactions(ctx context.Context, opts FetchTransactionOptions) ([]*Transaction, error) {
+	if err := validateResource(opts.Resource, opts.AccountID); err != nil {
+		return nil, err
+	}
+
+	values := url.Values{}
+	if !opts.Start.IsZero() {
+		values.Set("from", opts.Start.UTC().Format(dateTimeFormat))
+	}
+
+	if !opts.End.IsZero() {
+		values.Set("to", opts.End.UTC().Format(dateTimeFormat))
+	}
+
+	return fetch[Transaction](ctx, c, fmt.Sprintf(getTransactions, opts.Resource, url.PathEscape(opts.AccountID)), values)
+}
+
+// FetchPendingTransactions fetches the pending transactions of the account or card, which can't be filtered by date.
+// Providers without pending transactions don't support the endpoint, which is none rather than an error.
+func (c *client) FetchPendingTransactions(ctx context.Context, resource Resource, accountID string) ([]*Transaction, error) {
+	if err := validateResource(resource, accountID); err != nil {
+		return nil, err
+	}
+
+	transactions, err := fetch[Transaction](ctx, c, fmt.Sprintf(getPendingRoute, resource, url.PathEscape(accountID)), url.Values{})
+	if isNotSupported(err) {
+		return []*Transaction{}, nil
+	}
+
+	return transactions, err
+}
+
+func fetch[T any](ctx context.Context, c *client, route string, values url.Values) ([]*T, error) {
+	result, err := api.ExecuteRequest[response[T]](ctx, c.api, http.MethodGet, route, values)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Results == nil {
+		return []*T{}, nil
+	}
+
+	return result.Results, nil
+}
+
+func isNotSupported(err error) bool {
+	var trueLayerErr *Error
+	return errors.As(err, &trueLayerErr) && trueLayerErr.Code == ErrorCodeEndpointNotSupported
+}
+
+func validateResource(resource Resource, accountID string) error {
+	if resource != ResourceAccounts && resource != ResourceCards {
+		return fmt.Errorf("unknown resource %q", resource)
+	}
+
+	if accountID == "" {
+		return errors.New("account ID is required")
+	}
+
+	return nil
+}
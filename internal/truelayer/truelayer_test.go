@@ -0,0 +1,262 @@
+package truelayer_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/testhelper"
+	"github.com/HallyG/fingrab/internal/truelayer"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token     = "Bearer mock-token"
+	accountID = "56c7b029e0f8ec5a2334fb0ffc2fface"
+	cardID    = "f1234560abf9f57287637624def390871"
+)
+
+func setup(t *testing.T, routes ...testhelper.HTTPTestRoute) truelayer.Client {
+	t.Helper()
+
+	server := testhelper.NewHTTPTestServer(t, routes)
+	client := truelayer.New(&http.Client{},
+		api.WithBaseURL(server.URL),
+		api.WithAuthToken(token),
+	)
+
+	return client
+}
+
+func TestFetchAccounts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		handler        http.HandlerFunc
+		expectedErrMsg string
+	}{
+		"success": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				header := http.Header{}
+				header.Add("Authorization", token)
+				testhelper.AssertRequest(t, r, http.MethodGet, header, url.Values{})
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "accounts.json")(w, r)
+			},
+		},
+		"returns API error": {
+			handler:        testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json"),
+			expectedErrMsg: "invalid_token: Invalid access token.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     "/data/v1/accounts",
+				Handler: test.handler,
+			})
+
+			accounts, err := client.FetchAccounts(t.Context())
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, accounts)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, accounts, 1)
+			require.Equal(t, accountID, accounts[0].AccountID)
+			require.Equal(t, "TRANSACTION", accounts[0].AccountType)
+			require.Equal(t, "30-96-35", accounts[0].AccountNumber.SortCode)
+			require.Equal(t, "Lloyds", accounts[0].Provider.DisplayName)
+		})
+	}
+}
+
+func TestFetchCards(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		handler        http.HandlerFunc
+		expectedIDs    []string
+		expectedErrMsg string
+	}{
+		"success": {
+			handler:     testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "cards.json"),
+			expectedIDs: []string{cardID},
+		},
+		"returns no cards when provider doesn't support them": {
+			handler:     testhelper.ServeJSONTestDataHandler(t, http.StatusNotImplemented, "not-supported.json"),
+			expectedIDs: []string{},
+		},
+		"returns API error": {
+			handler:        testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json"),
+			expectedErrMsg: "invalid_token: Invalid access token.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     "/data/v1/cards",
+				Handler: test.handler,
+			})
+
+			cards, err := client.FetchCards(t.Context())
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, cards)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(cards))
+			for _, card := range cards {
+				ids = append(ids, card.AccountID)
+			}
+			require.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
+
+func TestFetchTransactions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		opts           truelayer.FetchTransactionOptions
+		route          string
+		expectedErrMsg string
+	}{
+		"success for card": {
+			opts: truelayer.FetchTransactionOptions{
+				Resource:  truelayer.ResourceCards,
+				AccountID: cardID,
+				Start:     time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				End:       time.Date(2025, time.March, 31, 23, 59, 59, 0, time.UTC),
+			},
+			route: "/data/v1/cards/" + cardID + "/transactions",
+		},
+		"success for account": {
+			opts: truelayer.FetchTransactionOptions{
+				Resource:  truelayer.ResourceAccounts,
+				AccountID: accountID,
+				Start:     time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				End:       time.Date(2025, time.March, 31, 23, 59, 59, 0, time.UTC),
+			},
+			route: "/data/v1/accounts/" + accountID + "/transactions",
+		},
+		"returns error when account ID is missing": {
+			opts: truelayer.FetchTransactionOptions{
+				Resource: truelayer.ResourceCards,
+			},
+			route:          "/data/v1/cards/" + cardID + "/transactions",
+			expectedErrMsg: "account ID is required",
+		},
+		"returns error when resource is unknown": {
+			opts: truelayer.FetchTransactionOptions{
+				Resource:  "loans",
+				AccountID: accountID,
+			},
+			route:          "/data/v1/accounts/" + accountID + "/transactions",
+			expectedErrMsg: `unknown resource "loans"`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method: http.MethodGet,
+				URL:    test.route,
+				Handler: func(w http.ResponseWriter, r *http.Request) {
+					query := url.Values{}
+					query.Add("from", "2025-03-01T00:00:00")
+					query.Add("to", "2025-03-31T23:59:59")
+
+					testhelper.AssertRequest(t, r, http.MethodGet, http.Header{}, query)
+					testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "transactions.json")(w, r)
+				},
+			})
+
+			transactions, err := client.FetchTransactions(t.Context(), test.opts)
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, transactions, 2)
+			require.Equal(t, "03c333979b729315545816aaa365c33f", transactions[0].TransactionID)
+			require.Equal(t, truelayer.Debit, transactions[0].TransactionType)
+			require.InDelta(t, 24.35, transactions[0].Amount, 0.001)
+			require.Equal(t, "Tesco", transactions[0].MerchantName)
+			require.Equal(t, time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC), transactions[0].Timestamp.UTC())
+			require.Equal(t, time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC), transactions[1].Timestamp.UTC())
+		})
+	}
+}
+
+func TestFetchPendingTransactions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		handler        http.HandlerFunc
+		expectedIDs    []string
+		expectedErrMsg string
+	}{
+		"success": {
+			handler:     testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "pending.json"),
+			expectedIDs: []string{"8d1f4e0a6c2b4b7f9a3e5d6c7b8a9f01"},
+		},
+		"returns no transactions when provider doesn't support them": {
+			handler:     testhelper.ServeJSONTestDataHandler(t, http.StatusNotImplemented, "not-supported.json"),
+			expectedIDs: []string{},
+		},
+		"returns API error": {
+			handler:        testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json"),
+			expectedErrMsg: "invalid_token: Invalid access token.",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     "/data/v1/cards/" + cardID + "/transactions/pending",
+				Handler: test.handler,
+			})
+
+			transactions, err := client.FetchPendingTransactions(t.Context(), truelayer.ResourceCards, cardID)
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			ids := make([]string, 0, len(transactions))
+			for _, txn := range transactions {
+				ids = append(ids, txn.TransactionID)
+			}
+			require.Equal(t, test.expectedIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package truelayer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Resource is the kind of account a transaction belongs to, which is also the path of its endpoints.
+type Resource string
+
+const (
+	ResourceAccounts Resource = "accounts"
+	ResourceCards    Resource = "cards"
+)
+
+const (
+	Debit  = "DEBIT"
+	Credit = "CREDIT"
+)
+
+// ErrorCodeEndpointNotSupported is returned when the provider doesn't support the endpoint, e.g. cards at a bank
+// which only has accounts.
+const ErrorCodeEndpointNotSupported = "endpoint_not_supported"
+
+// DateTime is an ISO 8601 date time. Providers differ on whether they include a time zone, so times without one are UTC.
+type DateTime struct {
+	time.Time
+}
+
+var dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02"}
+
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		return nil
+	}
+
+	for _, layout := range dateTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			d.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("parse date time %q", value)
+}
+
+type Provider struct {
+	ID          string `json:"provider_id"`
+	DisplayName string `json:"display_name"`
+}
+
+type AccountNumber struct {
+	IBAN     string `json:"iban"`
+	Number   string `json:"number"`
+	SortCode string `json:"sort_code"`
+	SwiftBIC string `json:"swift_bic"`
+}
+
+type Account struct {
+	AccountID       string        `json:"account_id"`
+	AccountType     string        `json:"account_type"` // e.g. TRANSACTION, SAVINGS, BUSINESS_TRANSACTION or BUSINESS_SAVINGS
+	DisplayName     string        `json:"display_name"`
+	Currency        string        `json:"currency"`
+	AccountNumber   AccountNumber `json:"account_number"`
+	Provider        Provider      `json:"provider"`
+	UpdateTimestamp DateTime      `json:"update_timestamp"`
+}
+
+// Card is a credit or charge card, e.g. an Amex or Barclaycard, which has no account number.
+type Card struct {
+	AccountID         string   `json:"account_id"`
+	CardNetwork       string   `json:"card_network"` // e.g. VISA, MASTERCARD or AMEX
+	CardType          string   `json:"card_type"`    // e.g. CREDIT
+	Currency          string   `json:"currency"`
+	DisplayName       string   `json:"display_name"`
+	PartialCardNumber string   `json:"partial_card_number"`
+	NameOnCard        string   `json:"name_on_card"`
+	ValidFrom         DateTime `json:"valid_from"`
+	ValidTo           DateTime `json:"valid_to"`
+	Provider          Provider `json:"provider"`
+	UpdateTimestamp   DateTime `json:"update_timestamp"`
+}
+
+type Transaction struct {
+	TransactionID                   string          `json:"transaction_id"`
+	NormalisedProviderTransactionID string          `json:"normalised_provider_transaction_id"`
+	ProviderTransactionID           string          `json:"provider_transaction_id"`
+	Timestamp                       DateTime        `json:"timestamp"`
+	Description                     string          `json:"description"`
+	Amount                          float64         `json:"amount"` // Providers differ on the sign of card transactions, TransactionType is reliable
+	Currency                        string          `json:"currency"`
+	TransactionType                 string          `json:"transaction_type"`     // DEBIT or CREDIT
+	TransactionCategory             string          `json:"transaction_category"` // e.g. PURCHASE, DIRECT_DEBIT or TRANSFER
+	TransactionClassification       []string        `json:"transaction_classification"`
+	MerchantName                    string          `json:"merchant_name"`
+	RunningBalance                  *RunningBalance `json:"running_balance"`
+}
+
+type RunningBalance struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// Error is returned by the Data API, e.g. when the access token has expired or the provider is unavailable.
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (err Error) Error() string {
+	if err.Code == "" {
+		return "unknown error"
+	}
+
+	if err.Description == "" {
+		return err.Code
+	}
+
+	return err.Code + ": " + err.Description
+}
@@ -0,0 +1,323 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/log"
+	"github.com/HallyG/fingrab/internal/wise"
+	"github.com/samber/lo"
+)
+
+const (
+	ExportTypeWise   = export.ExportType("wise")
+	wiseMaxDateRange = 469 * 24 * time.Hour // Statements cover at most 469 days
+	bankName         = "Wise"
+	feesCategory     = "fees"
+)
+
+var _ export.Exporter = (*TransactionExporter)(nil)
+
+var capabilities = export.Capabilities{
+	Accounts:     true,
+	Transactions: true,
+}
+
+type TransactionExporter struct {
+	api wise.Client
+}
+
+func New(api wise.Client) (*TransactionExporter, error) {
+	if api == nil {
+		return nil, errors.New("wise client is required")
+	}
+
+	return &TransactionExporter{
+		api: api,
+	}, nil
+}
+
+func (e *TransactionExporter) Type() export.ExportType {
+	return ExportTypeWise
+}
+
+func (e *TransactionExporter) MaxDateRange() time.Duration {
+	return wiseMaxDateRange
+}
+
+func (e *TransactionExporter) Capabilities() export.Capabilities {
+	return capabilities
+}
+
+// ExportAccounts returns each currency balance and jar of every profile as an account.
+func (e *TransactionExporter) ExportAccounts(ctx context.Context, opts export.AccountOptions) ([]*domain.Account, error) {
+	balances, err := e.fetchBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return lo.Map(balances, func(balance *profileBalance, _ int) *domain.Account {
+		account := &domain.Account{
+			ID:         strconv.FormatInt(balance.ID, 10),
+			Name:       balanceName(balance.Balance),
+			Type:       strings.ToLower(balance.Type),
+			Currency:   balance.Currency,
+			HolderType: strings.ToLower(balance.profile.Type),
+			CreatedAt:  balance.CreationTime,
+		}
+
+		if balance.profile.FullName != "" {
+			account.Owners = []string{balance.profile.FullName}
+		}
+
+		return account
+	}), nil
+}
+
+// ExportTransactions returns the statement transactions of the selected balance, or of every balance when the account
+// is "all". Balances are selected by ID, currency, e.g. EUR, or jar name.
+func (e *TransactionExporter) ExportTransactions(ctx context.Context, opts export.TransactionOptions) ([]*domain.Transaction, error) {
+	if err := opts.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	if opts.Space != "" {
+		return nil, errors.New("spaces are not supported by wise")
+	}
+
+	balances, err := e.fetchBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectBalances(balances, opts.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := log.FromContext(ctx)
+
+	transactions := make([]*domain.Transaction, 0)
+	for _, balance := range selected {
+		statement, err := e.api.FetchStatement(ctx, wise.FetchStatementOptions{
+			ProfileID: balance.profile.ID,
+			BalanceID: balance.ID,
+			Currency:  balance.Currency,
+			Start:     opts.StartDate,
+			End:       opts.EndDate,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("fetch statement: %w", err)
+		}
+
+		count := 0
+		for _, txn := range statement.Transactions {
+			if txn.Date.Before(opts.StartDate) || !txn.Date.Before(opts.EndDate) {
+				continue
+			}
+
+			transactions = append(transactions, toTransaction(txn, balanceName(balance.Balance)))
+			count++
+		}
+
+		logger.InfoContext(ctx, "fetched transactions",
+			slog.String("balance.id", strconv.FormatInt(balance.ID, 10)),
+			slog.String("balance.currency", balance.Currency),
+			slog.Int("transaction.count", count),
+		)
+	}
+
+	return transactions, nil
+}
+
+// profileBalance is a balance with the profile it belongs to, which statements are requested by.
+type profileBalance struct {
+	*wise.Balance
+	profile *wise.Profile
+}
+
+func (e *TransactionExporter) fetchBalances(ctx context.Context) ([]*profileBalance, error) {
+	profiles, err := e.api.FetchProfiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch profiles: %w", err)
+	}
+
+	var balances []*profileBalance
+	for _, profile := range profiles {
+		results, err := e.api.FetchBalances(ctx, profile.ID)
+		if err != nil {
+			return nil, fmt.Errorf("fetch balances: %w", err)
+		}
+
+		for _, balance := range results {
+			balances = append(balances, &profileBalance{Balance: balance, profile: profile})
+		}
+	}
+
+	return balances, nil
+}
+
+// selectBalances returns the balance matching the selector, or every balance when the selector is "all".
+// An empty selector selects the first balance.
+func selectBalances(balances []*profileBalance, selector string) ([]*profileBalance, error) {
+	if len(balances) == 0 {
+		return nil, errors.New("no balances found")
+	}
+
+	if strings.EqualFold(selector, export.AllAccounts) {
+		return balances, nil
+	}
+
+	if selector == "" {
+		return balances[:1], nil
+	}
+
+	if balance, ok := lo.Find(balances, func(balance *profileBalance) bool {
+		return strconv.FormatInt(balance.ID, 10) == selector
+	}); ok {
+		return []*profileBalance{balance}, nil
+	}
+
+	matches := lo.Filter(balances, func(balance *profileBalance, _ int) bool {
+		if balance.Name != "" {
+			return strings.EqualFold(balance.Name, selector)
+		}
+
+		return strings.EqualFold(balance.Currency, selector)
+	})
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no balance matches %q", selector)
+	case 1:
+		return matches, nil
+	default:
+		ids := lo.Map(matches, func(balance *profileBalance, _ int) string {
+			return strconv.FormatInt(balance.ID, 10)
+		})
+
+		return nil, fmt.Errorf("%q matches %d balances (%s), use a balance ID instead", selector, len(matches), strings.Join(ids, ", "))
+	}
+}
+
+// balanceName returns the jar's name, or the currency of a standard balance.
+func balanceName(balance *wise.Balance) string {
+	if balance.Name != "" {
+		return balance.Name
+	}
+
+	return balance.Currency
+}
+
+func toTransaction(txn *wise.StatementTransaction, account string) *domain.Transaction {
+	// Amounts are signed, but make sure debits are negative
+	value := txn.Amount.Value
+	if txn.Type == wise.Debit && value > 0 {
+		value = -value
+	}
+
+	details := txn.Details
+	reference, notes := describe(details)
+
+	transaction := &domain.Transaction{
+		ID:             txn.ReferenceNumber,
+		Amount:         domain.MoneyFromMajorUnit(value, txn.Amount.Currency),
+		OriginalAmount: originalAmount(txn, value < 0),
+		Reference:      reference,
+		Category:       details.Category,
+		CreatedAt:      txn.Date,
+		IsDeposit:      value > 0,
+		BankName:       bankName,
+		Account:        account,
+		Notes:          notes,
+		Status:         domain.TransactionStatusSettled, // Statements only include completed transactions
+	}
+
+	// The amount includes fees, so split them out to keep them visible
+	if fee := math.Abs(txn.TotalFees.Value); fee > 0 {
+		feeAmount := domain.MoneyFromMajorUnit(-fee, txn.Amount.Currency)
+
+		transaction.Splits = []domain.Split{
+			{
+				Category: details.Category,
+				Amount: domain.Money{
+					MinorUnit: transaction.Amount.MinorUnit - feeAmount.MinorUnit,
+					Currency:  transaction.Amount.Currency,
+				},
+			},
+			{
+				Category: feesCategory,
+				Amount:   feeAmount,
+			},
+		}
+	}
+
+	return transaction
+}
+
+// describe returns the counterparty as the reference, keeping the payment reference or description as notes.
+func describe(details wise.TransactionDetails) (string, string) {
+	var counterparty, notes string
+
+	switch details.Type {
+	case wise.DetailCard:
+		if details.Merchant != nil {
+			counterparty = details.Merchant.Name
+		}
+
+		notes = details.Description
+	case wise.DetailDeposit, wise.DetailMoneyAdded:
+		counterparty = details.SenderName
+		notes = details.PaymentReference
+	case wise.DetailTransfer, wise.DetailDirectDebit:
+		if details.Recipient != nil {
+			counterparty = details.Recipient.Name
+		}
+
+		notes = details.PaymentReference
+	}
+
+	if counterparty == "" {
+		return details.Description, ""
+	}
+
+	return counterparty, notes
+}
+
+// originalAmount returns the amount in the currency it was made in: the merchant's currency for card transactions,
+// or the other side of a conversion. It's zero when there was no exchange.
+func originalAmount(txn *wise.StatementTransaction, debit bool) domain.Money {
+	var original *wise.Amount
+
+	details := txn.Details
+	switch {
+	case details.Type == wise.DetailCard && details.Amount != nil:
+		original = details.Amount
+	case details.SourceAmount != nil && details.TargetAmount != nil:
+		original = details.SourceAmount
+		if strings.EqualFold(details.SourceAmount.Currency, txn.Amount.Currency) {
+			original = details.TargetAmount
+		}
+	case txn.ExchangeDetails != nil && txn.ExchangeDetails.ForAmount != nil:
+		original = txn.ExchangeDetails.ForAmount
+	}
+
+	if original == nil || original.Currency == "" || strings.EqualFold(original.Currency, txn.Amount.Currency) {
+		return domain.Money{}
+	}
+
+	value := math.Abs(original.Value)
+	if debit {
+		value = -value
+	}
+
+	return domain.MoneyFromMajorUnit(value, original.Currency)
+}
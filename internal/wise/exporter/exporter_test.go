@@ -0,0 +1,351 @@
+package exporter_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/domain"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/wise"
+	wiseexporter "github.com/HallyG/fingrab/internal/wise/exporter"
+	"github.com/stretchr/testify/require"
+)
+
+type StubClient struct {
+	Profiles          []*wise.Profile
+	Balances          map[int64][]*wise.Balance
+	Statements        map[int64]*wise.Statement
+	FetchStatementErr error
+
+	RequestedStatements []wise.FetchStatementOptions
+}
+
+var _ wise.Client = (*StubClient)(nil)
+
+func (c *StubClient) FetchProfiles(ctx context.Context) ([]*wise.Profile, error) {
+	return c.Profiles, nil
+}
+
+func (c *StubClient) FetchBalances(ctx context.Context, profileID int64) ([]*wise.Balance, error) {
+	return c.Balances[profileID], nil
+}
+
+func (c *StubClient) FetchStatement(ctx context.Context, opts wise.FetchStatementOptions) (*wise.Statement, error) {
+	if c.FetchStatementErr != nil {
+		return nil, c.FetchStatementErr
+	}
+
+	c.RequestedStatements = append(c.RequestedStatements, opts)
+
+	statement, ok := c.Statements[opts.BalanceID]
+	if !ok {
+		return &wise.Statement{}, nil
+	}
+
+	return statement, nil
+}
+
+func date(day int, hour int) time.Time {
+	return time.Date(2025, time.March, day, hour, 0, 0, 0, time.UTC)
+}
+
+func newStubClient() *StubClient {
+	return &StubClient{
+		Profiles: []*wise.Profile{
+			{ID: 1, Type: wise.ProfilePersonal, FullName: "Jane Doe"},
+			{ID: 2, Type: wise.ProfileBusiness, FullName: "Doe Consulting Ltd"},
+		},
+		Balances: map[int64][]*wise.Balance{
+			1: {
+				{ID: 100, Currency: "EUR", Type: wise.BalanceStandard, CreationTime: date(1, 0)},
+				{ID: 101, Currency: "USD", Type: wise.BalanceStandard, CreationTime: date(1, 0)},
+				{ID: 102, Currency: "USD", Type: wise.BalanceSavings, Name: "Holiday", CreationTime: date(1, 0)},
+			},
+			2: {
+				{ID: 200, Currency: "EUR", Type: wise.BalanceStandard, CreationTime: date(1, 0)},
+			},
+		},
+		Statements: map[int64]*wise.Statement{
+			100: {
+				Transactions: []*wise.StatementTransaction{
+					{
+						Type:      wise.Debit,
+						Date:      date(3, 10),
+						Amount:    wise.Amount{Value: -9.46, Currency: "EUR"},
+						TotalFees: wise.Amount{Value: 0.26, Currency: "EUR"},
+						Details: wise.TransactionDetails{
+							Type:        wise.DetailCard,
+							Description: "Card transaction of 10.00 USD issued by Starbucks NEW YORK",
+							Amount:      &wise.Amount{Value: 10, Currency: "USD"},
+							Category:    "Eating Places, Restaurants",
+							Merchant:    &wise.Merchant{Name: "Starbucks"},
+						},
+						ReferenceNumber: "CARD-1",
+					},
+					{
+						Type:   wise.Credit,
+						Date:   date(4, 8),
+						Amount: wise.Amount{Value: 2500, Currency: "EUR"},
+						Details: wise.TransactionDetails{
+							Type:             wise.DetailDeposit,
+							Description:      "Received money from ACME GMBH with reference SALARY MARCH",
+							SenderName:       "ACME GMBH",
+							PaymentReference: "SALARY MARCH",
+						},
+						ReferenceNumber: "TRANSFER-2",
+					},
+					{
+						Type:      wise.Debit,
+						Date:      date(5, 14),
+						Amount:    wise.Amount{Value: -101.2, Currency: "EUR"},
+						TotalFees: wise.Amount{Value: 1.2, Currency: "EUR"},
+						Details: wise.TransactionDetails{
+							Type:         wise.DetailConversion,
+							Description:  "Converted 100.00 EUR to 108.50 USD",
+							SourceAmount: &wise.Amount{Value: 100, Currency: "EUR"},
+							TargetAmount: &wise.Amount{Value: 108.5, Currency: "USD"},
+							Rate:         1.085,
+						},
+						ReferenceNumber: "BALANCE-3",
+					},
+					{
+						Type:            wise.Debit,
+						Date:            date(31, 23),
+						Amount:          wise.Amount{Value: -1, Currency: "EUR"},
+						Details:         wise.TransactionDetails{Type: wise.DetailCard, Description: "After the end date"},
+						ReferenceNumber: "CARD-4",
+					},
+				},
+			},
+			101: {
+				Transactions: []*wise.StatementTransaction{
+					{
+						Type:   wise.Credit,
+						Date:   date(5, 14),
+						Amount: wise.Amount{Value: 108.5, Currency: "USD"},
+						Details: wise.TransactionDetails{
+							Type:         wise.DetailConversion,
+							Description:  "Converted 100.00 EUR to 108.50 USD",
+							SourceAmount: &wise.Amount{Value: 100, Currency: "EUR"},
+							TargetAmount: &wise.Amount{Value: 108.5, Currency: "USD"},
+						},
+						ReferenceNumber: "BALANCE-3",
+					},
+				},
+			},
+			102: {
+				Transactions: []*wise.StatementTransaction{
+					{
+						Type:   wise.Debit,
+						Date:   date(6, 9),
+						Amount: wise.Amount{Value: -50, Currency: "USD"},
+						Details: wise.TransactionDetails{
+							Type:             wise.DetailTransfer,
+							Description:      "Sent money to John Smith",
+							Recipient:        &wise.Recipient{Name: "John Smith"},
+							PaymentReference: "Rent share",
+						},
+						ReferenceNumber: "TRANSFER-5",
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns error when client is nil", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := wiseexporter.New(nil)
+
+		require.Nil(t, exporter)
+		require.EqualError(t, err, "wise client is required")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		exporter, err := wiseexporter.New(&StubClient{})
+
+		require.NoError(t, err)
+		require.Equal(t, wiseexporter.ExportTypeWise, exporter.Type())
+		require.Equal(t, 469*24*time.Hour, exporter.MaxDateRange())
+	})
+}
+
+func TestExportTransactions(t *testing.T) {
+	t.Parallel()
+
+	card := &domain.Transaction{
+		ID:             "CARD-1",
+		Amount:         domain.Money{MinorUnit: -946, Currency: "EUR"},
+		OriginalAmount: domain.Money{MinorUnit: -1000, Currency: "USD"},
+		Reference:      "Starbucks",
+		Category:       "Eating Places, Restaurants",
+		CreatedAt:      date(3, 10),
+		BankName:       "Wise",
+		Account:        "EUR",
+		Notes:          "Card transaction of 10.00 USD issued by Starbucks NEW YORK",
+		Splits: []domain.Split{
+			{Category: "Eating Places, Restaurants", Amount: domain.Money{MinorUnit: -920, Currency: "EUR"}},
+			{Category: "fees", Amount: domain.Money{MinorUnit: -26, Currency: "EUR"}},
+		},
+		Status: domain.TransactionStatusSettled,
+	}
+	salary := &domain.Transaction{
+		ID:        "TRANSFER-2",
+		Amount:    domain.Money{MinorUnit: 250000, Currency: "EUR"},
+		Reference: "ACME GMBH",
+		CreatedAt: date(4, 8),
+		IsDeposit: true,
+		BankName:  "Wise",
+		Account:   "EUR",
+		Notes:     "SALARY MARCH",
+		Status:    domain.TransactionStatusSettled,
+	}
+	conversionOut := &domain.Transaction{
+		ID:             "BALANCE-3",
+		Amount:         domain.Money{MinorUnit: -10120, Currency: "EUR"},
+		OriginalAmount: domain.Money{MinorUnit: -10850, Currency: "USD"},
+		Reference:      "Converted 100.00 EUR to 108.50 USD",
+		CreatedAt:      date(5, 14),
+		BankName:       "Wise",
+		Account:        "EUR",
+		Splits: []domain.Split{
+			{Amount: domain.Money{MinorUnit: -10000, Currency: "EUR"}},
+			{Category: "fees", Amount: domain.Money{MinorUnit: -120, Currency: "EUR"}},
+		},
+		Status: domain.TransactionStatusSettled,
+	}
+	conversionIn := &domain.Transaction{
+		ID:             "BALANCE-3",
+		Amount:         domain.Money{MinorUnit: 10850, Currency: "USD"},
+		OriginalAmount: domain.Money{MinorUnit: 10000, Currency: "EUR"},
+		Reference:      "Converted 100.00 EUR to 108.50 USD",
+		CreatedAt:      date(5, 14),
+		IsDeposit:      true,
+		BankName:       "Wise",
+		Account:        "USD",
+		Status:         domain.TransactionStatusSettled,
+	}
+	transfer := &domain.Transaction{
+		ID:        "TRANSFER-5",
+		Amount:    domain.Money{MinorUnit: -5000, Currency: "USD"},
+		Reference: "John Smith",
+		CreatedAt: date(6, 9),
+		BankName:  "Wise",
+		Account:   "Holiday",
+		Notes:     "Rent share",
+		Status:    domain.TransactionStatusSettled,
+	}
+
+	tests := map[string]struct {
+		accountID            string
+		fetchStatementErr    error
+		expectedTransactions []*domain.Transaction
+		expectedErr          string
+	}{
+		"exports first balance, excluding transactions after the end date": {
+			expectedTransactions: []*domain.Transaction{card, salary, conversionOut},
+		},
+		"selects balance by currency": {
+			accountID:            "usd",
+			expectedTransactions: []*domain.Transaction{conversionIn},
+		},
+		"selects jar by name": {
+			accountID:            "holiday",
+			expectedTransactions: []*domain.Transaction{transfer},
+		},
+		"selects balance by ID": {
+			accountID:            "200",
+			expectedTransactions: []*domain.Transaction{},
+		},
+		"exports every balance": {
+			accountID:            export.AllAccounts,
+			expectedTransactions: []*domain.Transaction{card, salary, conversionOut, conversionIn, transfer},
+		},
+		"returns error when selector is ambiguous": {
+			accountID:   "eur",
+			expectedErr: `"eur" matches 2 balances (100, 200), use a balance ID instead`,
+		},
+		"returns error when no balance matches": {
+			accountID:   "gbp",
+			expectedErr: `no balance matches "gbp"`,
+		},
+		"returns error when fetching statement fails": {
+			fetchStatementErr: errors.New("boom"),
+			expectedErr:       "fetch statement: boom",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newStubClient()
+			client.FetchStatementErr = test.fetchStatementErr
+
+			exporter, err := wiseexporter.New(client)
+			require.NoError(t, err)
+
+			transactions, err := exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+				AccountID: test.accountID,
+				StartDate: date(1, 0),
+				EndDate:   date(31, 0),
+				Options:   export.Options{AuthToken: "test-token"},
+			})
+
+			if test.expectedErr != "" {
+				require.Nil(t, transactions)
+				require.EqualError(t, err, test.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedTransactions, transactions)
+		})
+	}
+
+	t.Run("requests the balance's statement", func(t *testing.T) {
+		t.Parallel()
+
+		client := newStubClient()
+
+		exporter, err := wiseexporter.New(client)
+		require.NoError(t, err)
+
+		_, err = exporter.ExportTransactions(t.Context(), export.TransactionOptions{
+			AccountID: "Holiday",
+			StartDate: date(1, 0),
+			EndDate:   date(31, 0),
+			Options:   export.Options{AuthToken: "test-token"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []wise.FetchStatementOptions{
+			{ProfileID: 1, BalanceID: 102, Currency: "USD", Start: date(1, 0), End: date(31, 0)},
+		}, client.RequestedStatements)
+	})
+}
+
+func TestExportAccounts(t *testing.T) {
+	t.Parallel()
+
+	exporter, err := wiseexporter.New(newStubClient())
+	require.NoError(t, err)
+
+	accounts, err := exporter.ExportAccounts(t.Context(), export.AccountOptions{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*domain.Account{
+		{ID: "100", Name: "EUR", Type: "standard", Currency: "EUR", HolderType: "personal", Owners: []string{"Jane Doe"}, CreatedAt: date(1, 0)},
+		{ID: "101", Name: "USD", Type: "standard", Currency: "USD", HolderType: "personal", Owners: []string{"Jane Doe"}, CreatedAt: date(1, 0)},
+		{ID: "102", Name: "Holiday", Type: "savings", Currency: "USD", HolderType: "personal", Owners: []string{"Jane Doe"}, CreatedAt: date(1, 0)},
+		{ID: "200", Name: "EUR", Type: "standard", Currency: "EUR", HolderType: "business", Owners: []string{"Doe Consulting Ltd"}, CreatedAt: date(1, 0)},
+	}, accounts)
+}
@@ -0,0 +1,71 @@
+package exporter
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/export"
+	"github.com/HallyG/fingrab/internal/wise"
+)
+
+const (
+	// Wise's personal tokens of UK and EEA customers need a signing key to fetch statements
+	envPrivateKeyFileSuffix = "_PRIVATE_KEY_FILE"
+	envBaseURLSuffix        = "_BASE_URL"
+)
+
+func init() {
+	description := fmt.Sprintf(`Commands for interacting with Wise multi-currency accounts, using a personal API token.
+Each currency balance and jar is exported as an account. Wise is configured with environment variables:
+
+  %s            Personal API token
+  %s Private key (PEM) whose public key is added to the token's settings, to answer
+                        strong customer authentication challenges when fetching statements
+  %s         API base URL, e.g. https://api.sandbox.transferwise.tech for the sandbox`,
+		export.EnvVarName(ExportTypeWise, export.EnvTokenSuffix),
+		export.EnvVarName(ExportTypeWise, envPrivateKeyFileSuffix),
+		export.EnvVarName(ExportTypeWise, envBaseURLSuffix),
+	)
+
+	export.Register(ExportTypeWise, func(opts export.Options) (export.Exporter, error) {
+		return NewFromOptions(opts)
+	},
+		export.WithDisplayName("Wise"),
+		export.WithDescription(description),
+		// Wise only issues personal tokens to individuals, there's no OAuth flow to fall back on
+		export.WithTokenHelp("create a personal API token in Wise's settings"),
+		export.WithCapabilities(capabilities),
+	)
+}
+
+// NewFromOptions returns an exporter of the Wise API, signing statement requests with the private key from the
+// environment when there is one.
+func NewFromOptions(opts export.Options) (*TransactionExporter, error) {
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+
+	var signingKey *rsa.PrivateKey
+	if path := export.Getenv(ExportTypeWise, envPrivateKeyFileSuffix); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read private key: %w", err)
+		}
+
+		signingKey, err = wise.ParsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var baseURL api.Option
+	if url := export.Getenv(ExportTypeWise, envBaseURLSuffix); url != "" {
+		baseURL = api.WithBaseURL(url)
+	}
+
+	api := wise.New(client, signingKey, api.WithAuthToken(opts.BearerAuthToken()), baseURL)
+	return New(api)
+}
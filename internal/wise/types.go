@@ -0,0 +1,139 @@
+package wise
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ProfilePersonal = "PERSONAL"
+	ProfileBusiness = "BUSINESS"
+
+	BalanceStandard = "STANDARD"
+	BalanceSavings  = "SAVINGS" // A jar
+
+	Debit  = "DEBIT"
+	Credit = "CREDIT"
+)
+
+// Detail types of statement transactions.
+const (
+	DetailCard        = "CARD"
+	DetailConversion  = "CONVERSION"
+	DetailDeposit     = "DEPOSIT"
+	DetailTransfer    = "TRANSFER"
+	DetailMoneyAdded  = "MONEY_ADDED"
+	DetailDirectDebit = "DIRECT_DEBIT"
+)
+
+type Profile struct {
+	ID       int64  `json:"id"`
+	Type     string `json:"type"` // PERSONAL or BUSINESS
+	FullName string `json:"fullName"`
+}
+
+type Amount struct {
+	Value    float64 `json:"value"`
+	Currency string  `json:"currency"`
+}
+
+// Balance holds a single currency of a multi-currency account.
+type Balance struct {
+	ID             int64     `json:"id"`
+	Currency       string    `json:"currency"`
+	Type           string    `json:"type"` // STANDARD or SAVINGS
+	Name           string    `json:"name"` // Only jars are named
+	Amount         Amount    `json:"amount"`
+	ReservedAmount Amount    `json:"reservedAmount"`
+	CreationTime   time.Time `json:"creationTime"`
+	Visible        bool      `json:"visible"`
+}
+
+type Statement struct {
+	Transactions            []*StatementTransaction `json:"transactions"`
+	StartOfStatementBalance Amount                  `json:"startOfStatementBalance"`
+	EndOfStatementBalance   Amount                  `json:"endOfStatementBalance"`
+}
+
+type StatementTransaction struct {
+	Type            string             `json:"type"` // DEBIT or CREDIT
+	Date            time.Time          `json:"date"`
+	Amount          Amount             `json:"amount"`    // Signed, including fees
+	TotalFees       Amount             `json:"totalFees"` // Unsigned
+	Details         TransactionDetails `json:"details"`
+	ExchangeDetails *ExchangeDetails   `json:"exchangeDetails"`
+	RunningBalance  Amount             `json:"runningBalance"`
+	ReferenceNumber string             `json:"referenceNumber"` // e.g. CARD-1234 or TRANSFER-5678
+}
+
+type TransactionDetails struct {
+	Type             string     `json:"type"` // e.g. CARD, CONVERSION, DEPOSIT or TRANSFER
+	Description      string     `json:"description"`
+	Amount           *Amount    `json:"amount"` // The amount in the merchant's currency, for card transactions
+	Category         string     `json:"category"`
+	Merchant         *Merchant  `json:"merchant"`
+	SenderName       string     `json:"senderName"`
+	SenderAccount    string     `json:"senderAccount"`
+	PaymentReference string     `json:"paymentReference"`
+	Recipient        *Recipient `json:"recipient"`
+	SourceAmount     *Amount    `json:"sourceAmount"` // Conversions only
+	TargetAmount     *Amount    `json:"targetAmount"` // Conversions only
+	Rate             float64    `json:"rate"`
+}
+
+type Merchant struct {
+	Name     string `json:"name"`
+	City     string `json:"city"`
+	Country  string `json:"country"`
+	Category string `json:"category"`
+}
+
+type Recipient struct {
+	Name        string `json:"name"`
+	BankAccount string `json:"bankAccount"`
+}
+
+// ExchangeDetails describe a currency exchange. Card transactions only have ForAmount, the amount in the
+// merchant's currency, whereas conversions have the amounts either side.
+type ExchangeDetails struct {
+	ForAmount  *Amount `json:"forAmount"`
+	FromAmount *Amount `json:"fromAmount"`
+	ToAmount   *Amount `json:"toAmount"`
+	Rate       float64 `json:"rate"`
+}
+
+// Error is returned either as a list of errors, or as an OAuth error for auth failures.
+type Error struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (err Error) Error() string {
+	messages := make([]string, 0, len(err.Errors)+1)
+	for _, e := range err.Errors {
+		if e.Message != "" {
+			messages = append(messages, e.Message)
+		} else {
+			messages = append(messages, e.Code)
+		}
+	}
+
+	if err.Code != "" {
+		message := err.Code
+		if err.Description != "" {
+			message += ": " + err.Description
+		}
+
+		messages = append(messages, message)
+	}
+
+	if len(messages) == 0 {
+		return "unknown error"
+	}
+
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,210 @@
+// Package wise is a client for the Wise (formerly TransferWise) API, authenticated with a personal API token.
+package wise
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	resty "resty.dev/v3"
+)
+
+const (
+	prodAPI              = "https://api.wise.com"
+	getProfilesRoute     = "/v2/profiles"
+	getBalancesRoute     = "/v4/profiles/%d/balances"
+	getStatementRoute    = "/v1/profiles/%d/balance-statements/%d/statement.json"
+	approvalHeader       = "x-2fa-approval"
+	approvalResultHeader = "x-2fa-approval-result"
+	signatureHeader      = "X-Signature"
+	dateTimeFormat       = "2006-01-02T15:04:05.000Z"
+)
+
+var _ Client = (*client)(nil)
+
+// ErrSCARequired is returned when Wise requires strong customer authentication, which personal tokens of UK and
+// EEA customers need to fetch statements, but the client has no signing key.
+var ErrSCARequired = errors.New("strong customer authentication required, add a public key to the API token's settings and configure its private key")
+
+type (
+	Client interface {
+		FetchProfiles(ctx context.Context) ([]*Profile, error)
+		FetchBalances(ctx context.Context, profileID int64) ([]*Balance, error)
+		FetchStatement(ctx context.Context, opts FetchStatementOptions) (*Statement, error)
+	}
+	client struct {
+		api        *resty.Client
+		signingKey *rsa.PrivateKey
+	}
+)
+
+type FetchStatementOptions struct {
+	ProfileID int64
+	BalanceID int64
+	Currency  string
+	Start     time.Time
+	End       time.Time
+}
+
+// New returns a client of the Wise API. The signing key signs strong customer authentication challenges, and may
+// be nil when they aren't needed.
+func New(httpClient *http.Client, signingKey *rsa.PrivateKey, opts ...api.Option) *client {
+	c := api.New(
+		prodAPI,
+		httpClient,
+		api.WithError[Error](),
+	)
+
+	for _, opt := range opts {
+		if opt == nil {
+			continue
+		}
+
+		opt(c)
+	}
+
+	return &client{
+		api:        c,
+		signingKey: signingKey,
+	}
+}
+
+// ParsePrivateKey parses a PEM encoded RSA private key, in either PKCS #1 or PKCS #8 form.
+func ParsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not an RSA key")
+	}
+
+	return key, nil
+}
+
+func (c *client) FetchProfiles(ctx context.Context) ([]*Profile, error) {
+	result, err := api.ExecuteRequest[[]*Profile](ctx, c.api,
+		http.MethodGet,
+		getProfilesRoute,
+		url.Values{},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return *result, nil
+}
+
+func (c *client) FetchBalances(ctx context.Context, profileID int64) ([]*Balance, error) {
+	values := url.Values{}
+	values.Set("types", BalanceStandard+","+BalanceSavings)
+
+	result, err := api.ExecuteRequest[[]*Balance](ctx, c.api,
+		http.MethodGet,
+		fmt.Sprintf(getBalancesRoute, profileID),
+		values,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return *result, nil
+}
+
+// FetchStatement fetches the balance's statement between the start and end, answering a strong customer
+// authentication challenge if Wise sends one.
+func (c *client) FetchStatement(ctx context.Context, opts FetchStatementOptions) (*Statement, error) {
+	if opts.Currency == "" {
+		return nil, errors.New("currency is required")
+	}
+
+	route := fmt.Sprintf(getStatementRoute, opts.ProfileID, opts.BalanceID)
+
+	values := url.Values{}
+	values.Set("currency", opts.Currency)
+	values.Set("intervalStart", opts.Start.UTC().Format(dateTimeFormat))
+	values.Set("intervalEnd", opts.End.UTC().Format(dateTimeFormat))
+	values.Set("type", "COMPACT")
+
+	var result Statement
+
+	resp, err := c.api.R().
+		SetContext(ctx).
+		SetResult(&result).
+		SetQueryParamsFromValues(values).
+		Get(route)
+	if err != nil {
+		return nil, fmt.Errorf("execute %s %s: %w", http.MethodGet, route, err)
+	}
+
+	// The challenge is a one time token, which is signed and sent back with the same request
+	if oneTimeToken := resp.Header().Get(approvalHeader); resp.StatusCode() == http.StatusForbidden && oneTimeToken != "" {
+		if c.signingKey == nil {
+			return nil, ErrSCARequired
+		}
+
+		signature, err := sign(c.signingKey, oneTimeToken)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err = c.api.R().
+			SetContext(ctx).
+			SetResult(&result).
+			SetQueryParamsFromValues(values).
+			SetHeader(approvalHeader, oneTimeToken).
+			SetHeader(signatureHeader, signature).
+			Get(route)
+		if err != nil {
+			return nil, fmt.Errorf("execute %s %s: %w", http.MethodGet, route, err)
+		}
+
+		if resp.StatusCode() == http.StatusForbidden && resp.Header().Get(approvalResultHeader) == "REJECTED" {
+			return nil, errors.New("strong customer authentication rejected, check the private key matches the API token's public key")
+		}
+	}
+
+	if resp.IsError() {
+		if err, ok := resp.Error().(error); ok {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode(), resp.String())
+	}
+
+	return &result, nil
+}
+
+// sign returns the base64 encoded SHA256 with RSA signature of the one time token.
+func sign(key *rsa.PrivateKey, oneTimeToken string) (string, error) {
+	digest := sha256.Sum256([]byte(oneTimeToken))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("sign one time token: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,271 @@
+package wise_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/HallyG/fingrab/internal/api"
+	"github.com/HallyG/fingrab/internal/testhelper"
+	"github.com/HallyG/fingrab/internal/wise"
+	"github.com/stretchr/testify/require"
+)
+
+const token = "Bearer mock-token"
+
+func setup(t *testing.T, signingKey *rsa.PrivateKey, routes ...testhelper.HTTPTestRoute) wise.Client {
+	t.Helper()
+
+	server := testhelper.NewHTTPTestServer(t, routes)
+	client := wise.New(&http.Client{}, signingKey,
+		api.WithBaseURL(server.URL),
+		api.WithAuthToken(token),
+	)
+
+	return client
+}
+
+func TestFetchProfiles(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		handler          http.HandlerFunc
+		expectedProfiles []*wise.Profile
+		expectedErrMsg   string
+	}{
+		"success": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				header := http.Header{}
+				header.Add("Authorization", token)
+				testhelper.AssertRequest(t, r, http.MethodGet, header, url.Values{})
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "profiles.json")(w, r)
+			},
+			expectedProfiles: []*wise.Profile{
+				{ID: 16614410, Type: wise.ProfilePersonal, FullName: "Jane Doe"},
+				{ID: 16614411, Type: wise.ProfileBusiness, FullName: "Doe Consulting Ltd"},
+			},
+		},
+		"returns API error": {
+			handler:        testhelper.ServeJSONTestDataHandler(t, http.StatusUnauthorized, "error.json"),
+			expectedErrMsg: "invalid_token: Invalid token",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, nil, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     "/v2/profiles",
+				Handler: test.handler,
+			})
+
+			profiles, err := client.FetchProfiles(t.Context())
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, profiles)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expectedProfiles, profiles)
+		})
+	}
+}
+
+func TestFetchBalances(t *testing.T) {
+	t.Parallel()
+
+	client := setup(t, nil, testhelper.HTTPTestRoute{
+		Method: http.MethodGet,
+		URL:    "/v4/profiles/16614410/balances",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			query := url.Values{}
+			query.Add("types", "STANDARD,SAVINGS")
+
+			testhelper.AssertRequest(t, r, http.MethodGet, http.Header{}, query)
+			testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "balances.json")(w, r)
+		},
+	})
+
+	balances, err := client.FetchBalances(t.Context(), 16614410)
+
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	require.Equal(t, &wise.Balance{
+		ID:             200002,
+		Currency:       "USD",
+		Type:           wise.BalanceSavings,
+		Name:           "Holiday",
+		Amount:         wise.Amount{Value: 300, Currency: "USD"},
+		ReservedAmount: wise.Amount{Currency: "USD"},
+		CreationTime:   time.Date(2024, time.June, 15, 12, 30, 0, 0, time.UTC),
+		Visible:        true,
+	}, balances[1])
+}
+
+func TestFetchStatement(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	const oneTimeToken = "5932d5b5-ec13-452f-8688-308feade7834"
+
+	// challenge responds as Wise does to personal tokens of UK and EEA customers, requiring the one time token to be signed
+	challenge := func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-2fa-approval") == "" {
+			w.Header().Set("x-2fa-approval", oneTimeToken)
+			w.Header().Set("x-2fa-approval-result", "REJECTED")
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		require.Equal(t, oneTimeToken, r.Header.Get("x-2fa-approval"))
+
+		signature, err := base64.StdEncoding.DecodeString(r.Header.Get("X-Signature"))
+		require.NoError(t, err)
+
+		digest := sha256.Sum256([]byte(oneTimeToken))
+		if rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature) != nil {
+			w.Header().Set("x-2fa-approval-result", "REJECTED")
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "statement.json")(w, r)
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		signingKey     *rsa.PrivateKey
+		handler        http.HandlerFunc
+		expectedErrMsg string
+	}{
+		"success": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				query := url.Values{}
+				query.Add("currency", "EUR")
+				query.Add("intervalStart", "2025-03-01T00:00:00.000Z")
+				query.Add("intervalEnd", "2025-04-01T00:00:00.000Z")
+				query.Add("type", "COMPACT")
+
+				header := http.Header{}
+				header.Add("Authorization", token)
+
+				testhelper.AssertRequest(t, r, http.MethodGet, header, query)
+				testhelper.ServeJSONTestDataHandler(t, http.StatusOK, "statement.json")(w, r)
+			},
+		},
+		"signs strong customer authentication challenge": {
+			signingKey: key,
+			handler:    challenge,
+		},
+		"returns error when challenged without a signing key": {
+			handler:        challenge,
+			expectedErrMsg: wise.ErrSCARequired.Error(),
+		},
+		"returns error when signature is rejected": {
+			signingKey:     otherKey,
+			handler:        challenge,
+			expectedErrMsg: "strong customer authentication rejected, check the private key matches the API token's public key",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client := setup(t, test.signingKey, testhelper.HTTPTestRoute{
+				Method:  http.MethodGet,
+				URL:     "/v1/profiles/16614410/balance-statements/200001/statement.json",
+				Handler: test.handler,
+			})
+
+			statement, err := client.FetchStatement(t.Context(), wise.FetchStatementOptions{
+				ProfileID: 16614410,
+				BalanceID: 200001,
+				Currency:  "EUR",
+				Start:     time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+				End:       time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC),
+			})
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, statement)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, statement.Transactions, 4)
+			require.Equal(t, wise.Amount{Value: 3589.34, Currency: "EUR"}, statement.EndOfStatementBalance)
+
+			card := statement.Transactions[0]
+			require.Equal(t, wise.DetailCard, card.Details.Type)
+			require.Equal(t, &wise.Amount{Value: 10, Currency: "USD"}, card.Details.Amount)
+			require.Equal(t, "Starbucks", card.Details.Merchant.Name)
+			require.Equal(t, wise.Amount{Value: 0.26, Currency: "EUR"}, card.TotalFees)
+
+			conversion := statement.Transactions[2]
+			require.Equal(t, &wise.Amount{Value: 108.5, Currency: "USD"}, conversion.Details.TargetAmount)
+			require.InDelta(t, 1.085, conversion.Details.Rate, 0.0001)
+		})
+	}
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		data           []byte
+		expectedErrMsg string
+	}{
+		"parses PKCS #1 key": {
+			data: pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+		"parses PKCS #8 key": {
+			data: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		},
+		"returns error without PEM data": {
+			data:           []byte("not a key"),
+			expectedErrMsg: "no PEM data found",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			parsed, err := wise.ParsePrivateKey(test.data)
+
+			if test.expectedErrMsg != "" {
+				require.Nil(t, parsed)
+				require.EqualError(t, err, test.expectedErrMsg)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.True(t, key.Equal(parsed))
+		})
+	}
+}